@@ -0,0 +1,240 @@
+// migrate-storage는 files.storage_path에 기록된 업로드 파일을 한 저장소에서 다른 저장소로 옮깁니다.
+//
+// 객체 키(storage_path)는 바뀌지 않으므로 복사와 검증이 끝난 뒤 서버의 FILE_STORAGE를 새 저장소로 바꾸면 됩니다.
+// 각 파일은 원본을 읽으며 SHA-256을 계산해 files.checksum과 비교하고, 대상에 쓴 뒤 다시 읽어 같은 체크섬인지 확인합니다.
+// 대상에 이미 같은 체크섬의 객체가 있으면 건너뛰므로 중간에 실패해도 다시 실행할 수 있습니다.
+//
+// 사용 예:
+//
+//	go run ./cmd/migrate-storage -from filesystem -to s3 -dry-run
+//	go run ./cmd/migrate-storage -from filesystem -to s3
+//	go run ./cmd/migrate-storage -from s3 -to filesystem -to-dir /srv/studiolicense/files
+//
+// 데이터베이스는 MYSQL_DSN, 저장소는 서버와 같은 환경 변수(FILE_STORAGE_DIR, S3_*)를 사용하며 플래그로 한쪽만 바꿀 수 있습니다.
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/services"
+)
+
+const defaultMySQLDSN = "root:root@tcp(localhost:3306)/studiolicense?parseTime=true&loc=Asia%2FSeoul"
+
+// migrationFile 옮길 파일 메타데이터
+type migrationFile struct {
+	ID          string
+	StoragePath string
+	MimeType    string
+	FileSize    int64
+	Checksum    string
+}
+
+func main() {
+	var (
+		from         = flag.String("from", "filesystem", "원본 저장소 (filesystem, s3)")
+		to           = flag.String("to", "s3", "대상 저장소 (filesystem, s3)")
+		fromDir      = flag.String("from-dir", "", "원본 filesystem 경로 (기본값 FILE_STORAGE_DIR 또는 data/files)")
+		toDir        = flag.String("to-dir", "", "대상 filesystem 경로 (기본값 FILE_STORAGE_DIR 또는 data/files)")
+		fromBucket   = flag.String("from-bucket", "", "원본 S3 버킷 (기본값 S3_BUCKET)")
+		toBucket     = flag.String("to-bucket", "", "대상 S3 버킷 (기본값 S3_BUCKET)")
+		fromPrefix   = flag.String("from-prefix", "", "원본 S3 키 접두사 (기본값 S3_PREFIX)")
+		toPrefix     = flag.String("to-prefix", "", "대상 S3 키 접두사 (기본값 S3_PREFIX)")
+		dsn          = flag.String("dsn", "", "MySQL DSN (기본값 MYSQL_DSN)")
+		dryRun       = flag.Bool("dry-run", false, "복사하지 않고 원본 객체 존재 여부와 크기만 확인")
+		deleteSource = flag.Bool("delete-source", false, "검증이 끝난 원본 객체 삭제")
+	)
+	flag.Parse()
+
+	if err := logger.Initialize(logger.Config{Level: logger.INFO, UseColor: true}); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	sourceCfg := storageConfig(*from, *fromDir, *fromBucket, *fromPrefix)
+	targetCfg := storageConfig(*to, *toDir, *toBucket, *toPrefix)
+	if storageLocation(sourceCfg) == storageLocation(targetCfg) {
+		logger.Fatal("Source and target are the same storage: %s", storageLocation(sourceCfg))
+	}
+	source, err := services.NewFileStorage(sourceCfg)
+	if err != nil {
+		logger.Fatal("Invalid source storage: %v", err)
+	}
+	target, err := services.NewFileStorage(targetCfg)
+	if err != nil {
+		logger.Fatal("Invalid target storage: %v", err)
+	}
+
+	if *dsn == "" {
+		*dsn = os.Getenv("MYSQL_DSN")
+	}
+	if *dsn == "" {
+		*dsn = defaultMySQLDSN
+	}
+	if err := database.Initialize(*dsn); err != nil {
+		logger.Fatal("Failed to initialize database: %v", err)
+	}
+	defer database.Close()
+
+	files, err := loadMigrationFiles()
+	if err != nil {
+		logger.Fatal("Failed to load files: %v", err)
+	}
+	logger.Info("Migrating %d files: %s -> %s (dry-run=%t, delete-source=%t)", len(files), source.Name(), target.Name(), *dryRun, *deleteSource)
+
+	ctx := context.Background()
+	var migrated, skipped, failed int
+	for _, file := range files {
+		if *dryRun {
+			info, err := source.Stat(ctx, file.StoragePath)
+			if err != nil {
+				failed++
+				logger.Error("[%s] %s: %v", file.ID, file.StoragePath, err)
+				continue
+			}
+			if info.Size != file.FileSize {
+				logger.Warn("[%s] %s: size %d differs from recorded %d", file.ID, file.StoragePath, info.Size, file.FileSize)
+			}
+			logger.Info("[%s] %s: %d bytes", file.ID, file.StoragePath, info.Size)
+			continue
+		}
+
+		copied, err := migrateFile(ctx, source, target, file)
+		if err != nil {
+			failed++
+			logger.Error("[%s] %s: %v", file.ID, file.StoragePath, err)
+			continue
+		}
+		if copied {
+			migrated++
+		} else {
+			skipped++
+		}
+
+		if *deleteSource {
+			if err := source.Delete(ctx, file.StoragePath); err != nil {
+				logger.Warn("[%s] %s: failed to delete source: %v", file.ID, file.StoragePath, err)
+			}
+		}
+	}
+
+	logger.Info("Done: %d migrated, %d already present, %d failed", migrated, skipped, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// storageConfig 서버와 같은 환경 변수 설정에 플래그로 받은 값만 덮어씁니다.
+func storageConfig(backend, dir, bucket, prefix string) services.FileStorageConfig {
+	cfg := services.FileStorageConfigFromEnv()
+	cfg.Backend = strings.ToLower(strings.TrimSpace(backend))
+	if dir != "" {
+		cfg.BaseDir = dir
+	}
+	if bucket != "" {
+		cfg.S3.Bucket = bucket
+	}
+	if prefix != "" {
+		cfg.S3.Prefix = prefix
+	}
+	return cfg
+}
+
+// storageLocation 원본과 대상이 같은 곳을 가리키는지 비교하기 위한 위치 문자열
+func storageLocation(cfg services.FileStorageConfig) string {
+	if cfg.Backend == "s3" || cfg.Backend == "minio" {
+		return "s3://" + strings.TrimSpace(cfg.S3.Endpoint) + "/" + strings.TrimSpace(cfg.S3.Bucket) + "/" + strings.Trim(cfg.S3.Prefix, "/ ")
+	}
+	dir := cfg.BaseDir
+	if dir == "" {
+		dir = services.DefaultFileStorageDir
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return "file://" + filepath.Clean(dir)
+}
+
+func loadMigrationFiles() ([]migrationFile, error) {
+	rows, err := database.DB.Query(`SELECT id, storage_path, mime_type, file_size, checksum FROM files ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := []migrationFile{}
+	for rows.Next() {
+		var (
+			file     migrationFile
+			checksum sql.NullString
+		)
+		if err := rows.Scan(&file.ID, &file.StoragePath, &file.MimeType, &file.FileSize, &checksum); err != nil {
+			return nil, err
+		}
+		file.Checksum = strings.ToLower(strings.TrimSpace(checksum.String))
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// migrateFile 원본을 대상에 복사하고 체크섬을 검증합니다. 대상에 이미 같은 객체가 있으면 복사하지 않고 false를 반환합니다.
+func migrateFile(ctx context.Context, source, target services.FileStorage, file migrationFile) (bool, error) {
+	if file.Checksum != "" {
+		if sum, err := objectChecksum(ctx, target, file.StoragePath); err == nil && sum == file.Checksum {
+			return false, nil
+		}
+	}
+
+	body, info, err := source.Get(ctx, file.StoragePath)
+	if err != nil {
+		return false, fmt.Errorf("open source: %w", err)
+	}
+	hash := sha256.New()
+	err = target.Put(ctx, file.StoragePath, io.TeeReader(body, hash), info.Size, file.MimeType)
+	body.Close()
+	if err != nil {
+		return false, fmt.Errorf("write target: %w", err)
+	}
+
+	// 원본이 이미 손상되었으면 대상에 남기지 않습니다.
+	sourceSum := hex.EncodeToString(hash.Sum(nil))
+	if file.Checksum != "" && sourceSum != file.Checksum {
+		target.Delete(ctx, file.StoragePath)
+		return false, fmt.Errorf("source checksum %s does not match recorded %s", sourceSum, file.Checksum)
+	}
+
+	targetSum, err := objectChecksum(ctx, target, file.StoragePath)
+	if err != nil {
+		return false, fmt.Errorf("verify target: %w", err)
+	}
+	if targetSum != sourceSum {
+		return false, fmt.Errorf("target checksum %s does not match source %s", targetSum, sourceSum)
+	}
+	return true, nil
+}
+
+// objectChecksum 저장소 객체를 끝까지 읽어 SHA-256을 계산합니다.
+func objectChecksum(ctx context.Context, storage services.FileStorage, key string) (string, error) {
+	body, _, err := storage.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
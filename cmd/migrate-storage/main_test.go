@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"studiolicense/services"
+)
+
+func newMigrationStorages(t *testing.T, key, content string) (services.FileStorage, services.FileStorage) {
+	t.Helper()
+	source := services.NewFileSystemStorage(t.TempDir())
+	target := services.NewFileSystemStorage(t.TempDir())
+	if err := source.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("seed source: %v", err)
+	}
+	return source, target
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestMigrateFileCopiesAndSkipsExisting(t *testing.T) {
+	ctx := context.Background()
+	source, target := newMigrationStorages(t, "releases/app.zip", "installer")
+	file := migrationFile{ID: "f1", StoragePath: "releases/app.zip", MimeType: "application/zip", Checksum: sha256Hex("installer")}
+
+	copied, err := migrateFile(ctx, source, target, file)
+	if err != nil || !copied {
+		t.Fatalf("migrateFile = %v, %v; want copied", copied, err)
+	}
+	if sum, err := objectChecksum(ctx, target, file.StoragePath); err != nil || sum != file.Checksum {
+		t.Fatalf("target checksum = %s, %v", sum, err)
+	}
+
+	// 다시 실행하면 대상에 같은 체크섬의 객체가 있으므로 복사하지 않습니다.
+	copied, err = migrateFile(ctx, source, target, file)
+	if err != nil || copied {
+		t.Fatalf("second migrateFile = %v, %v; want skipped", copied, err)
+	}
+}
+
+func TestMigrateFileRemovesTargetOnChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	source, target := newMigrationStorages(t, "releases/app.zip", "corrupted")
+	file := migrationFile{ID: "f1", StoragePath: "releases/app.zip", MimeType: "application/zip", Checksum: sha256Hex("installer")}
+
+	copied, err := migrateFile(ctx, source, target, file)
+	if err == nil || copied {
+		t.Fatalf("migrateFile = %v, %v; want checksum error", copied, err)
+	}
+	if !strings.Contains(err.Error(), "does not match recorded") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := target.Stat(ctx, file.StoragePath); !errors.Is(err, services.ErrFileObjectNotFound) {
+		t.Fatalf("corrupted copy left in target: Stat error = %v", err)
+	}
+}
+
+func TestMigrateFileMissingSource(t *testing.T) {
+	ctx := context.Background()
+	source, target := newMigrationStorages(t, "releases/app.zip", "installer")
+
+	_, err := migrateFile(ctx, source, target, migrationFile{ID: "f2", StoragePath: "releases/missing.zip"})
+	if !errors.Is(err, services.ErrFileObjectNotFound) {
+		t.Fatalf("migrateFile error = %v, want ErrFileObjectNotFound", err)
+	}
+}
@@ -50,6 +50,11 @@ func Initialize(dsn string) error {
 		return fmt.Errorf("failed to ensure device view permission: %w", err)
 	}
 
+	// 고객 테이블 도입 이전 라이선스를 이메일 기준으로 고객에 연결
+	if err := backfillLicenseCustomers(); err != nil {
+		return fmt.Errorf("failed to backfill license customers: %w", err)
+	}
+
 	// 샘플 제품 생성
 	if err := createSampleProducts(); err != nil {
 		return fmt.Errorf("failed to create sample products: %w", err)
@@ -238,6 +243,203 @@ func createTables() error {
 			INDEX idx_product_files_active (is_active)
 		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
 
+		// 라이선스 인증서 서명 키 테이블
+		`CREATE TABLE IF NOT EXISTS license_signing_keys (
+			id VARCHAR(50) PRIMARY KEY,
+			algorithm VARCHAR(20) NOT NULL DEFAULT 'Ed25519',
+			public_key VARCHAR(255) NOT NULL,
+			private_key TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'active',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			retired_at DATETIME NULL,
+			INDEX idx_signing_keys_status (status)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 플로팅 라이선스 임대(lease) 테이블
+		`CREATE TABLE IF NOT EXISTS license_leases (
+			id VARCHAR(50) PRIMARY KEY,
+			license_id VARCHAR(50) NOT NULL,
+			device_fingerprint VARCHAR(255) NOT NULL,
+			device_name VARCHAR(255),
+			status VARCHAR(50) NOT NULL DEFAULT 'active',
+			checked_out_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_heartbeat_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at DATETIME NOT NULL,
+			released_at DATETIME NULL,
+			FOREIGN KEY (license_id) REFERENCES licenses(id) ON DELETE CASCADE,
+			INDEX idx_leases_license_status (license_id, status),
+			INDEX idx_leases_expires (expires_at)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 제품 기능 카탈로그 테이블
+		`CREATE TABLE IF NOT EXISTS product_features (
+			id VARCHAR(50) PRIMARY KEY,
+			product_id VARCHAR(50) NOT NULL,
+			feature_key VARCHAR(100) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			description TEXT,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+			UNIQUE KEY unique_product_feature (product_id, feature_key)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 제품 릴리스 테이블 (라이선스 유지보수 기간 기준 버전 권한 판단)
+		`CREATE TABLE IF NOT EXISTS product_releases (
+			id VARCHAR(50) PRIMARY KEY,
+			product_id VARCHAR(50) NOT NULL,
+			version VARCHAR(50) NOT NULL,
+			release_date DATE NOT NULL,
+			channel VARCHAR(20) NOT NULL DEFAULT 'stable',
+			notes TEXT,
+			created_by VARCHAR(50),
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+			UNIQUE KEY unique_product_release (product_id, version),
+			INDEX idx_product_releases_date (product_id, release_date)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 라이선스 기능 권한 테이블
+		`CREATE TABLE IF NOT EXISTS license_entitlements (
+			id VARCHAR(50) PRIMARY KEY,
+			license_id VARCHAR(50) NOT NULL,
+			feature_id VARCHAR(50) NOT NULL,
+			quantity INT NULL,
+			expires_at DATETIME NULL,
+			created_by VARCHAR(50),
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (license_id) REFERENCES licenses(id) ON DELETE CASCADE,
+			FOREIGN KEY (feature_id) REFERENCES product_features(id) ON DELETE CASCADE,
+			UNIQUE KEY unique_license_feature (license_id, feature_id),
+			INDEX idx_entitlements_feature (feature_id)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 체험판 발급 이력 테이블 (제품별 디바이스/이메일당 1회)
+		`CREATE TABLE IF NOT EXISTS trial_claims (
+			id VARCHAR(50) PRIMARY KEY,
+			product_id VARCHAR(50) NOT NULL,
+			license_id VARCHAR(50) NULL,
+			device_fingerprint VARCHAR(255) NOT NULL,
+			customer_email VARCHAR(100) NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (license_id) REFERENCES licenses(id) ON DELETE SET NULL,
+			UNIQUE KEY unique_trial_device (product_id, device_fingerprint),
+			UNIQUE KEY unique_trial_email (product_id, customer_email)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 사용량 이벤트 테이블 (클라이언트 event_id 기준 중복 방지)
+		`CREATE TABLE IF NOT EXISTS usage_events (
+			id VARCHAR(50) PRIMARY KEY,
+			license_id VARCHAR(50) NOT NULL,
+			device_id VARCHAR(50) NULL,
+			event_id VARCHAR(100) NOT NULL,
+			metric VARCHAR(100) NOT NULL,
+			quantity BIGINT NOT NULL,
+			period_key VARCHAR(20) NOT NULL,
+			recorded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (license_id) REFERENCES licenses(id) ON DELETE CASCADE,
+			UNIQUE KEY unique_usage_event (license_id, event_id),
+			INDEX idx_usage_events_metric (license_id, metric, period_key)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 라이선스별/기간별 사용량 집계 테이블
+		`CREATE TABLE IF NOT EXISTS usage_counters (
+			license_id VARCHAR(50) NOT NULL,
+			metric VARCHAR(100) NOT NULL,
+			period_key VARCHAR(20) NOT NULL,
+			used BIGINT NOT NULL DEFAULT 0,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (license_id, metric, period_key),
+			FOREIGN KEY (license_id) REFERENCES licenses(id) ON DELETE CASCADE
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 라이선스 변경 이력 테이블 (갱신/연장/수정 시 이전·이후 값)
+		`CREATE TABLE IF NOT EXISTS license_events (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			license_id VARCHAR(50) NOT NULL,
+			event_type VARCHAR(30) NOT NULL,
+			changes TEXT NOT NULL,
+			reason TEXT,
+			order_ref VARCHAR(100) NULL,
+			actor_id VARCHAR(50) NOT NULL,
+			actor_name VARCHAR(100),
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (license_id) REFERENCES licenses(id) ON DELETE CASCADE,
+			INDEX idx_license_events_license (license_id, created_at),
+			INDEX idx_license_events_order (order_ref)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 고객(조직) 테이블 (라이선스의 customer_id가 참조)
+		`CREATE TABLE IF NOT EXISTS customers (
+			id VARCHAR(50) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			organization VARCHAR(255) NULL,
+			email VARCHAR(100) NOT NULL,
+			phone VARCHAR(50) NULL,
+			external_id VARCHAR(100) NULL,
+			contacts TEXT NULL,
+			notes TEXT,
+			created_by VARCHAR(50),
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY unique_customer_email (email),
+			UNIQUE KEY unique_customer_external_id (external_id),
+			INDEX idx_customers_organization (organization)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 폐기 목록 일련번호 테이블 (목록 내용 해시가 바뀔 때마다 새 일련번호 발급)
+		`CREATE TABLE IF NOT EXISTS revocation_list_serials (
+			serial BIGINT AUTO_INCREMENT PRIMARY KEY,
+			content_hash CHAR(64) NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 결제 플랜 매핑 테이블 (결제 웹훅의 플랜 코드 → 발급할 라이선스 조건)
+		`CREATE TABLE IF NOT EXISTS payment_plans (
+			id VARCHAR(50) PRIMARY KEY,
+			provider VARCHAR(50) NOT NULL,
+			plan_code VARCHAR(100) NOT NULL,
+			product_id VARCHAR(50) NOT NULL,
+			policy_id VARCHAR(50) NULL,
+			license_type VARCHAR(20) NOT NULL DEFAULT 'node_locked',
+			max_devices INT NOT NULL DEFAULT 1,
+			duration_days INT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY unique_payment_plan (provider, plan_code),
+			FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+			FOREIGN KEY (policy_id) REFERENCES policies(id) ON DELETE SET NULL
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 결제 웹훅 이벤트 테이블 (이벤트 ID 기준 중복 처리 방지)
+		`CREATE TABLE IF NOT EXISTS payment_events (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			provider VARCHAR(50) NOT NULL,
+			event_id VARCHAR(191) NOT NULL,
+			event_type VARCHAR(50) NOT NULL,
+			result VARCHAR(20) NOT NULL,
+			license_ids TEXT NULL,
+			payload MEDIUMTEXT NOT NULL,
+			received_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY unique_payment_event (provider, event_id),
+			INDEX idx_payment_events_received (received_at)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 요청 서명 nonce 테이블 (REQUEST_NONCE_STORE=mysql일 때 재전송 차단용)
+		`CREATE TABLE IF NOT EXISTS request_nonces (
+			nonce_key VARCHAR(191) PRIMARY KEY,
+			expires_at DATETIME NOT NULL,
+			INDEX idx_request_nonces_expires (expires_at)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
+		// 요청 속도 제한 토큰 버킷 테이블 (RATE_LIMIT_STORE=mysql일 때 서버 간 공유)
+		`CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+			bucket_key VARCHAR(191) PRIMARY KEY,
+			tokens DOUBLE NOT NULL,
+			updated_at_ms BIGINT NOT NULL,
+			full_at_ms BIGINT NOT NULL,
+			INDEX idx_rate_limit_buckets_full (full_at_ms)
+		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
+
 		// 클라이언트 로그 테이블
 		`CREATE TABLE IF NOT EXISTS client_logs (
 			id BIGINT AUTO_INCREMENT PRIMARY KEY,
@@ -261,6 +463,53 @@ func createTables() error {
 		) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci`,
 	}
 
+	// 기존 테이블에 대한 컬럼 추가 (이미 적용된 경우 Duplicate 오류로 무시됨)
+	schemaMigrations := []string{
+		`ALTER TABLE licenses ADD COLUMN license_type VARCHAR(20) NOT NULL DEFAULT 'node_locked' AFTER policy_id`,
+		`ALTER TABLE device_activations ADD COLUMN component_hashes TEXT NULL AFTER device_fingerprint`,
+		`ALTER TABLE products ADD COLUMN fingerprint_threshold INT NULL AFTER status`,
+		`ALTER TABLE licenses ADD COLUMN grace_period_days INT NULL AFTER status`,
+		`ALTER TABLE licenses ADD COLUMN max_offline_days INT NULL AFTER grace_period_days`,
+		`ALTER TABLE licenses ADD COLUMN is_trial TINYINT(1) NOT NULL DEFAULT 0 AFTER license_type`,
+		`ALTER TABLE licenses ADD COLUMN converted_at DATETIME NULL AFTER is_trial`,
+		`ALTER TABLE products ADD COLUMN trial_days INT NULL AFTER fingerprint_threshold`,
+		`ALTER TABLE licenses ADD COLUMN usage_quotas TEXT NULL AFTER max_offline_days`,
+		`ALTER TABLE device_activations ADD COLUMN offline_public_key VARCHAR(100) NULL AFTER component_hashes`,
+		`ALTER TABLE products ADD COLUMN request_signing VARCHAR(20) NULL AFTER trial_days`,
+		`ALTER TABLE products ADD COLUMN client_secret VARCHAR(128) NULL AFTER request_signing`,
+		`ALTER TABLE products ADD COLUMN client_public_key VARCHAR(100) NULL AFTER client_secret`,
+		`ALTER TABLE products ADD COLUMN key_template TEXT NULL AFTER client_public_key`,
+		`ALTER TABLE licenses ADD COLUMN license_key_prefix VARCHAR(32) NULL AFTER license_key`,
+		`ALTER TABLE licenses ADD COLUMN customer_id VARCHAR(50) NULL AFTER policy_id`,
+		`CREATE INDEX idx_licenses_customer ON licenses (customer_id)`,
+		`ALTER TABLE licenses ADD COLUMN suspension_reason VARCHAR(50) NULL AFTER status`,
+		`ALTER TABLE licenses ADD COLUMN suspended_at DATETIME NULL AFTER suspension_reason`,
+		`ALTER TABLE licenses ADD COLUMN resume_at DATE NULL AFTER suspended_at`,
+		`ALTER TABLE licenses ADD COLUMN revocation_reason VARCHAR(50) NULL AFTER resume_at`,
+		`ALTER TABLE licenses ADD COLUMN revoked_at DATETIME NULL AFTER revocation_reason`,
+		`ALTER TABLE licenses ADD COLUMN starts_at DATETIME NULL AFTER max_devices`,
+		`CREATE INDEX idx_licenses_starts ON licenses (starts_at)`,
+		`ALTER TABLE licenses ADD COLUMN payment_provider VARCHAR(50) NULL AFTER notes`,
+		`ALTER TABLE licenses ADD COLUMN payment_order_id VARCHAR(191) NULL AFTER payment_provider`,
+		`ALTER TABLE licenses ADD COLUMN payment_subscription_id VARCHAR(191) NULL AFTER payment_order_id`,
+		`CREATE INDEX idx_licenses_payment_order ON licenses (payment_provider, payment_order_id)`,
+		`CREATE INDEX idx_licenses_payment_subscription ON licenses (payment_provider, payment_subscription_id)`,
+		`ALTER TABLE licenses ADD COLUMN maintenance_until DATE NULL AFTER expires_at`,
+		`ALTER TABLE product_releases ADD COLUMN mandatory TINYINT(1) NOT NULL DEFAULT 0 AFTER channel`,
+		`ALTER TABLE product_files ADD COLUMN version VARCHAR(50) NULL AFTER delivery_url`,
+		`ALTER TABLE product_files ADD COLUMN os VARCHAR(20) NULL AFTER version`,
+		`ALTER TABLE product_files ADD COLUMN arch VARCHAR(20) NULL AFTER os`,
+		`CREATE INDEX idx_product_files_version ON product_files (product_id, version)`,
+		`ALTER TABLE product_files ADD COLUMN channel VARCHAR(20) NOT NULL DEFAULT 'stable' AFTER arch`,
+		`ALTER TABLE product_files ADD COLUMN rollout_percentage INT NOT NULL DEFAULT 100 AFTER channel`,
+		`ALTER TABLE product_files ADD COLUMN rollout_status VARCHAR(20) NOT NULL DEFAULT 'active' AFTER rollout_percentage`,
+		`ALTER TABLE product_files ADD COLUMN rollout_updated_at DATETIME NULL AFTER rollout_status`,
+		`ALTER TABLE licenses ADD COLUMN release_channel VARCHAR(20) NULL AFTER maintenance_until`,
+		`ALTER TABLE device_activations ADD COLUMN release_channel VARCHAR(20) NULL AFTER offline_public_key`,
+		`ALTER TABLE trial_claims ADD COLUMN component_hashes TEXT NULL AFTER device_fingerprint`,
+	}
+	baseTables = append(baseTables, schemaMigrations...)
+
 	// MySQL 테이블 생성
 	for _, sql := range baseTables {
 		if _, err := DB.Exec(sql); err != nil {
@@ -287,6 +536,36 @@ func ensureDeviceViewPermissionBackfill() error {
 	return err
 }
 
+// backfillLicenseCustomers 고객이 연결되지 않은 라이선스를 이메일(대소문자 무시)별로 묶어 고객을 만들고 연결합니다.
+// 같은 이메일의 고객이 이미 있으면 그 고객에 연결하므로 여러 번 실행해도 안전합니다.
+func backfillLicenseCustomers() error {
+	_, err := DB.Exec(`
+		INSERT IGNORE INTO customers (id, name, email, created_by, created_at, updated_at)
+		SELECT CONCAT('cust-', LEFT(MD5(LOWER(TRIM(customer_email))), 16)),
+			MIN(customer_name), LOWER(TRIM(customer_email)), MIN(created_by), MIN(created_at), NOW()
+		FROM licenses
+		WHERE customer_id IS NULL AND TRIM(customer_email) <> ''
+		GROUP BY LOWER(TRIM(customer_email))
+	`)
+	if err != nil {
+		return err
+	}
+
+	result, err := DB.Exec(`
+		UPDATE licenses l
+		JOIN customers c ON c.email = LOWER(TRIM(l.customer_email))
+		SET l.customer_id = c.id
+		WHERE l.customer_id IS NULL
+	`)
+	if err != nil {
+		return err
+	}
+	if linked, _ := result.RowsAffected(); linked > 0 {
+		logger.Info("Linked %d existing licenses to customers by email", linked)
+	}
+	return nil
+}
+
 // contains 문자열 포함 여부 확인
 func contains(s, substr string) bool {
 	if len(s) == 0 || len(substr) == 0 {
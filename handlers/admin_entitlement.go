@@ -0,0 +1,472 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+var featureKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,99}$`)
+
+// ListProductFeatures 제품 기능 카탈로그 조회
+// @Summary 제품 기능 목록
+// @Description 제품에 정의된 기능(feature) 카탈로그를 조회합니다
+// @Tags 관리자 - 기능 권한
+// @Produce json
+// @Security BearerAuth
+// @Param product_id query string true "제품 ID"
+// @Success 200 {object} models.APIResponse{data=[]models.ProductFeature} "조회 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/product-features [get]
+func ListProductFeatures(w http.ResponseWriter, r *http.Request) {
+	productID := strings.TrimSpace(r.URL.Query().Get("product_id"))
+	if productID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("product_id is required", nil))
+		return
+	}
+
+	rows, err := database.DB.Query(`SELECT id, product_id, feature_key, name, COALESCE(description, ''), created_at
+		FROM product_features WHERE product_id = ? ORDER BY feature_key`, productID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query product features", err))
+		return
+	}
+	defer rows.Close()
+
+	features := []models.ProductFeature{}
+	for rows.Next() {
+		var f models.ProductFeature
+		if err := rows.Scan(&f.ID, &f.ProductID, &f.FeatureKey, &f.Name, &f.Description, &f.CreatedAt); err != nil {
+			logger.Warn("Failed to scan product feature: %v", err)
+			continue
+		}
+		features = append(features, f)
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Product features retrieved", features))
+}
+
+// CreateProductFeature 제품 기능 등록
+// @Summary 제품 기능 등록
+// @Description 제품 기능 카탈로그에 새 기능을 등록합니다
+// @Tags 관리자 - 기능 권한
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateProductFeatureRequest true "기능 정보"
+// @Success 201 {object} models.APIResponse{data=models.ProductFeature} "등록 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 409 {object} models.APIResponse "중복 기능 키"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/product-features [post]
+func CreateProductFeature(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateProductFeatureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.ProductID = strings.TrimSpace(req.ProductID)
+	req.FeatureKey = strings.ToLower(strings.TrimSpace(req.FeatureKey))
+	req.Name = strings.TrimSpace(req.Name)
+	if req.ProductID == "" || req.Name == "" || !featureKeyPattern.MatchString(req.FeatureKey) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("product_id, name and a valid feature_key (a-z, 0-9, _ . -) are required", nil))
+		return
+	}
+
+	var productCount int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM products WHERE id = ?", req.ProductID).Scan(&productCount); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify product", err))
+		return
+	}
+	if productCount == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product not found", nil))
+		return
+	}
+
+	id, err := utils.GenerateID("feat")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate ID", err))
+		return
+	}
+
+	now := utils.FormatDateTimeForDB(utils.NowSeoul())
+	if _, err := database.DB.Exec(`INSERT INTO product_features (id, product_id, feature_key, name, description, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`, id, req.ProductID, req.FeatureKey, req.Name, req.Description, now); err != nil {
+		if strings.Contains(err.Error(), "Duplicate") {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Feature key already exists for this product", nil))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create product feature", err))
+		return
+	}
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionCreateFeature,
+			fmt.Sprintf("Product feature created: %s (%s)", req.FeatureKey, req.ProductID))
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.SuccessResponse("Product feature created", models.ProductFeature{
+		ID:          id,
+		ProductID:   req.ProductID,
+		FeatureKey:  req.FeatureKey,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   now,
+	}))
+}
+
+// DeleteProductFeature 제품 기능 삭제 (부여된 권한도 함께 삭제)
+// @Summary 제품 기능 삭제
+// @Description 기능을 카탈로그에서 삭제합니다. 라이선스에 부여된 해당 기능 권한도 함께 삭제됩니다.
+// @Tags 관리자 - 기능 권한
+// @Produce json
+// @Security BearerAuth
+// @Param id query string true "기능 ID"
+// @Success 200 {object} models.APIResponse "삭제 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "기능 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/product-features [delete]
+func DeleteProductFeature(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Feature ID is required", nil))
+		return
+	}
+
+	result, err := database.DB.Exec("DELETE FROM product_features WHERE id = ?", id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to delete product feature", err))
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product feature not found", nil))
+		return
+	}
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionDeleteFeature, "Product feature deleted: "+id)
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Product feature deleted", nil))
+}
+
+// ListLicenseEntitlements 라이선스 기능 권한 목록
+// @Summary 라이선스 기능 권한 조회
+// @Description 라이선스에 부여된 기능 권한을 조회합니다 (만료된 권한 포함)
+// @Tags 관리자 - 기능 권한
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Success 200 {object} models.APIResponse{data=[]models.LicenseEntitlement} "조회 성공"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/entitlements [get]
+func ListLicenseEntitlements(w http.ResponseWriter, r *http.Request) {
+	licenseID := licenseIDFromRequest(r)
+	if !authorizeLicenseAccess(w, r, licenseID) {
+		return
+	}
+
+	rows, err := database.DB.Query(`SELECT e.id, e.license_id, e.feature_id, f.feature_key, f.name,
+		e.quantity, e.expires_at, COALESCE(e.created_by, ''), e.created_at
+		FROM license_entitlements e
+		JOIN product_features f ON e.feature_id = f.id
+		WHERE e.license_id = ?
+		ORDER BY f.feature_key`, licenseID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query entitlements", err))
+		return
+	}
+	defer rows.Close()
+
+	entitlements := []models.LicenseEntitlement{}
+	for rows.Next() {
+		var e models.LicenseEntitlement
+		if err := rows.Scan(&e.ID, &e.LicenseID, &e.FeatureID, &e.FeatureKey, &e.FeatureName,
+			&e.Quantity, &e.ExpiresAt, &e.CreatedBy, &e.CreatedAt); err != nil {
+			logger.Warn("Failed to scan entitlement: %v", err)
+			continue
+		}
+		entitlements = append(entitlements, e)
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Entitlements retrieved", entitlements))
+}
+
+// GrantLicenseEntitlement 라이선스에 기능 권한 부여
+// @Summary 라이선스 기능 권한 부여
+// @Description 라이선스에 기능을 부여합니다. 이미 부여된 기능이면 수량과 만료일을 갱신합니다.
+// @Tags 관리자 - 기능 권한
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.GrantEntitlementRequest true "부여 정보"
+// @Success 200 {object} models.APIResponse "부여 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/entitlements [post]
+func GrantLicenseEntitlement(w http.ResponseWriter, r *http.Request) {
+	licenseID := licenseIDFromRequest(r)
+	if !authorizeLicenseAccess(w, r, licenseID) {
+		return
+	}
+
+	var req models.GrantEntitlementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.FeatureID) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("feature_id is required", err))
+		return
+	}
+	if req.Quantity != nil && *req.Quantity < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Quantity cannot be negative", nil))
+		return
+	}
+
+	var expiresAt *string
+	if strings.TrimSpace(req.ExpiresAt) != "" {
+		ts, err := utils.ParseUserDate(req.ExpiresAt)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid expiration date format", err))
+			return
+		}
+		// 날짜만 지정한 경우 해당 일자 끝까지 유효하도록 다음 날 0시로 저장합니다.
+		if len(strings.TrimSpace(req.ExpiresAt)) == len("2006-01-02") {
+			ts = utils.StartOfDay(ts).AddDate(0, 0, 1)
+		}
+		value := utils.FormatDateTimeForDB(ts)
+		expiresAt = &value
+	}
+
+	// 기능은 라이선스와 같은 제품의 카탈로그에 속해야 합니다.
+	var featureKey string
+	err := database.DB.QueryRow(`SELECT f.feature_key FROM product_features f
+		JOIN licenses l ON l.product_id = f.product_id
+		WHERE f.id = ? AND l.id = ?`, req.FeatureID, licenseID).Scan(&featureKey)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Feature does not belong to the license product", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify feature", err))
+		return
+	}
+
+	id, err := utils.GenerateID("ent")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate ID", err))
+		return
+	}
+
+	adminID, _ := r.Context().Value("admin_id").(string)
+	if _, err := database.DB.Exec(`INSERT INTO license_entitlements (id, license_id, feature_id, quantity, expires_at, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE quantity = VALUES(quantity), expires_at = VALUES(expires_at)`,
+		id, licenseID, req.FeatureID, req.Quantity, expiresAt, adminID, utils.FormatDateTimeForDB(utils.NowSeoul()),
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to grant entitlement", err))
+		return
+	}
+
+	if adminID != "" {
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionGrantEntitlement,
+			fmt.Sprintf("Entitlement granted: %s → %s", featureKey, licenseID))
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Entitlement granted", map[string]interface{}{
+		"license_id":  licenseID,
+		"feature_id":  req.FeatureID,
+		"feature_key": featureKey,
+		"quantity":    req.Quantity,
+		"expires_at":  expiresAt,
+	}))
+}
+
+// RevokeLicenseEntitlement 라이선스 기능 권한 회수
+// @Summary 라이선스 기능 권한 회수
+// @Description 라이선스에 부여된 기능 권한을 삭제합니다
+// @Tags 관리자 - 기능 권한
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param feature_id query string true "기능 ID"
+// @Success 200 {object} models.APIResponse "회수 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "부여된 권한 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/entitlements [delete]
+func RevokeLicenseEntitlement(w http.ResponseWriter, r *http.Request) {
+	licenseID := licenseIDFromRequest(r)
+	if !authorizeLicenseAccess(w, r, licenseID) {
+		return
+	}
+
+	featureID := strings.TrimSpace(r.URL.Query().Get("feature_id"))
+	if featureID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("feature_id is required", nil))
+		return
+	}
+
+	result, err := database.DB.Exec("DELETE FROM license_entitlements WHERE license_id = ? AND feature_id = ?", licenseID, featureID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to revoke entitlement", err))
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Entitlement not found", nil))
+		return
+	}
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionRevokeEntitlement,
+			fmt.Sprintf("Entitlement revoked: %s ← %s", featureID, licenseID))
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Entitlement revoked", nil))
+}
+
+// GetLicensesByEntitlement 특정 기능을 보유한 라이선스 목록
+// @Summary 기능별 라이선스 조회
+// @Description 지정한 기능 권한을 현재 보유한 라이선스 목록을 반환합니다
+// @Tags 관리자 - 기능 권한
+// @Produce json
+// @Security BearerAuth
+// @Param feature_key query string true "기능 키"
+// @Param product_id query string false "제품 ID"
+// @Param include_expired query bool false "만료된 권한 포함 여부"
+// @Success 200 {object} models.APIResponse "조회 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/entitlements/licenses [get]
+func GetLicensesByEntitlement(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	featureKey := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("feature_key")))
+	if featureKey == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("feature_key is required", nil))
+		return
+	}
+
+	scope, isSuper, adminID, err := resolveResourceScope(r, models.ResourceTypeLicenses)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to evaluate license permissions", err))
+		return
+	}
+
+	query := `SELECT l.id, ` + utils.LicenseKeyDisplaySQL("l.license_key") + `, l.customer_name, l.customer_email, l.status, l.expires_at,
+		COALESCE(prod.name, ''), f.feature_key, e.quantity, e.expires_at
+		FROM license_entitlements e
+		JOIN product_features f ON e.feature_id = f.id
+		JOIN licenses l ON e.license_id = l.id
+		LEFT JOIN products prod ON l.product_id = prod.id
+		WHERE f.feature_key = ?`
+	args := []interface{}{featureKey}
+
+	if productID := strings.TrimSpace(r.URL.Query().Get("product_id")); productID != "" {
+		query += " AND f.product_id = ?"
+		args = append(args, productID)
+	}
+	if r.URL.Query().Get("include_expired") != "true" {
+		query += " AND (e.expires_at IS NULL OR e.expires_at > ?)"
+		args = append(args, utils.FormatDateTimeForDB(utils.NowSeoul()))
+	}
+	if !isSuper {
+		filterSQL, filterArgs := utils.BuildResourceFilter(scope, "l.id", "l.created_by", adminID)
+		query += filterSQL
+		args = append(args, filterArgs...)
+	}
+	query += " ORDER BY l.created_at DESC LIMIT 1000"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query licenses", err))
+		return
+	}
+	defer rows.Close()
+
+	items := []map[string]interface{}{}
+	for rows.Next() {
+		var (
+			id, key, name, email, status, expires, productName, fKey string
+			quantity                                                 sql.NullInt64
+			entitlementExpires                                       sql.NullTime
+		)
+		if err := rows.Scan(&id, &key, &name, &email, &status, &expires, &productName, &fKey, &quantity, &entitlementExpires); err != nil {
+			logger.Warn("Failed to scan entitlement license row: %v", err)
+			continue
+		}
+
+		item := map[string]interface{}{
+			"license_id":     id,
+			"license_key":    key,
+			"customer_name":  name,
+			"customer_email": email,
+			"product_name":   productName,
+			"status":         status,
+			"expires_at":     normalizeDateOnly(expires),
+			"feature_key":    fKey,
+			"quantity":       nil,
+			"feature_until":  nil,
+		}
+		if quantity.Valid {
+			item["quantity"] = quantity.Int64
+		}
+		if entitlementExpires.Valid {
+			item["feature_until"] = entitlementExpires.Time.Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Licenses retrieved", items))
+}
@@ -11,7 +11,7 @@ import (
 	"mime"
 	"net/http"
 	"net/url"
-	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -20,6 +20,7 @@ import (
 	"studiolicense/database"
 	"studiolicense/logger"
 	"studiolicense/models"
+	"studiolicense/services"
 	"studiolicense/utils"
 )
 
@@ -27,7 +28,21 @@ const (
 	maxFileUploadSize = 100 << 20 // 100 MB
 )
 
-var fileStorageBaseDir = filepath.Join("data", "files")
+// fileDownloadURLTTL 저장소가 직접 다운로드 URL을 지원할 때 발급하는 URL의 유효 기간
+const fileDownloadURLTTL = 5 * time.Minute
+
+var (
+	fileStorage services.FileStorage = services.NewFileSystemStorage(services.DefaultFileStorageDir)
+	// fileDownloadRedirect가 true이면 다운로드 요청을 저장소의 서명 URL로 리다이렉트합니다 (S3 등).
+	fileDownloadRedirect bool
+)
+
+// SetFileStorage는 업로드/다운로드에 사용할 파일 저장소를 주입한다.
+// redirectDownloads가 true이고 저장소가 서명 URL을 지원하면 다운로드를 서버가 중계하지 않고 저장소로 리다이렉트한다.
+func SetFileStorage(storage services.FileStorage, redirectDownloads bool) {
+	fileStorage = storage
+	fileDownloadRedirect = redirectDownloads
+}
 
 // ListFiles 파일 목록 조회
 func ListFiles(w http.ResponseWriter, r *http.Request) {
@@ -127,12 +142,6 @@ func UploadFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := os.MkdirAll(fileStorageBaseDir, 0755); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to prepare storage directory", err))
-		return
-	}
-
 	r.Body = http.MaxBytesReader(w, r.Body, maxFileUploadSize+int64(1<<20))
 	if err := r.ParseMultipartForm(maxFileUploadSize); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -168,14 +177,7 @@ func UploadFile(w http.ResponseWriter, r *http.Request) {
 	ext := strings.ToLower(filepath.Ext(originalName))
 	storedName := strings.ToLower(fileID) + ext
 	subDir := time.Now().Format("2006/01")
-	relPath := filepath.Join(subDir, storedName)
-	absPath := filepath.Join(fileStorageBaseDir, filepath.FromSlash(relPath))
-
-	if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create storage path", err))
-		return
-	}
+	relPath := path.Join(subDir, storedName)
 
 	var reader io.Reader = file
 	mimeType := header.Header.Get("Content-Type")
@@ -207,25 +209,25 @@ func UploadFile(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	dst, err := os.Create(absPath)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to store file", err))
-		return
-	}
-	defer dst.Close()
-
+	// 저장소에 쓰는 동안 체크섬과 크기를 함께 계산합니다.
 	hash := sha256.New()
-	size, err := io.Copy(io.MultiWriter(dst, hash), reader)
-	if err != nil {
+	counter := &byteCounter{}
+	if err := fileStorage.Put(r.Context(), relPath, io.TeeReader(reader, io.MultiWriter(hash, counter)), header.Size, mimeType); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"storage":    fileStorage.Name(),
+			"path":       relPath,
+			"error":      err.Error(),
+		}).Error("Failed to store uploaded file")
+
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to save file", err))
 		return
 	}
 
+	size := counter.n
 	checksum := hex.EncodeToString(hash.Sum(nil))
 	now := utils.NowSeoul().Format("2006-01-02 15:04:05")
-	relPath = filepath.ToSlash(relPath)
 
 	insertQuery := `INSERT INTO files
 		(id, original_name, stored_name, description, mime_type, file_size, checksum, storage_path, uploaded_by, uploaded_username, created_at, updated_at)
@@ -246,7 +248,7 @@ func UploadFile(w http.ResponseWriter, r *http.Request) {
 		now,
 	)
 	if err != nil {
-		os.Remove(absPath)
+		fileStorage.Delete(r.Context(), relPath)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to persist file metadata", err))
 		return
@@ -335,8 +337,7 @@ func DeleteFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fullPath := filepath.Join(fileStorageBaseDir, filepath.FromSlash(file.StoragePath))
-	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+	if err := fileStorage.Delete(r.Context(), file.StoragePath); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to delete file from storage", err))
 		return
@@ -380,10 +381,22 @@ func getFileRecord(fileID string) (models.FileAsset, error) {
 }
 
 func streamFileDownload(w http.ResponseWriter, r *http.Request, file models.FileAsset) {
-	fullPath := filepath.Join(fileStorageBaseDir, filepath.FromSlash(file.StoragePath))
-	f, err := os.Open(fullPath)
+	// 서명 URL을 지원하는 저장소는 서버를 거치지 않고 저장소에서 바로 내려받게 합니다.
+	if fileDownloadRedirect {
+		presigned, err := fileStorage.PresignGet(r.Context(), file.StoragePath, fileDownloadURLTTL, file.OriginalName)
+		if err == nil {
+			logFileDownload(r, file)
+			http.Redirect(w, r, presigned, http.StatusFound)
+			return
+		}
+		if err != services.ErrPresignNotSupported {
+			logger.Warn("Failed to presign download for %s, streaming instead: %v", file.ID, err)
+		}
+	}
+
+	body, info, err := fileStorage.Get(r.Context(), file.StoragePath)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if err == services.ErrFileObjectNotFound {
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode(models.ErrorResponse("Stored file not found", nil))
 		} else {
@@ -392,28 +405,43 @@ func streamFileDownload(w http.ResponseWriter, r *http.Request, file models.File
 		}
 		return
 	}
-	defer f.Close()
-
-	stat, err := f.Stat()
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to stat file", err))
-		return
-	}
+	defer body.Close()
 
 	encodedName := url.PathEscape(file.OriginalName)
 	disposition := fmt.Sprintf("attachment; filename=\"%s\"; filename*=UTF-8''%s", sanitizeFilename(file.OriginalName), encodedName)
 	w.Header().Set("Content-Type", file.MimeType)
-	w.Header().Set("Content-Length", strconv.FormatInt(stat.Size(), 10))
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
 	w.Header().Set("Content-Disposition", disposition)
 
+	logFileDownload(r, file)
+
+	// 로컬 디스크 파일은 Range 요청(이어받기)을 지원하고, 원격 저장소는 그대로 전달합니다.
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, file.OriginalName, info.ModTime, seeker)
+		return
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	io.Copy(w, body)
+}
+
+func logFileDownload(r *http.Request, file models.FileAsset) {
 	if adminID, ok := r.Context().Value("admin_id").(string); ok && adminID != "" {
 		if username, ok := r.Context().Value("username").(string); ok {
 			utils.LogAdminActivity(adminID, username, models.AdminActionDownloadFile, fmt.Sprintf("Downloaded file %s (%s)", file.ID, file.OriginalName))
 		}
 	}
+}
+
+// byteCounter 저장소로 보낸 바이트 수를 셉니다.
+type byteCounter struct {
+	n int64
+}
 
-	http.ServeContent(w, r, file.OriginalName, stat.ModTime(), f)
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
 }
 
 func parsePositiveInt(val string, fallback int) int {
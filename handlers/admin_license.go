@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -65,6 +66,36 @@ func CreateLicense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 시작 시각이 미래이면 scheduled 상태로 발급하고 시작 시각이 되면 스케줄러가 활성화합니다.
+	var startsAt *string
+	if strings.TrimSpace(req.StartsAt) != "" {
+		value, err := parseLicenseStartsAt(req.StartsAt)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid start date format", err))
+			return
+		}
+		if value[:10] > expiresAtStr {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Start date cannot be after the expiration date", nil))
+			return
+		}
+		startsAt = &value
+	}
+	status := initialLicenseStatus(startsAt)
+
+	// 유지보수 종료일이 있으면 그날까지 출시된 제품 버전만 사용할 수 있습니다.
+	var maintenanceUntil *string
+	if strings.TrimSpace(req.MaintenanceUntil) != "" {
+		value, err := parseLicenseExpiresAt(strings.TrimSpace(req.MaintenanceUntil))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid maintenance date format", err))
+			return
+		}
+		maintenanceUntil = &value
+	}
+
 	// 제품 ID는 필수이며 활성 제품이어야 합니다.
 	req.ProductID = strings.TrimSpace(req.ProductID)
 	if req.ProductID == "" {
@@ -111,8 +142,44 @@ func CreateLicense(w http.ResponseWriter, r *http.Request) {
 		policyID = &req.PolicyID
 	}
 
-	// 라이선스 키 생성
-	licenseKey, err := utils.GenerateLicenseKey()
+	if (req.GracePeriodDays != nil && *req.GracePeriodDays < 0) || (req.MaxOfflineDays != nil && *req.MaxOfflineDays < 0) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Grace period and offline days cannot be negative", nil))
+		return
+	}
+
+	usageQuotas, err := encodeUsageQuotas(req.UsageQuotas)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid usage quotas", err))
+		return
+	}
+
+	// 라이선스 유형 (기본값: node_locked)
+	licenseType := strings.TrimSpace(req.LicenseType)
+	if licenseType == "" {
+		licenseType = models.LicenseTypeNodeLocked
+	}
+	if !models.IsValidLicenseType(licenseType) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid license type", nil))
+		return
+	}
+
+	// 고객 연결: customer_id가 없으면 이메일로 고객을 찾고, 없으면 새로 만듭니다.
+	customer, ok := resolveLicenseCustomer(w, r, req.CustomerID, req.CustomerName, req.CustomerEmail, creatorID)
+	if !ok {
+		return
+	}
+	var customerID *string
+	if customer.ID != "" {
+		customerID = &customer.ID
+		req.CustomerName = customer.Name
+		req.CustomerEmail = customer.Email
+	}
+
+	// 라이선스 키 생성 (제품 키 템플릿 적용)
+	licenseKey, err := utils.GenerateProductLicenseKey(req.ProductID)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate license key", err))
@@ -129,14 +196,17 @@ func CreateLicense(w http.ResponseWriter, r *http.Request) {
 
 	// DB에 저장
 	query := `
-		INSERT INTO licenses (id, license_key, product_id, policy_id, customer_name, 
-			customer_email, max_devices, expires_at, status, created_by, notes, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO licenses (id, license_key, license_key_prefix, product_id, policy_id, customer_id, license_type, customer_name, 
+			customer_email, max_devices, starts_at, expires_at, maintenance_until, status, grace_period_days, max_offline_days,
+			usage_quotas, created_by, notes, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
+	// 해시 저장 모드에서는 키의 해시와 표시용 접두사만 저장하고, 전체 키는 이 응답에서만 반환합니다.
 	_, err = database.DB.Exec(query,
-		id, licenseKey, productID, policyID, req.CustomerName,
-		req.CustomerEmail, req.MaxDevices, expiresAtStr, models.LicenseStatusActive, creatorID,
+		id, utils.LicenseKeyLookup(licenseKey), utils.LicenseKeyPrefix(licenseKey, req.ProductID), productID, policyID, customerID, licenseType, req.CustomerName,
+		req.CustomerEmail, req.MaxDevices, startsAt, expiresAtStr, maintenanceUntil, status,
+		req.GracePeriodDays, req.MaxOfflineDays, usageQuotas, creatorID,
 		req.Notes, now, now,
 	)
 
@@ -155,22 +225,33 @@ func CreateLicense(w http.ResponseWriter, r *http.Request) {
 
 	// 생성된 라이선스 조회
 	license := models.License{
-		ID:            id,
-		LicenseKey:    licenseKey,
-		ProductID:     productIDPtr,
-		PolicyID:      policyID,
-		ProductName:   productName,
-		CustomerName:  req.CustomerName,
-		CustomerEmail: req.CustomerEmail,
-		MaxDevices:    req.MaxDevices,
-		ExpiresAt:     expiresAtStr,
-		Status:        models.LicenseStatusActive,
-		CreatedBy:     creatorID,
-		Notes:         req.Notes,
-		CreatedAt:     now,
-		UpdatedAt:     now,
+		ID:               id,
+		LicenseKey:       licenseKey,
+		ProductID:        productIDPtr,
+		PolicyID:         policyID,
+		CustomerID:       customerID,
+		LicenseType:      licenseType,
+		ProductName:      productName,
+		CustomerName:     req.CustomerName,
+		CustomerEmail:    req.CustomerEmail,
+		MaxDevices:       req.MaxDevices,
+		StartsAt:         startsAt,
+		ExpiresAt:        expiresAtStr,
+		Status:           status,
+		CreatedBy:        creatorID,
+		MaintenanceUntil: maintenanceUntil,
+		Notes:            req.Notes,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		GracePeriodDays:  req.GracePeriodDays,
+		MaxOfflineDays:   req.MaxOfflineDays,
+	}
+	if usageQuotas != nil {
+		license.UsageQuotas = json.RawMessage(*usageQuotas)
 	}
 
+	recordLicenseChange(r, id, models.LicenseEventCreated, nil, "", "")
+
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(models.SuccessResponse("License created successfully", license))
 
@@ -193,6 +274,7 @@ func CreateLicense(w http.ResponseWriter, r *http.Request) {
 // @Param page_size query int false "페이지 크기" default(20)
 // @Param status query string false "상태 필터 (active, expired, revoked)"
 // @Param search query string false "검색어 (라이선스 키, 고객명, 이메일)"
+// @Param customer_id query string false "고객 ID 필터"
 // @Success 200 {object} models.PaginatedResponse{data=[]models.License} "조회 성공"
 // @Failure 401 {object} models.APIResponse "인증 필요"
 // @Failure 500 {object} models.APIResponse "서버 에러"
@@ -207,8 +289,6 @@ func GetLicenses(w http.ResponseWriter, r *http.Request) {
 	if pageSize < 1 || pageSize > 100 {
 		pageSize = 20
 	}
-	status := r.URL.Query().Get("status")
-	search := r.URL.Query().Get("search")
 
 	scope, isSuper, adminID, err := resolveResourceScope(r, models.ResourceTypeLicenses)
 	if err != nil {
@@ -216,60 +296,29 @@ func GetLicenses(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to evaluate license permissions", err))
 		return
 	}
+	filterSQL, filterArgs := licenseListFilter(r, scope, isSuper, adminID)
 
 	// 전체 개수 조회
 	var totalCount int
-	countQuery := "SELECT COUNT(*) FROM licenses WHERE 1=1"
-	countArgs := make([]interface{}, 0)
-
-	if status != "" {
-		countQuery += " AND status = ?"
-		countArgs = append(countArgs, status)
-	}
-	if search != "" {
-		countQuery += " AND (license_key LIKE ? OR customer_name LIKE ? OR customer_email LIKE ?)"
-		searchPattern := "%" + search + "%"
-		countArgs = append(countArgs, searchPattern, searchPattern, searchPattern)
-	}
-	if !isSuper {
-		filterSQL, filterArgs := utils.BuildResourceFilter(scope, "id", "created_by", adminID)
-		countQuery += filterSQL
-		countArgs = append(countArgs, filterArgs...)
-	}
-
-	database.DB.QueryRow(countQuery, countArgs...).Scan(&totalCount)
+	countQuery := "SELECT COUNT(*) FROM licenses l WHERE 1=1" + filterSQL
+	database.DB.QueryRow(countQuery, filterArgs...).Scan(&totalCount)
 
 	// 데이터 조회
 	offset := (page - 1) * pageSize
-	query := `SELECT l.id, l.license_key, l.product_id, l.policy_id,
+	query := `SELECT l.id, ` + utils.LicenseKeyDisplaySQL("l.license_key") + `, l.product_id, l.policy_id, l.customer_id, l.license_type, l.is_trial, l.converted_at,
 		COALESCE(prod.name, '') as product_name,
 		COALESCE(pol.policy_name, '') as policy_name,
 		l.customer_name, l.customer_email, l.max_devices,
 	COALESCE((SELECT COUNT(*) FROM device_activations WHERE license_id = l.id AND status = 'active'), 0) as active_devices,
-	l.expires_at, l.status, l.created_by, l.notes, l.created_at, l.updated_at 
+	l.starts_at, l.expires_at, l.status, l.grace_period_days, l.max_offline_days, l.created_by, l.notes, l.created_at, l.updated_at,
+	DATE_FORMAT(l.maintenance_until, '%Y-%m-%d')
 		FROM licenses l
 		LEFT JOIN products prod ON l.product_id = prod.id
 		LEFT JOIN policies pol ON l.policy_id = pol.id
-		WHERE 1=1`
-
-	dataArgs := make([]interface{}, 0)
-	if status != "" {
-		query += " AND l.status = ?"
-		dataArgs = append(dataArgs, status)
-	}
-	if search != "" {
-		query += " AND (l.license_key LIKE ? OR l.customer_name LIKE ? OR l.customer_email LIKE ?)"
-		searchPattern := "%" + search + "%"
-		dataArgs = append(dataArgs, searchPattern, searchPattern, searchPattern)
-	}
-	if !isSuper {
-		filterSQL, filterArgs := utils.BuildResourceFilter(scope, "l.id", "l.created_by", adminID)
-		query += filterSQL
-		dataArgs = append(dataArgs, filterArgs...)
-	}
+		WHERE 1=1` + filterSQL
 
 	query += " ORDER BY l.created_at DESC LIMIT ? OFFSET ?"
-	dataArgs = append(dataArgs, pageSize, offset)
+	dataArgs := append(append([]interface{}{}, filterArgs...), pageSize, offset)
 
 	rows, err := database.DB.Query(query, dataArgs...)
 	if err != nil {
@@ -283,11 +332,13 @@ func GetLicenses(w http.ResponseWriter, r *http.Request) {
 	for rows.Next() {
 		var license models.License
 		err := rows.Scan(
-			&license.ID, &license.LicenseKey, &license.ProductID, &license.PolicyID, &license.ProductName,
+			&license.ID, &license.LicenseKey, &license.ProductID, &license.PolicyID, &license.CustomerID, &license.LicenseType, &license.IsTrial, &license.ConvertedAt, &license.ProductName,
 			&license.PolicyName, &license.CustomerName, &license.CustomerEmail, &license.MaxDevices,
 			&license.ActiveDevices,
-			&license.ExpiresAt, &license.Status, &license.CreatedBy, &license.Notes,
+			&license.StartsAt, &license.ExpiresAt, &license.Status, &license.GracePeriodDays, &license.MaxOfflineDays,
+			&license.CreatedBy, &license.Notes,
 			&license.CreatedAt, &license.UpdatedAt,
+			&license.MaintenanceUntil,
 		)
 		if err != nil {
 			continue
@@ -313,6 +364,36 @@ func GetLicenses(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// licenseListFilter는 목록 조회와 내보내기가 함께 쓰는 검색 조건(status, search, customer_id)과 리소스 스코프 조건을 만듭니다.
+// 반환되는 SQL은 licenses 테이블의 별칭이 l이라고 가정합니다.
+func licenseListFilter(r *http.Request, scope models.AdminResourcePermissionConfig, isSuper bool, adminID string) (string, []interface{}) {
+	status := r.URL.Query().Get("status")
+	search := r.URL.Query().Get("search")
+	customerID := strings.TrimSpace(r.URL.Query().Get("customer_id"))
+
+	query := ""
+	args := make([]interface{}, 0)
+	if status != "" {
+		query += " AND l.status = ?"
+		args = append(args, status)
+	}
+	if customerID != "" {
+		query += " AND l.customer_id = ?"
+		args = append(args, customerID)
+	}
+	if search != "" {
+		query += " AND (l.license_key LIKE ? OR l.license_key = ? OR l.license_key_prefix LIKE ? OR l.customer_name LIKE ? OR l.customer_email LIKE ?)"
+		searchPattern := "%" + search + "%"
+		args = append(args, searchPattern, searchKeyLookup(search), searchPattern, searchPattern, searchPattern)
+	}
+	if !isSuper {
+		scopeSQL, scopeArgs := utils.BuildResourceFilter(scope, "l.id", "l.created_by", adminID)
+		query += scopeSQL
+		args = append(args, scopeArgs...)
+	}
+	return query, args
+}
+
 // licenseIDFromRequest extracts the license ID from context, path, or query parameters.
 func licenseIDFromRequest(r *http.Request) string {
 	if id, _ := r.Context().Value("path_license_id").(string); strings.TrimSpace(id) != "" {
@@ -364,12 +445,14 @@ func GetLicense(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var license models.License
-	query := `SELECT l.id, l.license_key, l.product_id, l.policy_id,
+	query := `SELECT l.id, ` + utils.LicenseKeyDisplaySQL("l.license_key") + `, l.product_id, l.policy_id, l.customer_id, l.license_type, l.is_trial, l.converted_at,
 		COALESCE(prod.name, '') as product_name,
 		COALESCE(pol.policy_name, '') as policy_name,
 		l.customer_name, l.customer_email, l.max_devices,
 		COALESCE((SELECT COUNT(*) FROM device_activations WHERE license_id = l.id AND status = 'active'), 0) as active_devices,
-		l.expires_at, l.status, l.created_by, l.notes, l.created_at, l.updated_at 
+		l.starts_at, l.expires_at, l.status, l.grace_period_days, l.max_offline_days, l.usage_quotas, l.created_by, l.notes, l.created_at, l.updated_at,
+		l.suspension_reason, l.suspended_at, DATE_FORMAT(l.resume_at, '%Y-%m-%d'), l.revocation_reason, l.revoked_at,
+		l.payment_provider, l.payment_order_id, l.payment_subscription_id, DATE_FORMAT(l.maintenance_until, '%Y-%m-%d'), l.release_channel
 		FROM licenses l
 		LEFT JOIN products prod ON l.product_id = prod.id
 		LEFT JOIN policies pol ON l.policy_id = pol.id
@@ -381,11 +464,15 @@ func GetLicense(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = database.DB.QueryRow(query, args...).Scan(
-		&license.ID, &license.LicenseKey, &license.ProductID, &license.PolicyID, &license.ProductName,
+		&license.ID, &license.LicenseKey, &license.ProductID, &license.PolicyID, &license.CustomerID, &license.LicenseType, &license.IsTrial, &license.ConvertedAt, &license.ProductName,
 		&license.PolicyName, &license.CustomerName, &license.CustomerEmail, &license.MaxDevices,
 		&license.ActiveDevices,
-		&license.ExpiresAt, &license.Status, &license.CreatedBy, &license.Notes,
+		&license.StartsAt, &license.ExpiresAt, &license.Status, &license.GracePeriodDays, &license.MaxOfflineDays,
+		&license.UsageQuotas, &license.CreatedBy, &license.Notes,
 		&license.CreatedAt, &license.UpdatedAt,
+		&license.SuspensionReason, &license.SuspendedAt, &license.ResumeAt, &license.RevocationReason, &license.RevokedAt,
+		&license.PaymentProvider, &license.PaymentOrderID, &license.PaymentSubscriptionID, &license.MaintenanceUntil,
+		&license.ReleaseChannel,
 	)
 
 	if err == sql.ErrNoRows {
@@ -464,6 +551,67 @@ func UpdateLicense(w http.ResponseWriter, r *http.Request) {
 		policyID = &req.PolicyID
 	}
 
+	usageQuotas, err := encodeUsageQuotas(req.UsageQuotas)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid usage quotas", err))
+		return
+	}
+
+	// 고객 연결 변경: 지정한 고객의 이름/이메일로 라이선스의 고객 정보를 맞춥니다.
+	linkCustomer := req.CustomerID != nil
+	linkedCustomerID := ""
+	if linkCustomer && strings.TrimSpace(*req.CustomerID) != "" {
+		creatorID, _ := r.Context().Value("admin_id").(string)
+		customer, ok := resolveLicenseCustomer(w, r, *req.CustomerID, "", "", creatorID)
+		if !ok {
+			return
+		}
+		linkedCustomerID = customer.ID
+		req.CustomerName = customer.Name
+		req.CustomerEmail = customer.Email
+	}
+
+	// 시작 시각 변경: 빈 문자열이면 제거하고, 값은 만료일 이전이어야 합니다.
+	startsAtStr := ""
+	if req.StartsAt != nil && strings.TrimSpace(*req.StartsAt) != "" {
+		value, err := parseLicenseStartsAt(*req.StartsAt)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid start date format", err))
+			return
+		}
+		limit := expiresAtStr
+		if limit == "" {
+			var current sql.NullString
+			err := database.DB.QueryRow("SELECT DATE_FORMAT(expires_at, '%Y-%m-%d') FROM licenses WHERE id = ?", id).Scan(&current)
+			if err != nil && err != sql.ErrNoRows {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+				return
+			}
+			limit = current.String
+		}
+		if limit != "" && value[:10] > limit {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Start date cannot be after the expiration date", nil))
+			return
+		}
+		startsAtStr = value
+	}
+
+	// 유지보수 종료일 변경: 빈 문자열이면 제거해 모든 버전을 허용합니다.
+	maintenanceUntilStr := ""
+	if req.MaintenanceUntil != nil && strings.TrimSpace(*req.MaintenanceUntil) != "" {
+		value, err := parseLicenseExpiresAt(strings.TrimSpace(*req.MaintenanceUntil))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid maintenance date format", err))
+			return
+		}
+		maintenanceUntilStr = value
+	}
+
 	// 최대 디바이스 수 검증: 현재 활성 디바이스 수보다 작게 설정 불가
 	if req.MaxDevices > 0 {
 		var activeCount int
@@ -487,14 +635,59 @@ func UpdateLicense(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// policy_id 포함한 업데이트 쿼리
+	// 유예/오프라인 일수는 생략하면 유지, 음수이면 NULL(정책 또는 서버 기본값)로 초기화합니다.
+	// 사용량 한도는 생략하면 유지, 빈 객체이면 NULL(정책 한도)로 초기화합니다.
 	query := `UPDATE licenses SET customer_name = ?,
-		customer_email = ?, max_devices = ?, expires_at = ?, notes = ?, policy_id = ?, updated_at = ?
+		customer_email = ?, max_devices = ?, expires_at = ?, notes = ?, policy_id = ?,
+		grace_period_days = CASE WHEN ? IS NULL THEN grace_period_days WHEN ? < 0 THEN NULL ELSE ? END,
+		max_offline_days = CASE WHEN ? IS NULL THEN max_offline_days WHEN ? < 0 THEN NULL ELSE ? END,
+		usage_quotas = CASE WHEN ? THEN ? ELSE usage_quotas END,
+		customer_id = CASE WHEN ? THEN NULLIF(?, '') ELSE customer_id END,
+		starts_at = CASE WHEN ? THEN NULLIF(?, '') ELSE starts_at END,
+		maintenance_until = CASE WHEN ? THEN NULLIF(?, '') ELSE maintenance_until END,
+		updated_at = ?
 		WHERE id = ?`
 
-	_, err := database.DB.Exec(query,
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	// 수정과 만료일/시작 시각에 따른 상태 전환을 행을 잠근 한 트랜잭션에서 처리해
+	// 스케줄러나 다른 상태 변경과 겹쳐도 전환 결과와 이력이 어긋나지 않게 합니다.
+	currentStatus, err := utils.CurrentLicenseStatus(tx, id, true)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+
+	// 변경 이력 비교용 스냅샷
+	before, err := utils.LoadLicenseSnapshot(tx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+
+	_, err = tx.Exec(query,
 		req.CustomerName,
 		req.CustomerEmail, req.MaxDevices, expiresAtStr, req.Notes,
 		policyID,
+		req.GracePeriodDays, req.GracePeriodDays, req.GracePeriodDays,
+		req.MaxOfflineDays, req.MaxOfflineDays, req.MaxOfflineDays,
+		req.UsageQuotas != nil, usageQuotas,
+		linkCustomer, linkedCustomerID,
+		req.StartsAt != nil, startsAtStr,
+		req.MaintenanceUntil != nil, maintenanceUntilStr,
 		time.Now().Format("2006-01-02 15:04:05"), id,
 	)
 
@@ -505,49 +698,86 @@ func UpdateLicense(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 만료일 변경 시 상태 자동 조정
+	// 만료일이 미래이면서 만료(또는 유예) 상태이면 활성화, 과거이면서 활성 상태이면 만료 처리합니다.
+	originalStatus := currentStatus
+	autoStatus := ""
 	if req.ExpiresAt != "" {
-		now := time.Now().Format("2006-01-02 15:04:05")
+		today := time.Now().Format("2006-01-02")
+		if expiresAtStr >= today && (currentStatus == models.LicenseStatusExpired || currentStatus == models.LicenseStatusGrace) {
+			autoStatus = models.LicenseStatusActive
+		}
+		if expiresAtStr < today && currentStatus == models.LicenseStatusActive {
+			autoStatus = models.LicenseStatusExpired
+		}
+		if autoStatus != "" {
+			if err := utils.TransitionLicenseStatus(tx, id, utils.LicenseTransition{From: currentStatus, To: autoStatus}); err != nil {
+				writeLicenseTransitionError(w, currentStatus, err)
+				return
+			}
+			currentStatus = autoStatus
+		}
+	}
 
-		// 현재 상태 확인
-		var currentStatus, licenseKey string
-		statusQuery := "SELECT status, license_key FROM licenses WHERE id = ?"
-		database.DB.QueryRow(statusQuery, id).Scan(&currentStatus, &licenseKey)
+	var licenseKey string
+	if autoStatus != "" {
+		if err := tx.QueryRow("SELECT "+utils.LicenseKeyDisplaySQL("license_key")+" FROM licenses WHERE id = ?", id).Scan(&licenseKey); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+			return
+		}
+	}
 
-		// 관리자 정보 가져오기
-		var adminID, username string
-		if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
-			adminID = adminIDRaw.(string)
+	// 시작 시각 변경 시 scheduled/active 상태 조정
+	if req.StartsAt != nil {
+		future := initialLicenseStatus(&startsAtStr) == models.LicenseStatusScheduled
+		next := ""
+		if currentStatus == models.LicenseStatusActive && future {
+			next = models.LicenseStatusScheduled
 		}
-		if usernameRaw := r.Context().Value("username"); usernameRaw != nil {
-			username = usernameRaw.(string)
+		if currentStatus == models.LicenseStatusScheduled && !future {
+			next = models.LicenseStatusActive
 		}
+		if next != "" {
+			if err := utils.TransitionLicenseStatus(tx, id, utils.LicenseTransition{From: currentStatus, To: next}); err != nil {
+				writeLicenseTransitionError(w, currentStatus, err)
+				return
+			}
+			currentStatus = next
+		}
+	}
 
-		// 만료일이 미래이면서 만료 상태인 경우 -> 활성화
-		if expiresAtStr > now[:10] && currentStatus == models.LicenseStatusExpired {
-			updateStatusQuery := "UPDATE licenses SET status = ?, updated_at = ? WHERE id = ?"
-			database.DB.Exec(updateStatusQuery, models.LicenseStatusActive, now, id)
+	if err := recordLicenseChangeTx(tx, r, id, models.LicenseEventUpdated, before, req.Reason, req.OrderRef); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record license history", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update license", err))
+		return
+	}
+
+	if autoStatus != "" {
+		adminID, _ := r.Context().Value("admin_id").(string)
+		username, _ := r.Context().Value("username").(string)
 
+		if autoStatus == models.LicenseStatusActive {
 			logger.WithFields(map[string]interface{}{
 				"license_id": id,
-				"old_status": currentStatus,
-				"new_status": models.LicenseStatusActive,
+				"old_status": originalStatus,
+				"new_status": autoStatus,
 				"expires_at": expiresAtStr,
 			}).Info("License auto-reactivated due to expiration date extension")
 
 			// 활동 로그 기록
 			details := fmt.Sprintf("라이선스 키: %s, 만료일 연장으로 자동 재활성화 (만료일: %s)", licenseKey, expiresAtStr)
 			utils.LogAdminActivity(adminID, username, "라이선스 재활성화", details)
-		}
-
-		// 만료일이 과거이면서 활성 상태인 경우 -> 만료 처리
-		if expiresAtStr < now[:10] && currentStatus == models.LicenseStatusActive {
-			updateStatusQuery := "UPDATE licenses SET status = ?, updated_at = ? WHERE id = ?"
-			database.DB.Exec(updateStatusQuery, models.LicenseStatusExpired, now, id)
-
+		} else {
 			logger.WithFields(map[string]interface{}{
 				"license_id": id,
-				"old_status": currentStatus,
-				"new_status": models.LicenseStatusExpired,
+				"old_status": originalStatus,
+				"new_status": autoStatus,
 				"expires_at": expiresAtStr,
 			}).Info("License auto-expired due to past expiration date")
 
@@ -573,6 +803,12 @@ func UpdateLicense(w http.ResponseWriter, r *http.Request) {
 		if req.ExpiresAt != "" {
 			changes = append(changes, fmt.Sprintf("만료일: %s", expiresAtStr))
 		}
+		if linkCustomer {
+			changes = append(changes, fmt.Sprintf("고객 ID: %s", linkedCustomerID))
+		}
+		if req.MaintenanceUntil != nil {
+			changes = append(changes, fmt.Sprintf("유지보수 종료일: %s", displayValue(maintenanceUntilStr)))
+		}
 
 		details := fmt.Sprintf("라이선스 ID: %s | 변경사항: %s", id, strings.Join(changes, " | "))
 		utils.LogAdminActivity(adminID, username, "라이선스 수정", details)
@@ -581,8 +817,35 @@ func UpdateLicense(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(models.SuccessResponse("License updated successfully", nil))
 }
 
-// DeleteLicense 라이선스 삭제
-func DeleteLicense(w http.ResponseWriter, r *http.Request) {
+// writeLicenseTransitionError는 상태 전환 실패를 응답합니다. 현재 상태에서 허용되지 않는 전환이면 409입니다.
+func writeLicenseTransitionError(w http.ResponseWriter, status string, err error) {
+	if errors.Is(err, utils.ErrInvalidLicenseTransition) || errors.Is(err, utils.ErrLicenseStatusChanged) {
+		resp := models.ErrorResponse(fmt.Sprintf("Cannot change license status from %s", status), nil)
+		resp.Data = map[string]interface{}{"status": status}
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update license status", err))
+}
+
+// ConvertTrialLicense 체험판 라이선스를 정식 라이선스로 전환
+// @Summary 체험판 정식 전환
+// @Description 체험판 라이선스를 같은 키로 정식 라이선스로 전환합니다. 기존 디바이스 활성화는 유지됩니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.ConvertTrialRequest true "전환 정보"
+// @Success 200 {object} models.APIResponse "전환 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 409 {object} models.APIResponse "체험판이 아님"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/convert [post]
+func ConvertTrialLicense(w http.ResponseWriter, r *http.Request) {
 	id := licenseIDFromRequest(r)
 	if id == "" {
 		w.WriteHeader(http.StatusBadRequest)
@@ -590,27 +853,158 @@ func DeleteLicense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := "DELETE FROM licenses WHERE id = ?"
-	_, err := database.DB.Exec(query, id)
+	if !authorizeLicenseAccess(w, r, id) {
+		return
+	}
+
+	var req models.ConvertTrialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
 
+	expiresTime, err := time.Parse(time.RFC3339, req.ExpiresAt)
+	if err != nil {
+		expiresTime, err = time.Parse("2006-01-02", req.ExpiresAt)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid expiration date format", err))
+			return
+		}
+	}
+	expiresAtStr := expiresTime.Format("2006-01-02")
+	if expiresAtStr < time.Now().Format("2006-01-02") {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Expiration date cannot be in the past", nil))
+		return
+	}
+
+	var policyID *string
+	if req.PolicyID != "" {
+		var policyExists int
+		if err := database.DB.QueryRow("SELECT COUNT(*) FROM policies WHERE id = ?", req.PolicyID).Scan(&policyExists); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify policy", err))
+			return
+		}
+		if policyExists == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Policy not found", nil))
+			return
+		}
+		policyID = &req.PolicyID
+	}
+
+	tx, err := database.DB.Begin()
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to delete license", err))
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	// 동시에 들어온 전환·상태 변경과 겹치지 않도록 행을 잠근 뒤 확인하고 전환합니다.
+	var (
+		isTrial    bool
+		maxDevices int
+		licenseKey string
+		status     string
+	)
+	err = tx.QueryRow("SELECT is_trial, max_devices, status, "+utils.LicenseKeyDisplaySQL("license_key")+" FROM licenses WHERE id = ? FOR UPDATE", id).Scan(&isTrial, &maxDevices, &status, &licenseKey)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+	if !isTrial {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License is not a trial", nil))
+		return
+	}
+	// 폐기/일시 정지된 체험판은 전환으로 다시 활성화하지 않습니다.
+	if status == models.LicenseStatusRevoked || status == models.LicenseStatusSuspended {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse(fmt.Sprintf("Cannot convert a %s trial license", status), nil))
 		return
 	}
 
-	json.NewEncoder(w).Encode(models.SuccessResponse("License deleted successfully", nil))
+	if req.MaxDevices > 0 {
+		maxDevices = req.MaxDevices
+	}
+
+	before, err := utils.LoadLicenseSnapshot(tx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+
+	// 같은 라이선스 행을 그대로 전환하므로 키와 디바이스 활성화가 유지됩니다.
+	now := time.Now().Format("2006-01-02 15:04:05")
+	result, err := tx.Exec(`UPDATE licenses SET is_trial = 0, converted_at = ?, expires_at = ?, max_devices = ?,
+		policy_id = COALESCE(?, policy_id), notes = CASE WHEN ? = '' THEN notes ELSE ? END, updated_at = ?
+		WHERE id = ? AND is_trial = 1`,
+		now, expiresAtStr, maxDevices, policyID, req.Notes, req.Notes, now, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to convert trial license", err))
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License is not a trial", nil))
+		return
+	}
+	if status != models.LicenseStatusActive {
+		if err := utils.TransitionLicenseStatus(tx, id, utils.LicenseTransition{From: status, To: models.LicenseStatusActive}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to convert trial license", err))
+			return
+		}
+	}
+
+	if err := recordLicenseChangeTx(tx, r, id, models.LicenseEventConverted, before, req.Reason, req.OrderRef); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record license history", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to convert trial license", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"license_id":  id,
+		"expires_at":  expiresAtStr,
+		"max_devices": maxDevices,
+	}).Info("Trial license converted")
 
-	// 관리자 활동 로그
 	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
 		adminID := adminIDRaw.(string)
 		username, _ := r.Context().Value("username").(string)
-		utils.LogAdminActivity(adminID, username, models.AdminActionDeleteLicense, "License deleted: "+id)
+		details := fmt.Sprintf("라이선스 키: %s, 체험판 정식 전환 (만료일: %s, 최대 디바이스: %d)", licenseKey, expiresAtStr, maxDevices)
+		utils.LogAdminActivity(adminID, username, models.AdminActionConvertTrial, details)
 	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Trial license converted", map[string]interface{}{
+		"id":           id,
+		"license_key":  licenseKey,
+		"expires_at":   expiresAtStr,
+		"max_devices":  maxDevices,
+		"converted_at": now,
+	}))
 }
 
-// RevokeLicense 라이선스 폐기
-func RevokeLicense(w http.ResponseWriter, r *http.Request) {
+// DeleteLicense 라이선스 삭제
+func DeleteLicense(w http.ResponseWriter, r *http.Request) {
 	id := licenseIDFromRequest(r)
 	if id == "" {
 		w.WriteHeader(http.StatusBadRequest)
@@ -618,16 +1012,23 @@ func RevokeLicense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := "UPDATE licenses SET status = ?, updated_at = ? WHERE id = ?"
-	_, err := database.DB.Exec(query, models.LicenseStatusRevoked, time.Now().Format("2006-01-02 15:04:05"), id)
+	query := "DELETE FROM licenses WHERE id = ?"
+	_, err := database.DB.Exec(query, id)
 
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to revoke license", err))
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to delete license", err))
 		return
 	}
 
-	json.NewEncoder(w).Encode(models.SuccessResponse("License revoked successfully", nil))
+	json.NewEncoder(w).Encode(models.SuccessResponse("License deleted successfully", nil))
+
+	// 관리자 활동 로그
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionDeleteLicense, "License deleted: "+id)
+	}
 }
 
 // GetLicenseDevices 라이선스의 활성화된 디바이스 목록
@@ -665,7 +1066,7 @@ func GetLicenseDevices(w http.ResponseWriter, r *http.Request) {
 	}
 
 	query := `SELECT id, license_id, device_fingerprint, device_info, device_name, 
-		status, activated_at, last_validated_at, deactivated_at 
+		status, activated_at, last_validated_at, deactivated_at, release_channel
 		FROM device_activations WHERE license_id = ? ORDER BY activated_at DESC`
 
 	rows, err := database.DB.Query(query, licenseID)
@@ -682,7 +1083,7 @@ func GetLicenseDevices(w http.ResponseWriter, r *http.Request) {
 		err := rows.Scan(
 			&device.ID, &device.LicenseID, &device.DeviceFingerprint,
 			&device.DeviceInfo, &device.DeviceName, &device.Status,
-			&device.ActivatedAt, &device.LastValidatedAt, &device.DeactivatedAt,
+			&device.ActivatedAt, &device.LastValidatedAt, &device.DeactivatedAt, &device.ReleaseChannel,
 		)
 		if err != nil {
 			continue
@@ -690,8 +1091,49 @@ func GetLicenseDevices(w http.ResponseWriter, r *http.Request) {
 		devices = append(devices, device)
 	}
 
+	// 플로팅 라이선스는 include=leases 요청 시 좌석 임대 현황을 함께 반환합니다.
+	if r.URL.Query().Get("include") == "leases" {
+		leases, err := loadLicenseLeases(licenseID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query leases", err))
+			return
+		}
+		json.NewEncoder(w).Encode(models.SuccessResponse("Devices retrieved", map[string]interface{}{
+			"devices": devices,
+			"leases":  leases,
+		}))
+		return
+	}
+
 	json.NewEncoder(w).Encode(models.SuccessResponse("Devices retrieved", devices))
 }
+
+// encodeUsageQuotas는 사용량 한도를 검증하고 저장할 JSON으로 변환합니다. 비어 있으면 nil을 반환합니다.
+func encodeUsageQuotas(quotas map[string]models.UsageQuota) (*string, error) {
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+	for metric, quota := range quotas {
+		if !featureKeyPattern.MatchString(metric) {
+			return nil, fmt.Errorf("invalid metric name: %s", metric)
+		}
+		if quota.Period == "" {
+			quota.Period = models.UsagePeriodMonthly
+		}
+		if !models.IsValidUsagePeriod(quota.Period) {
+			return nil, fmt.Errorf("invalid usage period for %s: %s", metric, quota.Period)
+		}
+		quotas[metric] = quota
+	}
+	encoded, err := json.Marshal(quotas)
+	if err != nil {
+		return nil, err
+	}
+	value := string(encoded)
+	return &value, nil
+}
+
 func normalizeDateOnly(value string) string {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -714,3 +1156,9 @@ func normalizeDateOnly(value string) string {
 	}
 	return value
 }
+
+// searchKeyLookup 검색어가 전체 라이선스 키일 때 저장된 값(해시 저장 모드에서는 해시)과 비교할 수 있도록 변환합니다.
+func searchKeyLookup(search string) string {
+	normalized, _ := utils.NormalizeLicenseKey(search)
+	return utils.LicenseKeyLookup(normalized)
+}
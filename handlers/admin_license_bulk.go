@@ -0,0 +1,638 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/services"
+	"studiolicense/utils"
+)
+
+// maxBulkLicenseBodyBytes 일괄 발급 요청 본문 최대 크기
+const maxBulkLicenseBodyBytes = 5 << 20
+
+// bulkLicenseCSVColumns 일괄 발급 결과와 내보내기 CSV의 공통 열
+var bulkLicenseCSVColumns = []string{"license_id", "license_key", "product_id", "customer_id", "customer_name", "customer_email", "max_devices", "expires_at"}
+
+// BulkCreateLicenses 라이선스 일괄 발급
+// @Summary 라이선스 일괄 발급
+// @Description 개수(count) 또는 고객 목록(JSON customers, text/csv 본문)으로 라이선스를 한 트랜잭션에서 발급합니다.
+// @Description CSV 본문은 customer_id, customer_name, customer_email, max_devices, expires_at, notes 헤더를 사용하고 공통 설정은 쿼리 파라미터로 전달합니다.
+// @Description 한 행이라도 검증에 실패하면 아무것도 발급하지 않고 data.errors에 행별 오류를 반환합니다. 결과는 기본적으로 CSV이며 format=json이면 JSON입니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Accept text/csv
+// @Produce text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BulkCreateLicenseRequest false "일괄 발급 정보 (JSON)"
+// @Param product_id query string false "제품 ID (CSV 본문일 때)"
+// @Param policy_id query string false "정책 ID (CSV 본문일 때)"
+// @Param license_type query string false "라이선스 유형 (CSV 본문일 때)"
+// @Param max_devices query int false "기본 최대 디바이스 수 (CSV 본문일 때)"
+// @Param expires_at query string false "기본 만료일 (CSV 본문일 때)"
+// @Param starts_at query string false "사용 시작 시각 (CSV 본문일 때, 미래이면 scheduled로 발급)"
+// @Param maintenance_until query string false "유지보수 종료일 (CSV 본문일 때)"
+// @Param format query string false "결과 형식 (csv, json)" default(csv)
+// @Success 201 {string} string "발급된 라이선스 키 CSV"
+// @Failure 400 {object} models.APIResponse "잘못된 요청 또는 행별 검증 오류 (data.errors: []models.BulkLicenseRowError)"
+// @Failure 401 {object} models.APIResponse "인증 필요"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/bulk [post]
+func BulkCreateLicenses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	creatorID, _ := r.Context().Value("admin_id").(string)
+	if strings.TrimSpace(creatorID) == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Missing creator context", nil))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkLicenseBodyBytes)
+	req, err := decodeBulkLicenseRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	rows := req.Customers
+	if len(rows) == 0 {
+		for i := 0; i < req.Count; i++ {
+			rows = append(rows, models.BulkLicenseRow{
+				CustomerID:    req.CustomerID,
+				CustomerName:  req.CustomerName,
+				CustomerEmail: req.CustomerEmail,
+			})
+		}
+	}
+	maxRows := utils.GetEnvInt("BULK_LICENSE_MAX_ROWS", 1000)
+	if len(rows) == 0 || len(rows) > maxRows {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse(fmt.Sprintf("Between 1 and %d licenses can be issued at once", maxRows), nil))
+		return
+	}
+
+	// 공통 설정 검증 (제품, 정책, 유형, 유예/오프라인 일수, 사용량 한도)
+	req.ProductID = strings.TrimSpace(req.ProductID)
+	var productName string
+	if err := database.DB.QueryRow("SELECT name FROM products WHERE id = ? AND status = 'active'", req.ProductID).Scan(&productName); err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Product not found or inactive", nil))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify product", err))
+		return
+	}
+
+	var policyID *string
+	if req.PolicyID != "" {
+		var policyExists int
+		if err := database.DB.QueryRow("SELECT COUNT(*) FROM policies WHERE id = ?", req.PolicyID).Scan(&policyExists); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify policy", err))
+			return
+		}
+		if policyExists == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Policy not found", nil))
+			return
+		}
+		policyID = &req.PolicyID
+	}
+
+	licenseType := strings.TrimSpace(req.LicenseType)
+	if licenseType == "" {
+		licenseType = models.LicenseTypeNodeLocked
+	}
+	if !models.IsValidLicenseType(licenseType) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid license type", nil))
+		return
+	}
+
+	if (req.GracePeriodDays != nil && *req.GracePeriodDays < 0) || (req.MaxOfflineDays != nil && *req.MaxOfflineDays < 0) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Grace period and offline days cannot be negative", nil))
+		return
+	}
+
+	usageQuotas, err := encodeUsageQuotas(req.UsageQuotas)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid usage quotas", err))
+		return
+	}
+
+	var startsAt *string
+	if strings.TrimSpace(req.StartsAt) != "" {
+		value, err := parseLicenseStartsAt(req.StartsAt)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid start date format", err))
+			return
+		}
+		startsAt = &value
+	}
+	status := initialLicenseStatus(startsAt)
+
+	var maintenanceUntil *string
+	if strings.TrimSpace(req.MaintenanceUntil) != "" {
+		value, err := parseLicenseExpiresAt(strings.TrimSpace(req.MaintenanceUntil))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid maintenance date format", err))
+			return
+		}
+		maintenanceUntil = &value
+	}
+
+	// 행별 검증: 오류를 모두 모아 한 번에 반환합니다.
+	rowErrors := make([]models.BulkLicenseRowError, 0)
+	customerScope, customerSuper, adminID, err := resolveResourceScope(r, models.ResourceTypeCustomers)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to evaluate customer permissions", err))
+		return
+	}
+	today := time.Now().Format("2006-01-02")
+	for i := range rows {
+		row := &rows[i]
+		rowNumber := i + 1
+
+		if row.MaxDevices == 0 {
+			row.MaxDevices = req.MaxDevices
+		}
+		if row.MaxDevices < 1 {
+			rowErrors = append(rowErrors, models.BulkLicenseRowError{Row: rowNumber, Field: "max_devices", Message: "must be at least 1"})
+		}
+
+		if strings.TrimSpace(row.ExpiresAt) == "" {
+			row.ExpiresAt = req.ExpiresAt
+		}
+		expiresAt, err := parseLicenseExpiresAt(row.ExpiresAt)
+		switch {
+		case err != nil:
+			rowErrors = append(rowErrors, models.BulkLicenseRowError{Row: rowNumber, Field: "expires_at", Message: "invalid date format"})
+		case expiresAt < today:
+			rowErrors = append(rowErrors, models.BulkLicenseRowError{Row: rowNumber, Field: "expires_at", Message: "cannot be in the past"})
+		case startsAt != nil && (*startsAt)[:10] > expiresAt:
+			rowErrors = append(rowErrors, models.BulkLicenseRowError{Row: rowNumber, Field: "expires_at", Message: "cannot be before starts_at"})
+		default:
+			row.ExpiresAt = expiresAt
+		}
+
+		if row.Notes == "" {
+			row.Notes = req.Notes
+		}
+
+		row.CustomerID = strings.TrimSpace(row.CustomerID)
+		row.CustomerEmail = strings.TrimSpace(row.CustomerEmail)
+		if row.CustomerEmail != "" && !emailRegex.MatchString(row.CustomerEmail) {
+			rowErrors = append(rowErrors, models.BulkLicenseRowError{Row: rowNumber, Field: "customer_email", Message: "invalid email"})
+		}
+		if row.CustomerID != "" && customerService != nil {
+			customer, err := customerService.Get(r.Context(), row.CustomerID)
+			switch {
+			case errors.Is(err, services.ErrCustomerNotFound):
+				rowErrors = append(rowErrors, models.BulkLicenseRowError{Row: rowNumber, Field: "customer_id", Message: "customer not found"})
+			case err != nil:
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(models.ErrorResponse("Failed to resolve customer", err))
+				return
+			case !customerSuper && !utils.CanAccessResource(customerScope, customer.ID, customer.CreatedBy, adminID):
+				rowErrors = append(rowErrors, models.BulkLicenseRowError{Row: rowNumber, Field: "customer_id", Message: "customer access denied"})
+			}
+		}
+	}
+	if len(rowErrors) > 0 {
+		resp := models.ErrorResponse("Bulk license validation failed", nil)
+		resp.Data = map[string]interface{}{"errors": rowErrors}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	// 고객 연결 (같은 고객은 한 번만 조회/생성). 라이선스 트랜잭션이 실패해도 새로 만든 고객은 남지만 다음 요청에서 재사용됩니다.
+	resolved := make(map[string]models.Customer)
+	customerIDs := make([]*string, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		if customerService == nil || (row.CustomerID == "" && row.CustomerEmail == "") {
+			continue
+		}
+		cacheKey := "id:" + row.CustomerID
+		if row.CustomerID == "" {
+			cacheKey = "email:" + strings.ToLower(row.CustomerEmail)
+		}
+		customer, ok := resolved[cacheKey]
+		if !ok {
+			customer, err = customerService.ResolveForLicense(r.Context(), row.CustomerID, row.CustomerName, row.CustomerEmail, creatorID)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(models.ErrorResponse("Failed to resolve customer", err))
+				return
+			}
+			resolved[cacheKey] = customer
+		}
+		if customer.ID != "" {
+			linkedID := customer.ID
+			customerIDs[i] = &linkedID
+			row.CustomerName = customer.Name
+			row.CustomerEmail = customer.Email
+		}
+	}
+
+	keyTemplate, err := utils.LoadKeyTemplate(req.ProductID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license key template", err))
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO licenses (id, license_key, license_key_prefix, product_id, policy_id, customer_id, license_type, customer_name,
+			customer_email, max_devices, starts_at, expires_at, maintenance_until, status, grace_period_days, max_offline_days,
+			usage_quotas, created_by, notes, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create licenses", err))
+		return
+	}
+	defer stmt.Close()
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	productID := req.ProductID
+	actorID, actorName := licenseEventActor(r)
+	licenses := make([]models.License, 0, len(rows))
+	for i, row := range rows {
+		id, err := utils.GenerateID("lic")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate ID", err))
+			return
+		}
+		licenseKey, err := keyTemplate.Generate()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate license key", err))
+			return
+		}
+
+		if _, err := stmt.Exec(
+			id, utils.LicenseKeyLookup(licenseKey), utils.LicenseKeyPrefix(licenseKey, productID), productID, policyID, customerIDs[i], licenseType, row.CustomerName,
+			row.CustomerEmail, row.MaxDevices, startsAt, row.ExpiresAt, maintenanceUntil, status,
+			req.GracePeriodDays, req.MaxOfflineDays, usageQuotas, creatorID,
+			row.Notes, now, now,
+		); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"error":      err.Error(),
+				"row":        i + 1,
+				"product_id": productID,
+			}).Error("Failed to create bulk license")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create licenses", err))
+			return
+		}
+
+		// 발급 이력은 라이선스와 같은 트랜잭션에 기록해 일괄 발급이 실패하면 함께 취소됩니다.
+		snapshot, err := utils.LoadLicenseSnapshot(tx, id)
+		if err == nil {
+			err = utils.RecordLicenseEvent(tx, models.LicenseEvent{
+				LicenseID: id,
+				EventType: models.LicenseEventCreated,
+				Changes:   utils.DiffLicenseSnapshots(nil, snapshot),
+				Reason:    "bulk",
+				ActorID:   actorID,
+				ActorName: actorName,
+			})
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record license history", err))
+			return
+		}
+
+		licenses = append(licenses, models.License{
+			ID:               id,
+			LicenseKey:       licenseKey,
+			ProductID:        &productID,
+			PolicyID:         policyID,
+			CustomerID:       customerIDs[i],
+			LicenseType:      licenseType,
+			ProductName:      productName,
+			CustomerName:     row.CustomerName,
+			CustomerEmail:    row.CustomerEmail,
+			MaxDevices:       row.MaxDevices,
+			StartsAt:         startsAt,
+			ExpiresAt:        row.ExpiresAt,
+			MaintenanceUntil: maintenanceUntil,
+			Status:           status,
+			GracePeriodDays:  req.GracePeriodDays,
+			MaxOfflineDays:   req.MaxOfflineDays,
+			CreatedBy:        creatorID,
+			Notes:            row.Notes,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create licenses", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"product_id": productID,
+		"count":      len(licenses),
+	}).Info("Bulk licenses created")
+
+	if strings.EqualFold(r.URL.Query().Get("format"), "json") {
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.SuccessResponse("Licenses created successfully", licenses))
+	} else {
+		// 해시 저장 모드에서는 전체 키를 이 응답에서만 확인할 수 있습니다.
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="licenses-%s.csv"`, time.Now().Format("20060102-150405")))
+		w.WriteHeader(http.StatusCreated)
+		writer := csv.NewWriter(w)
+		writer.Write(bulkLicenseCSVColumns)
+		for _, license := range licenses {
+			writer.Write(bulkLicenseCSVRecord(license))
+		}
+		writer.Flush()
+	}
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionBulkCreateLicense,
+			fmt.Sprintf("Bulk licenses created: %d (product: %s)", len(licenses), productID))
+	}
+}
+
+// exportFlushInterval 내보내기 중 응답을 클라이언트로 흘려보내는 행 간격
+const exportFlushInterval = 200
+
+// licenseExportCSVColumns 라이선스 내보내기 CSV 열
+var licenseExportCSVColumns = []string{"license_id", "license_key", "product_id", "product_name", "policy_name", "customer_id",
+	"customer_name", "customer_email", "license_type", "is_trial", "status", "max_devices", "active_devices", "starts_at", "expires_at", "created_at"}
+
+// ExportLicenses 라이선스 내보내기
+// @Summary 라이선스 내보내기
+// @Description 목록 조회와 같은 필터(status, search, customer_id)와 리소스 스코프를 적용해 전체 라이선스를 CSV 또는 JSON 배열로 스트리밍합니다.
+// @Description 해시 저장 모드에서는 키 대신 표시용 접두사가 포함됩니다.
+// @Tags 관리자 - 라이선스
+// @Produce text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "형식 (csv, json)" default(csv)
+// @Param status query string false "상태 필터"
+// @Param search query string false "검색어 (라이선스 키, 고객명, 이메일)"
+// @Param customer_id query string false "고객 ID 필터"
+// @Success 200 {string} string "라이선스 CSV 또는 JSON 배열"
+// @Failure 401 {object} models.APIResponse "인증 필요"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/export [get]
+func ExportLicenses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	scope, isSuper, adminID, err := resolveResourceScope(r, models.ResourceTypeLicenses)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to evaluate license permissions", err))
+		return
+	}
+	filterSQL, filterArgs := licenseListFilter(r, scope, isSuper, adminID)
+
+	query := `SELECT l.id, ` + utils.LicenseKeyDisplaySQL("l.license_key") + `, l.product_id, l.policy_id, l.customer_id, l.license_type, l.is_trial,
+		COALESCE(prod.name, '') as product_name,
+		COALESCE(pol.policy_name, '') as policy_name,
+		l.customer_name, l.customer_email, l.max_devices,
+		COALESCE((SELECT COUNT(*) FROM device_activations WHERE license_id = l.id AND status = 'active'), 0) as active_devices,
+		l.starts_at, l.expires_at, l.status, l.created_by, l.notes, l.created_at, l.updated_at
+		FROM licenses l
+		LEFT JOIN products prod ON l.product_id = prod.id
+		LEFT JOIN policies pol ON l.policy_id = pol.id
+		WHERE 1=1` + filterSQL + " ORDER BY l.created_at DESC"
+
+	rows, err := database.DB.QueryContext(r.Context(), query, filterArgs...)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query licenses", err))
+		return
+	}
+	defer rows.Close()
+
+	asJSON := strings.EqualFold(r.URL.Query().Get("format"), "json")
+	filename := "licenses-" + time.Now().Format("20060102-150405")
+	if asJSON {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		io.WriteString(w, "[")
+	} else {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+	}
+
+	// 전체 결과를 메모리에 모으지 않고 행 단위로 기록하면서 주기적으로 flush합니다.
+	controller := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	writer := csv.NewWriter(w)
+	if !asJSON {
+		writer.Write(licenseExportCSVColumns)
+	}
+
+	count := 0
+	for rows.Next() {
+		var license models.License
+		if err := rows.Scan(
+			&license.ID, &license.LicenseKey, &license.ProductID, &license.PolicyID, &license.CustomerID, &license.LicenseType, &license.IsTrial,
+			&license.ProductName, &license.PolicyName, &license.CustomerName, &license.CustomerEmail, &license.MaxDevices,
+			&license.ActiveDevices, &license.StartsAt, &license.ExpiresAt, &license.Status, &license.CreatedBy, &license.Notes,
+			&license.CreatedAt, &license.UpdatedAt,
+		); err != nil {
+			logger.Warn("Failed to scan exported license: %v", err)
+			continue
+		}
+		license.ExpiresAt = normalizeDateOnly(license.ExpiresAt)
+
+		if asJSON {
+			if count > 0 {
+				io.WriteString(w, ",")
+			}
+			encoder.Encode(license)
+		} else {
+			writer.Write([]string{
+				license.ID, license.LicenseKey, stringValue(license.ProductID), license.ProductName, license.PolicyName,
+				stringValue(license.CustomerID), license.CustomerName, license.CustomerEmail, license.LicenseType,
+				strconv.FormatBool(license.IsTrial), license.Status, strconv.Itoa(license.MaxDevices),
+				strconv.Itoa(license.ActiveDevices), stringValue(license.StartsAt), license.ExpiresAt, license.CreatedAt,
+			})
+		}
+
+		count++
+		if count%exportFlushInterval == 0 {
+			writer.Flush()
+			controller.Flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		// 이미 응답 본문을 보내기 시작했으므로 로그만 남깁니다.
+		logger.Error("License export interrupted after %d rows: %v", count, err)
+	}
+
+	if asJSON {
+		io.WriteString(w, "]")
+	} else {
+		writer.Flush()
+	}
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionExportLicenses, fmt.Sprintf("Licenses exported: %d", count))
+	}
+}
+
+// decodeBulkLicenseRequest는 JSON 본문 또는 CSV 본문(공통 설정은 쿼리 파라미터)을 읽습니다.
+func decodeBulkLicenseRequest(r *http.Request) (models.BulkCreateLicenseRequest, error) {
+	var req models.BulkCreateLicenseRequest
+	contentType := strings.ToLower(r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(contentType, "text/csv") {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		return req, err
+	}
+
+	query := r.URL.Query()
+	req.ProductID = query.Get("product_id")
+	req.PolicyID = query.Get("policy_id")
+	req.LicenseType = query.Get("license_type")
+	req.ExpiresAt = query.Get("expires_at")
+	req.StartsAt = query.Get("starts_at")
+	req.MaintenanceUntil = query.Get("maintenance_until")
+	req.Notes = query.Get("notes")
+	if value := query.Get("max_devices"); value != "" {
+		maxDevices, err := strconv.Atoi(value)
+		if err != nil {
+			return req, fmt.Errorf("invalid max_devices: %w", err)
+		}
+		req.MaxDevices = maxDevices
+	}
+
+	reader := csv.NewReader(r.Body)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return req, fmt.Errorf("missing CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return req, err
+		}
+		row := models.BulkLicenseRow{
+			CustomerID:    field(record, "customer_id"),
+			CustomerName:  field(record, "customer_name"),
+			CustomerEmail: field(record, "customer_email"),
+			ExpiresAt:     field(record, "expires_at"),
+			Notes:         field(record, "notes"),
+		}
+		if value := field(record, "max_devices"); value != "" {
+			if row.MaxDevices, err = strconv.Atoi(value); err != nil {
+				return req, fmt.Errorf("line %d: invalid max_devices", line)
+			}
+		}
+		req.Customers = append(req.Customers, row)
+	}
+	return req, nil
+}
+
+// parseLicenseExpiresAt는 RFC3339 또는 YYYY-MM-DD 형식의 만료일을 YYYY-MM-DD로 바꿉니다.
+func parseLicenseExpiresAt(value string) (string, error) {
+	expiresTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		expiresTime, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return "", err
+		}
+	}
+	return expiresTime.Format("2006-01-02"), nil
+}
+
+// parseLicenseStartsAt는 RFC3339 또는 YYYY-MM-DD(서울 기준 자정) 형식의 시작 시각을 DATETIME 문자열로 바꿉니다.
+func parseLicenseStartsAt(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	startsTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		startsTime, err = time.ParseInLocation("2006-01-02", value, utils.SeoulLocation())
+		if err != nil {
+			return "", err
+		}
+	}
+	return utils.FormatDateTimeForDB(startsTime), nil
+}
+
+// initialLicenseStatus는 시작 시각이 미래이면 scheduled, 아니면 active를 반환합니다.
+func initialLicenseStatus(startsAt *string) string {
+	if startsAt != nil && *startsAt > utils.FormatDateTimeForDB(utils.NowSeoul()) {
+		return models.LicenseStatusScheduled
+	}
+	return models.LicenseStatusActive
+}
+
+func bulkLicenseCSVRecord(license models.License) []string {
+	return []string{
+		license.ID,
+		license.LicenseKey,
+		stringValue(license.ProductID),
+		stringValue(license.CustomerID),
+		license.CustomerName,
+		license.CustomerEmail,
+		strconv.Itoa(license.MaxDevices),
+		license.ExpiresAt,
+	}
+}
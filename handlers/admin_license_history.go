@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// GetLicenseHistory 라이선스 변경 이력 조회
+// @Summary 라이선스 변경 이력
+// @Description 생성, 수정, 갱신, 연장, 체험판 전환, 자동 만료 등 라이선스 변경 이력을 최신순으로 조회합니다.
+// @Tags 관리자 - 라이선스
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param limit query int false "최대 개수" default(100)
+// @Success 200 {object} models.APIResponse{data=[]models.LicenseEvent} "조회 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/history [get]
+func GetLicenseHistory(w http.ResponseWriter, r *http.Request) {
+	id := licenseIDFromRequest(r)
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License ID is required", nil))
+		return
+	}
+	if !authorizeLicenseAccess(w, r, id) {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	rows, err := database.DB.Query(`SELECT id, license_id, event_type, changes, COALESCE(reason, ''), order_ref,
+		actor_id, COALESCE(actor_name, ''), created_at
+		FROM license_events WHERE license_id = ? ORDER BY created_at DESC, id DESC LIMIT ?`, id, limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query license history", err))
+		return
+	}
+	defer rows.Close()
+
+	events := make([]models.LicenseEvent, 0)
+	for rows.Next() {
+		var (
+			event   models.LicenseEvent
+			changes string
+		)
+		if err := rows.Scan(&event.ID, &event.LicenseID, &event.EventType, &changes, &event.Reason, &event.OrderRef,
+			&event.ActorID, &event.ActorName, &event.CreatedAt); err != nil {
+			continue
+		}
+		json.Unmarshal([]byte(changes), &event.Changes)
+		events = append(events, event)
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("License history retrieved", events))
+}
+
+// RenewLicense 라이선스 갱신
+// @Summary 라이선스 갱신
+// @Description 새 이용 기간을 시작합니다. months/days는 오늘과 기존 만료일 중 늦은 날부터 계산하며, 만료(또는 유예) 상태인 라이선스는 다시 활성화됩니다.
+// @Description 일시 정지된 라이선스는 기간만 바뀌며 재개는 /resume으로 처리합니다.
+// @Description 갱신과 함께 최대 디바이스 수와 정책을 바꿀 수 있으며 변경 내용은 이력에 기록됩니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.RenewLicenseRequest true "갱신 정보"
+// @Success 200 {object} models.APIResponse{data=models.LicenseEvent} "갱신 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 409 {object} models.APIResponse "폐기된 라이선스"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/renew [post]
+func RenewLicense(w http.ResponseWriter, r *http.Request) {
+	var req models.RenewLicenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	specified := 0
+	for _, set := range []bool{req.Months != 0, req.Days != 0, strings.TrimSpace(req.ExpiresAt) != ""} {
+		if set {
+			specified++
+		}
+	}
+	if specified != 1 || req.Months < 0 || req.Days < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Specify exactly one of months, days or expires_at", nil))
+		return
+	}
+	if req.MaxDevices != nil && *req.MaxDevices < 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("max_devices must be at least 1", nil))
+		return
+	}
+
+	changeLicenseTerm(w, r, models.LicenseEventRenewed, req.Reason, req.OrderRef, req.MaxDevices, req.PolicyID,
+		func(current time.Time, today time.Time) (time.Time, error) {
+			if req.ExpiresAt != "" {
+				return parseTermDate(req.ExpiresAt)
+			}
+			base := current
+			if base.Before(today) {
+				base = today
+			}
+			return base.AddDate(0, req.Months, req.Days), nil
+		})
+}
+
+// ExtendLicense 라이선스 기간 연장
+// @Summary 라이선스 기간 연장
+// @Description 기존 만료일에 days를 더하거나 더 늦은 expires_at으로 바꿉니다. 새 만료일이 오늘 이후이면 만료(또는 유예) 상태인 라이선스가 다시 활성화됩니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.ExtendLicenseRequest true "연장 정보"
+// @Success 200 {object} models.APIResponse{data=models.LicenseEvent} "연장 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 409 {object} models.APIResponse "폐기된 라이선스"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/extend [post]
+func ExtendLicense(w http.ResponseWriter, r *http.Request) {
+	var req models.ExtendLicenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	if (req.Days > 0) == (strings.TrimSpace(req.ExpiresAt) != "") || req.Days < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Specify either a positive days value or expires_at", nil))
+		return
+	}
+
+	changeLicenseTerm(w, r, models.LicenseEventExtended, req.Reason, req.OrderRef, nil, nil,
+		func(current time.Time, _ time.Time) (time.Time, error) {
+			if req.ExpiresAt == "" {
+				return current.AddDate(0, 0, req.Days), nil
+			}
+			next, err := parseTermDate(req.ExpiresAt)
+			if err == nil && !next.After(current) {
+				return next, fmt.Errorf("expires_at must be later than the current expiration date")
+			}
+			return next, err
+		})
+}
+
+// changeLicenseTerm은 갱신/연장의 공통 처리입니다. 새 만료일을 계산해 라이선스를 갱신하고,
+// 만료·유예 상태이면 다시 활성화한 뒤 변경 내용을 같은 트랜잭션에서 이력으로 기록합니다.
+func changeLicenseTerm(w http.ResponseWriter, r *http.Request, eventType, reason, orderRef string,
+	maxDevices *int, policyID *string, nextExpiry func(current, today time.Time) (time.Time, error)) {
+	id := licenseIDFromRequest(r)
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License ID is required", nil))
+		return
+	}
+	if !authorizeLicenseAccess(w, r, id) {
+		return
+	}
+
+	if policyID != nil && *policyID != "" {
+		var policyExists int
+		if err := database.DB.QueryRow("SELECT COUNT(*) FROM policies WHERE id = ?", *policyID).Scan(&policyExists); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify policy", err))
+			return
+		}
+		if policyExists == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Policy not found", nil))
+			return
+		}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	// 동시에 들어온 갱신이 같은 만료일을 기준으로 계산하지 않도록 행을 잠급니다.
+	var status string
+	if err := tx.QueryRow("SELECT status FROM licenses WHERE id = ? FOR UPDATE", id).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+	if status == models.LicenseStatusRevoked {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Revoked licenses cannot be renewed or extended", nil))
+		return
+	}
+
+	before, err := utils.LoadLicenseSnapshot(tx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+
+	today, _ := time.Parse("2006-01-02", utils.FormatDateOnly(utils.NowSeoul()))
+	expiresAt, _ := before["expires_at"].(string)
+	current, err := time.Parse("2006-01-02", expiresAt)
+	if err != nil {
+		current = today
+	}
+	next, err := nextExpiry(current, today)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid expiration date", err))
+		return
+	}
+	if next.Before(today) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Expiration date cannot be in the past", nil))
+		return
+	}
+
+	if maxDevices != nil {
+		var activeCount int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM device_activations WHERE license_id = ? AND status = ?",
+			id, models.DeviceStatusActive).Scan(&activeCount); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to check active devices", err))
+			return
+		}
+		if *maxDevices < activeCount {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse(
+				fmt.Sprintf("Cannot reduce max devices to %d. Currently %d devices are active. Please deactivate devices first.",
+					*maxDevices, activeCount),
+				nil))
+			return
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE licenses SET expires_at = ?,
+		max_devices = COALESCE(?, max_devices),
+		policy_id = CASE WHEN ? IS NULL THEN policy_id ELSE NULLIF(?, '') END,
+		updated_at = ?
+		WHERE id = ?`,
+		next.Format("2006-01-02"), maxDevices, policyID, policyID,
+		time.Now().Format("2006-01-02 15:04:05"), id,
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update license", err))
+		return
+	}
+
+	// 스케줄러가 만료/유예 처리한 라이선스는 새 만료일로 다시 활성화합니다.
+	// 일시 정지된 라이선스는 기간만 바뀌고, 재개는 별도로 처리합니다.
+	if status == models.LicenseStatusExpired || status == models.LicenseStatusGrace {
+		if err := utils.TransitionLicenseStatus(tx, id, utils.LicenseTransition{From: status, To: models.LicenseStatusActive}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to reactivate license", err))
+			return
+		}
+	}
+
+	after, err := utils.LoadLicenseSnapshot(tx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	event := models.LicenseEvent{
+		LicenseID: id,
+		EventType: eventType,
+		Changes:   utils.DiffLicenseSnapshots(before, after),
+		Reason:    strings.TrimSpace(reason),
+		ActorID:   actorID,
+		ActorName: actorName,
+	}
+	if ref := strings.TrimSpace(orderRef); ref != "" {
+		event.OrderRef = &ref
+	}
+	if err := utils.RecordLicenseEvent(tx, event); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record license history", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update license", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"license_id": id,
+		"event":      eventType,
+		"expires_at": after["expires_at"],
+		"status":     after["status"],
+		"order_ref":  orderRef,
+	}).Info("License term changed")
+
+	action := models.AdminActionRenewLicense
+	message := "License renewed"
+	if eventType == models.LicenseEventExtended {
+		action = models.AdminActionExtendLicense
+		message = "License extended"
+	}
+	details := fmt.Sprintf("라이선스 ID: %s, 만료일: %v → %v", id, before["expires_at"], after["expires_at"])
+	if event.OrderRef != nil {
+		details += ", 주문: " + *event.OrderRef
+	}
+	utils.LogAdminActivity(actorID, actorName, action, details)
+
+	event.CreatedAt = utils.FormatDateTimeForDB(utils.NowSeoul())
+	json.NewEncoder(w).Encode(models.SuccessResponse(message, event))
+}
+
+// recordLicenseChange는 변경 전 스냅샷과 현재 값을 비교해 이력을 남깁니다. 이력 기록 실패는 요청을 실패시키지 않습니다.
+func recordLicenseChange(r *http.Request, licenseID, eventType string, before utils.LicenseSnapshot, reason, orderRef string) {
+	if err := recordLicenseChangeTx(database.DB, r, licenseID, eventType, before, reason, orderRef); err != nil {
+		logger.Error("Failed to record license history: %v", err)
+	}
+}
+
+// recordLicenseChangeTx는 recordLicenseChange와 같지만 주어진 트랜잭션에서 기록하고 실패를 반환합니다.
+// 변경과 이력이 함께 커밋되어야 하는 경우에 사용합니다.
+func recordLicenseChangeTx(db utils.QueryExecer, r *http.Request, licenseID, eventType string, before utils.LicenseSnapshot, reason, orderRef string) error {
+	after, err := utils.LoadLicenseSnapshot(db, licenseID)
+	if err != nil {
+		return err
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	event := models.LicenseEvent{
+		LicenseID: licenseID,
+		EventType: eventType,
+		Changes:   utils.DiffLicenseSnapshots(before, after),
+		Reason:    strings.TrimSpace(reason),
+		ActorID:   actorID,
+		ActorName: actorName,
+	}
+	if ref := strings.TrimSpace(orderRef); ref != "" {
+		event.OrderRef = &ref
+	}
+	return utils.RecordLicenseEvent(db, event)
+}
+
+// licenseEventActor는 이력에 기록할 관리자 ID와 이름을 반환합니다. 관리자 요청이 아니면 system입니다.
+func licenseEventActor(r *http.Request) (string, string) {
+	adminID, _ := r.Context().Value("admin_id").(string)
+	username, _ := r.Context().Value("username").(string)
+	if adminID == "" {
+		return "system", "System"
+	}
+	return adminID, username
+}
+
+// parseTermDate는 RFC3339 또는 YYYY-MM-DD 형식의 날짜를 날짜 단위로 읽습니다.
+func parseTermDate(value string) (time.Time, error) {
+	date, err := parseLicenseExpiresAt(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse("2006-01-02", date)
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// productChangeDevice 제품 변경 시 검토하는 활성 디바이스
+type productChangeDevice struct {
+	ID              string  `json:"id"`
+	DeviceName      *string `json:"device_name"`
+	ActivatedAt     string  `json:"activated_at"`
+	LastValidatedAt *string `json:"last_validated_at"`
+}
+
+// ChangeLicenseProduct 라이선스 제품 변경 (업그레이드/다운그레이드)
+// @Summary 라이선스 제품 변경
+// @Description 라이선스 키와 디바이스 활성화를 유지한 채 제품과 정책을 바꿉니다. 새 max_devices를 넘는 활성 디바이스는
+// @Description deactivate_device_ids로 지정하거나 trim_strategy(least_recent, newest)로 자동 선택해 해제하며, 둘 다 없으면 409와 활성 디바이스 목록을 반환합니다.
+// @Description 기능 권한은 새 제품에 같은 feature_key가 있으면 옮기고 없으면 삭제합니다. 응답에는 새 정책 기준으로 다시 계산한 정책 데이터가 포함됩니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.ChangeLicenseProductRequest true "변경할 제품 정보"
+// @Success 200 {object} models.APIResponse "변경 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 409 {object} models.APIResponse "폐기된 라이선스 또는 해제할 디바이스 선택 필요"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/change-product [post]
+func ChangeLicenseProduct(w http.ResponseWriter, r *http.Request) {
+	id := licenseIDFromRequest(r)
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License ID is required", nil))
+		return
+	}
+	if !authorizeLicenseAccess(w, r, id) {
+		return
+	}
+
+	var req models.ChangeLicenseProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.ProductID = strings.TrimSpace(req.ProductID)
+	req.TrimStrategy = strings.TrimSpace(req.TrimStrategy)
+	if req.ProductID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product ID is required", nil))
+		return
+	}
+	if req.TrimStrategy != "" && req.TrimStrategy != models.DeviceTrimLeastRecent && req.TrimStrategy != models.DeviceTrimNewest {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("trim_strategy must be least_recent or newest", nil))
+		return
+	}
+	if req.MaxDevices != nil && *req.MaxDevices < 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("max_devices must be at least 1", nil))
+		return
+	}
+
+	var productName string
+	if err := database.DB.QueryRow("SELECT name FROM products WHERE id = ? AND status = 'active'", req.ProductID).Scan(&productName); err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Product not found or inactive", nil))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify product", err))
+		return
+	}
+
+	if req.PolicyID != nil && *req.PolicyID != "" {
+		var policyExists int
+		if err := database.DB.QueryRow("SELECT COUNT(*) FROM policies WHERE id = ?", *req.PolicyID).Scan(&policyExists); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify policy", err))
+			return
+		}
+		if policyExists == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Policy not found", nil))
+			return
+		}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	// 동시에 들어온 디바이스 활성화가 새 한도를 넘기지 않도록 라이선스 행을 잠급니다.
+	var (
+		status, licenseType string
+		maxDevices          int
+		oldProductID        sql.NullString
+		oldPolicyID         sql.NullString
+	)
+	err = tx.QueryRow("SELECT status, license_type, max_devices, product_id, policy_id FROM licenses WHERE id = ? FOR UPDATE", id).
+		Scan(&status, &licenseType, &maxDevices, &oldProductID, &oldPolicyID)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+	if status == models.LicenseStatusRevoked {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Revoked licenses cannot change product", nil))
+		return
+	}
+
+	newMaxDevices := maxDevices
+	if req.MaxDevices != nil {
+		newMaxDevices = *req.MaxDevices
+	}
+	newPolicyID := oldPolicyID.String
+	if req.PolicyID != nil {
+		newPolicyID = strings.TrimSpace(*req.PolicyID)
+	}
+
+	before, err := utils.LoadLicenseSnapshot(tx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+
+	// 플로팅 라이선스의 max_devices는 동시 임대 좌석 수이므로 디바이스 활성화를 정리하지 않습니다.
+	var dropped []productChangeDevice
+	if licenseType != models.LicenseTypeFloating || len(req.DeactivateDeviceIDs) > 0 {
+		active, err := loadProductChangeDevices(tx, id, req.TrimStrategy)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to check active devices", err))
+			return
+		}
+
+		excess := 0
+		if licenseType != models.LicenseTypeFloating && len(active) > newMaxDevices {
+			excess = len(active) - newMaxDevices
+		}
+
+		switch {
+		case len(req.DeactivateDeviceIDs) > 0:
+			byID := make(map[string]productChangeDevice, len(active))
+			for _, device := range active {
+				byID[device.ID] = device
+			}
+			for _, deviceID := range req.DeactivateDeviceIDs {
+				device, ok := byID[strings.TrimSpace(deviceID)]
+				if !ok {
+					w.WriteHeader(http.StatusBadRequest)
+					json.NewEncoder(w).Encode(models.ErrorResponse("Device is not an active device of this license: "+deviceID, nil))
+					return
+				}
+				delete(byID, device.ID)
+				dropped = append(dropped, device)
+			}
+		case excess > 0 && req.TrimStrategy != "":
+			// loadProductChangeDevices가 해제 우선순위 순으로 정렬해 반환합니다.
+			dropped = active[:excess]
+		}
+
+		if len(dropped) < excess {
+			resp := models.ErrorResponse(fmt.Sprintf(
+				"%d active devices exceed the new max_devices of %d. Choose devices to deactivate or set trim_strategy.",
+				len(active), newMaxDevices), nil)
+			resp.Data = map[string]interface{}{
+				"max_devices":    newMaxDevices,
+				"active_devices": active,
+				"excess":         excess,
+			}
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+	}
+
+	nowStr := utils.FormatDateTimeForDB(utils.NowSeoul())
+	if _, err := tx.Exec(`UPDATE licenses SET product_id = ?, policy_id = NULLIF(?, ''), max_devices = ?, updated_at = ? WHERE id = ?`,
+		req.ProductID, newPolicyID, newMaxDevices, nowStr, id); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update license", err))
+		return
+	}
+
+	for _, device := range dropped {
+		if _, err := tx.Exec("UPDATE device_activations SET status = ?, deactivated_at = ? WHERE id = ? AND license_id = ?",
+			models.DeviceStatusDeactivated, nowStr, device.ID, id); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to deactivate device", err))
+			return
+		}
+	}
+
+	// 기능 권한은 새 제품에 같은 feature_key가 있으면 옮기고, 없으면 삭제합니다.
+	migrated, err := tx.Exec(`UPDATE license_entitlements e
+		JOIN product_features old ON old.id = e.feature_id
+		JOIN product_features nf ON nf.product_id = ? AND nf.feature_key = old.feature_key
+		SET e.feature_id = nf.id
+		WHERE e.license_id = ? AND old.product_id <> ?`, req.ProductID, id, req.ProductID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to migrate entitlements", err))
+		return
+	}
+	removed, err := tx.Exec(`DELETE e FROM license_entitlements e
+		JOIN product_features f ON f.id = e.feature_id
+		WHERE e.license_id = ? AND f.product_id <> ?`, id, req.ProductID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to migrate entitlements", err))
+		return
+	}
+	migratedCount, _ := migrated.RowsAffected()
+	removedCount, _ := removed.RowsAffected()
+
+	after, err := utils.LoadLicenseSnapshot(tx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+
+	droppedIDs := make([]string, 0, len(dropped))
+	for _, device := range dropped {
+		droppedIDs = append(droppedIDs, device.ID)
+	}
+	changes := utils.DiffLicenseSnapshots(before, after)
+	if len(droppedIDs) > 0 {
+		changes["deactivated_devices"] = models.LicenseFieldChange{Old: nil, New: droppedIDs}
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	event := models.LicenseEvent{
+		LicenseID: id,
+		EventType: models.LicenseEventProductChanged,
+		Changes:   changes,
+		Reason:    strings.TrimSpace(req.Reason),
+		ActorID:   actorID,
+		ActorName: actorName,
+	}
+	if ref := strings.TrimSpace(req.OrderRef); ref != "" {
+		event.OrderRef = &ref
+	}
+	if err := utils.RecordLicenseEvent(tx, event); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record license history", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update license", err))
+		return
+	}
+
+	for _, device := range dropped {
+		utils.LogDeviceActivity(device.ID, id, models.DeviceActionDeactivated, "Deactivated by product change")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"license_id":          id,
+		"from_product":        oldProductID.String,
+		"to_product":          req.ProductID,
+		"max_devices":         newMaxDevices,
+		"deactivated_devices": droppedIDs,
+	}).Info("License product changed")
+
+	details := fmt.Sprintf("라이선스 ID: %s, 제품: %s → %s, 정책: %s → %s, 최대 디바이스: %d → %d",
+		id, displayValue(oldProductID.String), req.ProductID, displayValue(oldPolicyID.String), displayValue(newPolicyID),
+		maxDevices, newMaxDevices)
+	if len(droppedIDs) > 0 {
+		details += ", 해제된 디바이스: " + strings.Join(droppedIDs, ", ")
+	}
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionChangeProduct, details)
+
+	// 클라이언트가 다음 검증에서 받게 될 정책 데이터를 새 정책 기준으로 다시 계산해 돌려줍니다.
+	var policyIDPtr *string
+	if newPolicyID != "" {
+		policyIDPtr = &newPolicyID
+	}
+	var gracePeriodDays, maxOfflineDays *int
+	database.DB.QueryRow("SELECT grace_period_days, max_offline_days FROM licenses WHERE id = ?", id).Scan(&gracePeriodDays, &maxOfflineDays)
+	lifecycle := utils.ResolveLicenseLifecycle(gracePeriodDays, maxOfflineDays, policyIDPtr)
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("License product changed", map[string]interface{}{
+		"id":                   id,
+		"product_id":           req.ProductID,
+		"product_name":         productName,
+		"policy_id":            policyIDPtr,
+		"max_devices":          newMaxDevices,
+		"deactivated_devices":  dropped,
+		"entitlements_moved":   migratedCount,
+		"entitlements_removed": removedCount,
+		"policies":             loadPoliciesForLicense(policyIDPtr),
+		"grace_period_days":    lifecycle.GracePeriodDays,
+		"max_offline_days":     lifecycle.MaxOfflineDays,
+		"changes":              changes,
+	}))
+}
+
+// loadProductChangeDevices 라이선스의 활성 디바이스를 해제 우선순위 순으로 조회합니다.
+// least_recent는 마지막 검증이 오래된 순, newest는 최근 활성화 순, 기본은 활성화 순입니다.
+func loadProductChangeDevices(tx *sql.Tx, licenseID, strategy string) ([]productChangeDevice, error) {
+	order := "activated_at ASC"
+	switch strategy {
+	case models.DeviceTrimLeastRecent:
+		order = "COALESCE(last_validated_at, activated_at) ASC"
+	case models.DeviceTrimNewest:
+		order = "activated_at DESC"
+	}
+
+	rows, err := tx.Query(`SELECT id, device_name, activated_at, last_validated_at
+		FROM device_activations WHERE license_id = ? AND status = ?
+		ORDER BY `+order+` FOR UPDATE`, licenseID, models.DeviceStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := []productChangeDevice{}
+	for rows.Next() {
+		var device productChangeDevice
+		if err := rows.Scan(&device.ID, &device.DeviceName, &device.ActivatedAt, &device.LastValidatedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, rows.Err()
+}
+
+// displayValue 활동 로그에 표시할 값 (빈 값은 "없음")
+func displayValue(value string) string {
+	if value == "" {
+		return "없음"
+	}
+	return value
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// SuspendLicense 라이선스 일시 정지
+// @Summary 라이선스 일시 정지
+// @Description 미납 등으로 라이선스를 일시 정지합니다. 폐기와 달리 재개할 수 있으며, resume_at을 지정하면 그날 스케줄러가 자동으로 재개합니다.
+// @Description 일시 정지된 라이선스의 검증 요청은 403과 함께 suspension_reason 코드를 반환합니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.SuspendLicenseRequest true "일시 정지 정보"
+// @Success 200 {object} models.APIResponse "일시 정지 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 409 {object} models.APIResponse "현재 상태에서 일시 정지 불가"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/suspend [post]
+func SuspendLicense(w http.ResponseWriter, r *http.Request) {
+	var req models.SuspendLicenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if !models.IsValidSuspensionReason(req.Reason) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse(
+			"reason must be one of non_payment, chargeback, policy_violation, customer_request, other", nil))
+		return
+	}
+
+	resumeAt := ""
+	if strings.TrimSpace(req.ResumeAt) != "" {
+		date, err := parseLicenseExpiresAt(req.ResumeAt)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid resume_at date format", err))
+			return
+		}
+		if date <= utils.FormatDateOnly(utils.NowSeoul()) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("resume_at must be a future date", nil))
+			return
+		}
+		resumeAt = date
+	}
+
+	after, ok := transitionLicense(w, r, models.LicenseEventSuspended, req.Note, func(from string) utils.LicenseTransition {
+		return utils.LicenseTransition{
+			From:             from,
+			To:               models.LicenseStatusSuspended,
+			SuspensionReason: req.Reason,
+			ResumeAt:         resumeAt,
+		}
+	})
+	if !ok {
+		return
+	}
+
+	id := licenseIDFromRequest(r)
+	actorID, actorName := licenseEventActor(r)
+	details := fmt.Sprintf("라이선스 ID: %s, 사유: %s", id, req.Reason)
+	if resumeAt != "" {
+		details += ", 자동 재개일: " + resumeAt
+	}
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionSuspendLicense, details)
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("License suspended", map[string]interface{}{
+		"id":                id,
+		"status":            after["status"],
+		"suspension_reason": after["suspension_reason"],
+		"resume_at":         after["resume_at"],
+	}))
+}
+
+// ResumeLicense 일시 정지된 라이선스 재개
+// @Summary 라이선스 재개
+// @Description 일시 정지된 라이선스를 다시 활성화합니다. 만료일이 이미 지났으면 다음 스케줄러 실행 때 유예/만료로 전환됩니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.ResumeLicenseRequest false "재개 메모"
+// @Success 200 {object} models.APIResponse "재개 성공"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 409 {object} models.APIResponse "일시 정지 상태가 아님"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/resume [post]
+func ResumeLicense(w http.ResponseWriter, r *http.Request) {
+	var req models.ResumeLicenseRequest
+	// 본문은 선택사항입니다.
+	json.NewDecoder(r.Body).Decode(&req)
+
+	after, ok := transitionLicense(w, r, models.LicenseEventResumed, req.Note, func(from string) utils.LicenseTransition {
+		if from != models.LicenseStatusSuspended {
+			// 일시 정지 상태가 아니면 규칙 검사에서 거부되도록 그대로 넘깁니다.
+			return utils.LicenseTransition{From: from, To: from}
+		}
+		return utils.LicenseTransition{From: from, To: models.LicenseStatusActive}
+	})
+	if !ok {
+		return
+	}
+
+	id := licenseIDFromRequest(r)
+	actorID, actorName := licenseEventActor(r)
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionResumeLicense, "라이선스 ID: "+id)
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("License resumed", map[string]interface{}{
+		"id":     id,
+		"status": after["status"],
+	}))
+}
+
+// RevokeLicense 라이선스 폐기
+// @Summary 라이선스 폐기
+// @Description 라이선스를 되돌릴 수 없게 폐기합니다. 사유 코드와 설명은 필수이며 관리자 활동 로그와 변경 이력에 기록됩니다.
+// @Description 폐기된 라이선스는 즉시 서명된 폐기 목록(/api/license/revocations)에 포함되어 오프라인 클라이언트에도 전파됩니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.RevokeLicenseRequest true "폐기 사유"
+// @Success 200 {object} models.APIResponse "폐기 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 409 {object} models.APIResponse "이미 폐기됨"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/revoke [post]
+func RevokeLicense(w http.ResponseWriter, r *http.Request) {
+	var req models.RevokeLicenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	req.Comment = strings.TrimSpace(req.Comment)
+	if !models.IsValidRevocationReason(req.Reason) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse(
+			"reason must be one of key_compromise, refund, chargeback, fraud, superseded, customer_request, other", nil))
+		return
+	}
+	if req.Comment == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("comment is required", nil))
+		return
+	}
+
+	after, ok := transitionLicense(w, r, models.LicenseEventRevoked, req.Comment, func(from string) utils.LicenseTransition {
+		return utils.LicenseTransition{
+			From:             from,
+			To:               models.LicenseStatusRevoked,
+			RevocationReason: req.Reason,
+		}
+	})
+	if !ok {
+		return
+	}
+
+	id := licenseIDFromRequest(r)
+	actorID, actorName := licenseEventActor(r)
+	details := fmt.Sprintf("라이선스 ID: %s, 사유: %s, 설명: %s", id, req.Reason, req.Comment)
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionRevokeLicense, details)
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("License revoked successfully", map[string]interface{}{
+		"id":                id,
+		"status":            after["status"],
+		"revocation_reason": after["revocation_reason"],
+	}))
+}
+
+// transitionLicense는 관리자 상태 전환의 공통 처리입니다. 행을 잠근 뒤 상태 전환 규칙에 따라 전환하고
+// 변경 내용을 같은 트랜잭션에서 이력으로 기록합니다. 실패하면 응답을 쓰고 false를 반환합니다.
+func transitionLicense(w http.ResponseWriter, r *http.Request, eventType, note string,
+	build func(from string) utils.LicenseTransition) (utils.LicenseSnapshot, bool) {
+	id := licenseIDFromRequest(r)
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License ID is required", nil))
+		return nil, false
+	}
+	if !authorizeLicenseAccess(w, r, id) {
+		return nil, false
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return nil, false
+	}
+	defer tx.Rollback()
+
+	status, err := utils.CurrentLicenseStatus(tx, id, true)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return nil, false
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return nil, false
+	}
+
+	before, err := utils.LoadLicenseSnapshot(tx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return nil, false
+	}
+
+	transition := build(status)
+	if err := utils.TransitionLicenseStatus(tx, id, transition); err != nil {
+		if errors.Is(err, utils.ErrInvalidLicenseTransition) {
+			resp := models.ErrorResponse(fmt.Sprintf("Cannot change license status from %s", status), nil)
+			resp.Data = map[string]interface{}{"status": status}
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(resp)
+			return nil, false
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update license status", err))
+		return nil, false
+	}
+
+	after, err := utils.LoadLicenseSnapshot(tx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return nil, false
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	if err := utils.RecordLicenseEvent(tx, models.LicenseEvent{
+		LicenseID: id,
+		EventType: eventType,
+		Changes:   utils.DiffLicenseSnapshots(before, after),
+		Reason:    strings.TrimSpace(note),
+		ActorID:   actorID,
+		ActorName: actorName,
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record license history", err))
+		return nil, false
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update license status", err))
+		return nil, false
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"license_id": id,
+		"from":       status,
+		"to":         transition.To,
+	}).Info("License status changed")
+
+	return after, true
+}
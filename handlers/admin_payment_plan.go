@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// GetPaymentPlans 결제 플랜 매핑 목록
+// @Summary 결제 플랜 매핑 목록
+// @Description 결제 웹훅의 플랜 코드별로 발급할 제품, 정책, 디바이스 수, 이용 기간을 조회합니다
+// @Tags 관리자 - 결제 연동
+// @Produce json
+// @Security BearerAuth
+// @Param provider query string false "결제 서비스 이름으로 필터"
+// @Success 200 {object} models.APIResponse{data=[]models.PaymentPlan} "조회 성공"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/payment-plans [get]
+func GetPaymentPlans(w http.ResponseWriter, r *http.Request) {
+	query := `SELECT pp.id, pp.provider, pp.plan_code, pp.product_id, COALESCE(p.name, ''), pp.policy_id,
+		pp.license_type, pp.max_devices, pp.duration_days, pp.created_at, pp.updated_at
+		FROM payment_plans pp
+		LEFT JOIN products p ON p.id = pp.product_id`
+	args := []interface{}{}
+	if provider := strings.TrimSpace(r.URL.Query().Get("provider")); provider != "" {
+		query += " WHERE pp.provider = ?"
+		args = append(args, strings.ToLower(provider))
+	}
+	query += " ORDER BY pp.provider, pp.plan_code"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query payment plans", err))
+		return
+	}
+	defer rows.Close()
+
+	plans := []models.PaymentPlan{}
+	for rows.Next() {
+		var plan models.PaymentPlan
+		if err := rows.Scan(&plan.ID, &plan.Provider, &plan.PlanCode, &plan.ProductID, &plan.ProductName, &plan.PolicyID,
+			&plan.LicenseType, &plan.MaxDevices, &plan.DurationDays, &plan.CreatedAt, &plan.UpdatedAt); err != nil {
+			continue
+		}
+		plans = append(plans, plan)
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Payment plans retrieved", plans))
+}
+
+// SavePaymentPlan 결제 플랜 매핑 생성/수정
+// @Summary 결제 플랜 매핑 저장
+// @Description 결제 서비스의 플랜 코드에 발급할 라이선스 조건을 연결합니다. 같은 provider, plan_code가 있으면 덮어씁니다.
+// @Tags 관리자 - 결제 연동
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SavePaymentPlanRequest true "플랜 매핑"
+// @Success 200 {object} models.APIResponse{data=models.PaymentPlan} "저장 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/payment-plans [post]
+func SavePaymentPlan(w http.ResponseWriter, r *http.Request) {
+	var req models.SavePaymentPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.Provider = strings.ToLower(strings.TrimSpace(req.Provider))
+	req.PlanCode = strings.TrimSpace(req.PlanCode)
+	req.ProductID = strings.TrimSpace(req.ProductID)
+	req.PolicyID = strings.TrimSpace(req.PolicyID)
+	req.LicenseType = strings.TrimSpace(req.LicenseType)
+	if req.Provider == "" || req.PlanCode == "" || req.ProductID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("provider, plan_code and product_id are required", nil))
+		return
+	}
+	if req.LicenseType == "" {
+		req.LicenseType = models.LicenseTypeNodeLocked
+	}
+	if !models.IsValidLicenseType(req.LicenseType) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("license_type must be node_locked or floating", nil))
+		return
+	}
+	if req.MaxDevices == 0 {
+		req.MaxDevices = 1
+	}
+	if req.MaxDevices < 1 || req.DurationDays < 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("max_devices and duration_days must be at least 1", nil))
+		return
+	}
+
+	var productExists int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM products WHERE id = ?", req.ProductID).Scan(&productExists); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify product", err))
+		return
+	}
+	if productExists == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product not found", nil))
+		return
+	}
+
+	var policyID *string
+	if req.PolicyID != "" {
+		var policyExists int
+		if err := database.DB.QueryRow("SELECT COUNT(*) FROM policies WHERE id = ?", req.PolicyID).Scan(&policyExists); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify policy", err))
+			return
+		}
+		if policyExists == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Policy not found", nil))
+			return
+		}
+		policyID = &req.PolicyID
+	}
+
+	id, err := utils.GenerateID("plan")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate ID", err))
+		return
+	}
+	now := utils.FormatDateTimeForDB(utils.NowSeoul())
+	if _, err := database.DB.Exec(`INSERT INTO payment_plans
+		(id, provider, plan_code, product_id, policy_id, license_type, max_devices, duration_days, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE product_id = VALUES(product_id), policy_id = VALUES(policy_id),
+			license_type = VALUES(license_type), max_devices = VALUES(max_devices),
+			duration_days = VALUES(duration_days), updated_at = VALUES(updated_at)`,
+		id, req.Provider, req.PlanCode, req.ProductID, policyID, req.LicenseType, req.MaxDevices, req.DurationDays, now, now,
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to save payment plan", err))
+		return
+	}
+
+	plan, err := loadPaymentPlan(database.DB, req.Provider, req.PlanCode)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load payment plan", err))
+		return
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionSavePaymentPlan,
+		fmt.Sprintf("결제 플랜: %s/%s → 제품 %s, %d일", plan.Provider, plan.PlanCode, plan.ProductID, plan.DurationDays))
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Payment plan saved", plan))
+}
+
+// DeletePaymentPlan 결제 플랜 매핑 삭제
+// @Summary 결제 플랜 매핑 삭제
+// @Description 플랜 매핑을 삭제합니다. 이미 발급된 라이선스는 그대로 유지되며, 이후 해당 플랜의 주문 웹훅은 처리되지 않습니다.
+// @Tags 관리자 - 결제 연동
+// @Produce json
+// @Security BearerAuth
+// @Param id query string true "플랜 매핑 ID"
+// @Success 200 {object} models.APIResponse "삭제 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "매핑 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/payment-plans [delete]
+func DeletePaymentPlan(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Payment plan ID is required", nil))
+		return
+	}
+
+	result, err := database.DB.Exec("DELETE FROM payment_plans WHERE id = ?", id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to delete payment plan", err))
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Payment plan not found", nil))
+		return
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionDeletePaymentPlan, "결제 플랜 ID: "+id)
+	logger.WithFields(map[string]interface{}{
+		"admin_id": actorID,
+		"plan_id":  id,
+	}).Info("Payment plan deleted")
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Payment plan deleted", nil))
+}
+
+// loadPaymentPlan 결제 서비스와 플랜 코드로 매핑을 조회합니다. 없으면 sql.ErrNoRows를 반환합니다.
+func loadPaymentPlan(db utils.QueryExecer, provider, planCode string) (models.PaymentPlan, error) {
+	var plan models.PaymentPlan
+	err := db.QueryRow(`SELECT pp.id, pp.provider, pp.plan_code, pp.product_id, COALESCE(p.name, ''), pp.policy_id,
+		pp.license_type, pp.max_devices, pp.duration_days, pp.created_at, pp.updated_at
+		FROM payment_plans pp
+		LEFT JOIN products p ON p.id = pp.product_id
+		WHERE pp.provider = ? AND pp.plan_code = ?`, provider, planCode,
+	).Scan(&plan.ID, &plan.Provider, &plan.PlanCode, &plan.ProductID, &plan.ProductName, &plan.PolicyID,
+		&plan.LicenseType, &plan.MaxDevices, &plan.DurationDays, &plan.CreatedAt, &plan.UpdatedAt)
+	return plan, err
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// ListProductReleases 제품 릴리스 목록
+// @Summary 제품 릴리스 목록
+// @Description 제품에 등록된 출시 버전을 버전이 낮은 순으로 조회합니다
+// @Tags 관리자 - 제품 릴리스
+// @Produce json
+// @Security BearerAuth
+// @Param product_id query string true "제품 ID"
+// @Param channel query string false "릴리스 채널 (stable, beta, internal)"
+// @Success 200 {object} models.APIResponse{data=[]models.ProductRelease} "조회 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/product-releases [get]
+func ListProductReleases(w http.ResponseWriter, r *http.Request) {
+	productID := strings.TrimSpace(r.URL.Query().Get("product_id"))
+	if productID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("product_id is required", nil))
+		return
+	}
+	channel := strings.TrimSpace(r.URL.Query().Get("channel"))
+	if channel != "" && !models.IsValidReleaseChannel(channel) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("channel must be stable, beta or internal", nil))
+		return
+	}
+
+	releases, err := utils.LoadProductReleases(productID, channel)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query product releases", err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Product releases retrieved", releases))
+}
+
+// CreateProductRelease 제품 릴리스 등록
+// @Summary 제품 릴리스 등록
+// @Description 제품의 출시 버전을 등록합니다. 유지보수 종료일(maintenance_until)이 있는 라이선스는 그날까지 출시된 버전만 사용할 수 있습니다.
+// @Tags 관리자 - 제품 릴리스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateProductReleaseRequest true "릴리스 정보"
+// @Success 201 {object} models.APIResponse{data=models.ProductRelease} "등록 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 409 {object} models.APIResponse "중복 버전"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/product-releases [post]
+func CreateProductRelease(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateProductReleaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.ProductID = strings.TrimSpace(req.ProductID)
+	req.Channel = strings.ToLower(strings.TrimSpace(req.Channel))
+	if req.ProductID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("product_id is required", nil))
+		return
+	}
+	version, err := utils.ParseSemver(req.Version)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("version must be a semantic version (e.g. 2.3.0)", err))
+		return
+	}
+	releaseDate, err := parseLicenseExpiresAt(strings.TrimSpace(req.ReleaseDate))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid release date format", err))
+		return
+	}
+	if req.Channel == "" {
+		req.Channel = models.ReleaseChannelStable
+	}
+	if !models.IsValidReleaseChannel(req.Channel) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("channel must be stable, beta or internal", nil))
+		return
+	}
+
+	var productCount int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM products WHERE id = ?", req.ProductID).Scan(&productCount); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify product", err))
+		return
+	}
+	if productCount == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product not found", nil))
+		return
+	}
+
+	id, err := utils.GenerateID("rel")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate ID", err))
+		return
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	now := utils.FormatDateTimeForDB(utils.NowSeoul())
+	release := models.ProductRelease{
+		ID:          id,
+		ProductID:   req.ProductID,
+		Version:     version.String(),
+		ReleaseDate: releaseDate,
+		Channel:     req.Channel,
+		Mandatory:   req.Mandatory,
+		Notes:       req.Notes,
+		CreatedBy:   actorID,
+		CreatedAt:   now,
+	}
+	if _, err := database.DB.Exec(`INSERT INTO product_releases (id, product_id, version, release_date, channel, mandatory, notes, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		release.ID, release.ProductID, release.Version, release.ReleaseDate, release.Channel, release.Mandatory, release.Notes, release.CreatedBy, release.CreatedAt,
+	); err != nil {
+		if strings.Contains(err.Error(), "Duplicate") {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Version already exists for this product", nil))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create product release", err))
+		return
+	}
+
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionCreateRelease,
+		fmt.Sprintf("Product release created: %s %s (%s, %s)", release.ProductID, release.Version, release.Channel, release.ReleaseDate))
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.SuccessResponse("Product release created", release))
+}
+
+// DeleteProductRelease 제품 릴리스 삭제
+// @Summary 제품 릴리스 삭제
+// @Description 출시 버전을 삭제합니다. 삭제된 버전은 더 이상 버전 권한 판단에 사용되지 않습니다.
+// @Tags 관리자 - 제품 릴리스
+// @Produce json
+// @Security BearerAuth
+// @Param id query string true "릴리스 ID"
+// @Success 200 {object} models.APIResponse "삭제 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "릴리스 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/product-releases [delete]
+func DeleteProductRelease(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Release ID is required", nil))
+		return
+	}
+
+	result, err := database.DB.Exec("DELETE FROM product_releases WHERE id = ?", id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to delete product release", err))
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product release not found", nil))
+		return
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionDeleteRelease, "Product release deleted: "+id)
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Product release deleted", nil))
+}
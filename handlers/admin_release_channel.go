@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// SetLicenseReleaseChannel 라이선스 릴리스 채널 지정
+// @Summary 라이선스 릴리스 채널 지정
+// @Description 라이선스의 모든 디바이스가 업데이트를 받을 채널(stable, beta, internal)을 지정합니다. 빈 문자열이면 지정을 해제하고 클라이언트가 요청한 채널을 사용합니다.
+// @Description 디바이스에 따로 지정된 채널이 있으면 디바이스 채널이 우선합니다.
+// @Tags 관리자 - 라이선스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param request body models.SetReleaseChannelRequest true "릴리스 채널"
+// @Success 200 {object} models.APIResponse "지정 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/release-channel [post]
+func SetLicenseReleaseChannel(w http.ResponseWriter, r *http.Request) {
+	id := licenseIDFromRequest(r)
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License ID is required", nil))
+		return
+	}
+	if !authorizeLicenseAccess(w, r, id) {
+		return
+	}
+
+	var req models.SetReleaseChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+	channel := strings.ToLower(strings.TrimSpace(req.Channel))
+	if channel != "" && !models.IsValidReleaseChannel(channel) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("channel must be stable, beta or internal", nil))
+		return
+	}
+
+	before, err := utils.LoadLicenseSnapshot(database.DB, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+
+	if _, err := database.DB.Exec("UPDATE licenses SET release_channel = ?, updated_at = ? WHERE id = ?",
+		nullIfEmpty(channel), utils.FormatDateTimeForDB(utils.NowSeoul()), id); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update release channel", err))
+		return
+	}
+
+	recordLicenseChange(r, id, models.LicenseEventUpdated, before, "", "")
+
+	actorID, actorName := licenseEventActor(r)
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionSetReleaseChannel,
+		fmt.Sprintf("License release channel set: %s (%s)", id, displayValue(channel)))
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Release channel updated", map[string]interface{}{
+		"license_id":      id,
+		"release_channel": nullIfEmpty(channel),
+	}))
+}
+
+// SetDeviceReleaseChannel 디바이스 릴리스 채널 지정
+// @Summary 디바이스 릴리스 채널 지정
+// @Description 특정 디바이스만 다른 채널(stable, beta, internal)로 업데이트를 받도록 지정합니다. 빈 문자열이면 라이선스의 채널을 따릅니다.
+// @Tags 관리자 - 디바이스
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SetDeviceReleaseChannelRequest true "디바이스 ID와 릴리스 채널"
+// @Success 200 {object} models.APIResponse "지정 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "디바이스 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/devices/release-channel [post]
+func SetDeviceReleaseChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.SetDeviceReleaseChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.DeviceID = strings.TrimSpace(req.DeviceID)
+	channel := strings.ToLower(strings.TrimSpace(req.Channel))
+	if req.DeviceID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Device ID is required", nil))
+		return
+	}
+	if channel != "" && !models.IsValidReleaseChannel(channel) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("channel must be stable, beta or internal", nil))
+		return
+	}
+
+	var licenseID string
+	err := database.DB.QueryRow("SELECT license_id FROM device_activations WHERE id = ?", req.DeviceID).Scan(&licenseID)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Device not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load device", err))
+		return
+	}
+	if !authorizeLicenseAccess(w, r, licenseID) {
+		return
+	}
+
+	if _, err := database.DB.Exec("UPDATE device_activations SET release_channel = ? WHERE id = ?", nullIfEmpty(channel), req.DeviceID); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update release channel", err))
+		return
+	}
+
+	actorID, actorName := licenseEventActor(r)
+	utils.LogDeviceActivity(req.DeviceID, licenseID, models.DeviceActionReleaseChannelChanged,
+		"Release channel set to "+displayValue(channel))
+	utils.LogAdminActivity(actorID, actorName, models.AdminActionSetReleaseChannel,
+		fmt.Sprintf("Device release channel set: %s (%s)", req.DeviceID, displayValue(channel)))
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": r.Context().Value("request_id"),
+		"device_id":  req.DeviceID,
+		"license_id": licenseID,
+		"channel":    channel,
+	}).Info("Device release channel updated")
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Release channel updated", map[string]interface{}{
+		"device_id":       req.DeviceID,
+		"license_id":      licenseID,
+		"release_channel": nullIfEmpty(channel),
+	}))
+}
+
+// assignedReleaseChannel 디바이스에 지정된 채널, 없으면 라이선스에 지정된 채널을 반환합니다. 둘 다 없으면 빈 문자열입니다.
+// deviceID가 비어 있으면(플로팅 라이선스) 라이선스 채널만 확인합니다.
+func assignedReleaseChannel(licenseID, deviceID string) string {
+	var channel sql.NullString
+	err := database.DB.QueryRow(`SELECT COALESCE(d.release_channel, l.release_channel)
+		FROM licenses l
+		LEFT JOIN device_activations d ON d.id = ? AND d.license_id = l.id
+		WHERE l.id = ?`, deviceID, licenseID).Scan(&channel)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"license_id": licenseID,
+			"device_id":  deviceID,
+			"error":      err.Error(),
+		}).Warn("Failed to load assigned release channel")
+		return ""
+	}
+	return stringIfValid(channel)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// GetLicenseUsage 라이선스 사용량 조회
+// @Summary 라이선스 사용량 조회
+// @Description 적용 중인 사용량 한도, 현재 기간 사용량, 기간별 집계 이력을 조회합니다
+// @Tags 관리자 - 사용량
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Success 200 {object} models.APIResponse "조회 성공"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/usage [get]
+func GetLicenseUsage(w http.ResponseWriter, r *http.Request) {
+	licenseID := licenseIDFromRequest(r)
+	if !authorizeLicenseAccess(w, r, licenseID) {
+		return
+	}
+
+	var (
+		policyID    sql.NullString
+		usageQuotas sql.NullString
+	)
+	if err := database.DB.QueryRow("SELECT policy_id, usage_quotas FROM licenses WHERE id = ?", licenseID).
+		Scan(&policyID, &usageQuotas); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return
+	}
+	quotas := utils.ResolveUsageQuotas(nullStringPtr(usageQuotas), nullStringPtr(policyID))
+
+	rows, err := database.DB.Query(`SELECT metric, period_key, used, updated_at
+		FROM usage_counters WHERE license_id = ?
+		ORDER BY period_key DESC, metric LIMIT 500`, licenseID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query usage", err))
+		return
+	}
+	defer rows.Close()
+
+	history := []map[string]interface{}{}
+	for rows.Next() {
+		var (
+			metric, periodKey, updatedAt string
+			used                         int64
+		)
+		if err := rows.Scan(&metric, &periodKey, &used, &updatedAt); err != nil {
+			logger.Warn("Failed to scan usage counter: %v", err)
+			continue
+		}
+		history = append(history, map[string]interface{}{
+			"metric":     metric,
+			"period_key": periodKey,
+			"used":       used,
+			"updated_at": updatedAt,
+		})
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Usage retrieved", map[string]interface{}{
+		"license_id": licenseID,
+		"quotas":     quotas,
+		"current":    loadUsageForLicense(licenseID, quotas),
+		"history":    history,
+	}))
+}
+
+// ResetLicenseUsage 라이선스 사용량 초기화
+// @Summary 라이선스 사용량 초기화
+// @Description 현재 기간(또는 지정한 기간)의 사용량 집계를 초기화합니다. 이벤트 이력은 중복 방지를 위해 유지됩니다.
+// @Tags 관리자 - 사용량
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "라이선스 ID"
+// @Param metric query string false "측정 항목 (생략 시 전체)"
+// @Param period_key query string false "기간 키 (예: 2025-01, 생략 시 현재 기간)"
+// @Success 200 {object} models.APIResponse "초기화 성공"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "라이선스 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/licenses/{id}/usage [delete]
+func ResetLicenseUsage(w http.ResponseWriter, r *http.Request) {
+	licenseID := licenseIDFromRequest(r)
+	if !authorizeLicenseAccess(w, r, licenseID) {
+		return
+	}
+
+	query := "DELETE FROM usage_counters WHERE license_id = ?"
+	args := []interface{}{licenseID}
+
+	metric := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("metric")))
+	if metric != "" {
+		query += " AND metric = ?"
+		args = append(args, metric)
+	}
+
+	// 기간 키 형식이 주기별로 달라 현재 일/월/전체 기간 키를 함께 지정하면 현재 기간만 초기화됩니다.
+	if periodKey := strings.TrimSpace(r.URL.Query().Get("period_key")); periodKey != "" {
+		query += " AND period_key = ?"
+		args = append(args, periodKey)
+	} else {
+		now := utils.NowSeoul()
+		query += " AND period_key IN (?, ?, ?)"
+		args = append(args,
+			utils.UsagePeriodKey(models.UsagePeriodDaily, now),
+			utils.UsagePeriodKey(models.UsagePeriodMonthly, now),
+			utils.UsagePeriodKey(models.UsagePeriodLifetime, now),
+		)
+	}
+
+	result, err := database.DB.Exec(query, args...)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to reset usage", err))
+		return
+	}
+	affected, _ := result.RowsAffected()
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		target := metric
+		if target == "" {
+			target = "all metrics"
+		}
+		utils.LogAdminActivity(adminID, username, models.AdminActionResetUsage,
+			fmt.Sprintf("Usage reset: %s (%s, %d counters)", licenseID, target, affected))
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Usage reset", map[string]interface{}{
+		"license_id":     licenseID,
+		"reset_counters": affected,
+	}))
+}
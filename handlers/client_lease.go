@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// leaseTTL은 클라이언트가 요청한 TTL을 서버 설정 범위로 보정합니다.
+// FLOATING_LEASE_TTL_SECONDS(기본 300초)가 기본값이자 최대값입니다.
+func leaseTTL(requested int) time.Duration {
+	maxTTL := utils.GetEnvInt("FLOATING_LEASE_TTL_SECONDS", 300)
+	if maxTTL < 30 {
+		maxTTL = 30
+	}
+	ttl := maxTTL
+	if requested >= 30 && requested < maxTTL {
+		ttl = requested
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+// leaseLicense는 임대 처리에 필요한 라이선스 정보를 조회하고 사용 가능 여부를 확인합니다.
+// 사용할 수 없으면 응답을 작성하고 ok=false를 반환합니다.
+func leaseLicense(w http.ResponseWriter, q utils.QueryExecer, licenseKey string, forUpdate bool) (license models.License, ok bool) {
+	query := `SELECT id, license_key, policy_id, license_type, max_devices, expires_at, status,
+		grace_period_days, max_offline_days
+		FROM licenses WHERE license_key = ?`
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	err := q.QueryRow(query, utils.LicenseKeyLookup(licenseKey)).Scan(
+		&license.ID, &license.LicenseKey, &license.PolicyID, &license.LicenseType,
+		&license.MaxDevices, &license.ExpiresAt, &license.Status,
+		&license.GracePeriodDays, &license.MaxOfflineDays,
+	)
+	license.LicenseKey = licenseKey
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return license, false
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query license", err))
+		return license, false
+	}
+
+	if license.LicenseType != models.LicenseTypeFloating {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License is not a floating license", nil))
+		return license, false
+	}
+	startLicenseIfDue(q, &license)
+	// 만료일이 지났더라도 유예 기간 내이면 좌석 임대와 갱신을 허용합니다.
+	if !licenseUsable(license) {
+		if license.Status == models.LicenseStatusActive || license.Status == models.LicenseStatusGrace {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(models.ErrorResponse("License has expired", nil))
+			return license, false
+		}
+		resp := models.ErrorResponse(inactiveLicenseMessage(license.Status), nil)
+		resp.Data = inactiveLicenseData(license.ID, license.Status)
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(resp)
+		return license, false
+	}
+	return license, true
+}
+
+// licenseGrace는 유예 기간 중인 라이선스의 유예 종료 시각과 남은 초를 반환합니다. 유예 중이 아니면 nil입니다.
+func licenseGrace(license models.License) (*string, *int64) {
+	if !license.IsExpired() {
+		return nil, nil
+	}
+	lifecycle := utils.ResolveLicenseLifecycle(license.GracePeriodDays, license.MaxOfflineDays, license.PolicyID)
+	endsAt, err := lifecycle.GraceEndsAt(license.ExpiresAt)
+	if err != nil {
+		return nil, nil
+	}
+	formatted := endsAt.Format(time.RFC3339)
+	remaining := int64(time.Until(endsAt).Seconds())
+	return &formatted, &remaining
+}
+
+// CheckoutLease는 플로팅 라이선스의 좌석을 임대합니다.
+// 같은 디바이스에 유효한 임대가 있으면 새 좌석을 쓰지 않고 기존 임대를 연장합니다.
+// @Summary 플로팅 라이선스 좌석 임대
+// @Description 동시 사용 라이선스에서 TTL이 있는 좌석을 임대합니다. 만료 전 renew로 연장해야 합니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Produce json
+// @Param request body models.LeaseCheckoutRequest true "임대 요청 본문"
+// @Success 201 {object} models.APIResponse{data=models.LicenseLease} "임대 성공"
+// @Success 200 {object} models.APIResponse{data=models.LicenseLease} "기존 임대 연장"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "라이선스 비활성/만료(유예 기간 종료)"
+// @Failure 404 {object} models.APIResponse "라이선스를 찾을 수 없음"
+// @Failure 409 {object} models.APIResponse "플로팅 라이선스가 아니거나 사용 가능한 좌석 없음"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/lease/checkout [post]
+func CheckoutLease(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("request_id")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.LeaseCheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.LicenseKey) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+	if keyErr := normalizeLicenseKey(requestID, &req.LicenseKey); keyErr != nil {
+		keyErr.write(w)
+		return
+	}
+
+	fingerprint := utils.GenerateDeviceFingerprint(
+		req.DeviceInfo.ClientID,
+		req.DeviceInfo.CPUID,
+		req.DeviceInfo.MotherboardSN,
+		req.DeviceInfo.MACAddress,
+		req.DeviceInfo.DiskSerial,
+		req.DeviceInfo.MachineID,
+	)
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	// 라이선스 행을 잠가 동시 임대 요청 간 좌석 수 계산이 겹치지 않도록 합니다.
+	license, ok := leaseLicense(w, tx, req.LicenseKey, true)
+	if !ok {
+		return
+	}
+
+	now := utils.NowSeoul()
+	nowStr := utils.FormatDateTimeForDB(now)
+	expiresAt := utils.FormatDateTimeForDB(now.Add(leaseTTL(req.TTLSeconds)))
+
+	var existing models.LicenseLease
+	err = tx.QueryRow(`SELECT id, checked_out_at FROM license_leases
+		WHERE license_id = ? AND device_fingerprint = ? AND status = ? AND expires_at > ?
+		ORDER BY checked_out_at DESC LIMIT 1`,
+		license.ID, fingerprint, models.LeaseStatusActive, nowStr,
+	).Scan(&existing.ID, &existing.CheckedOutAt)
+	if err != nil && err != sql.ErrNoRows {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query leases", err))
+		return
+	}
+
+	if err == nil {
+		if _, err := tx.Exec(`UPDATE license_leases SET last_heartbeat_at = ?, expires_at = ? WHERE id = ?`,
+			nowStr, expiresAt, existing.ID); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to renew lease", err))
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to renew lease", err))
+			return
+		}
+
+		existing.LicenseID = license.ID
+		existing.DeviceFingerprint = fingerprint
+		existing.DeviceName = req.DeviceInfo.Hostname
+		existing.Status = models.LeaseStatusActive
+		existing.LastHeartbeatAt = nowStr
+		existing.ExpiresAt = expiresAt
+		existing.GraceEndsAt, existing.GraceRemainingSeconds = licenseGrace(license)
+		json.NewEncoder(w).Encode(models.SuccessResponse("Lease renewed", existing))
+		return
+	}
+
+	var inUse int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM license_leases WHERE license_id = ? AND status = ? AND expires_at > ?`,
+		license.ID, models.LeaseStatusActive, nowStr).Scan(&inUse); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to count leases", err))
+		return
+	}
+
+	if inUse >= license.MaxDevices {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"license_id": license.ID,
+			"in_use":     inUse,
+			"max_seats":  license.MaxDevices,
+		}).Warn("No floating seats available")
+
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse("No floating seats available", nil))
+		return
+	}
+
+	leaseID, err := utils.GenerateID("lease")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate lease ID", err))
+		return
+	}
+
+	if _, err := tx.Exec(`INSERT INTO license_leases
+		(id, license_id, device_fingerprint, device_name, status, checked_out_at, last_heartbeat_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		leaseID, license.ID, fingerprint, req.DeviceInfo.Hostname, models.LeaseStatusActive, nowStr, nowStr, expiresAt,
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to checkout lease", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to checkout lease", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":  requestID,
+		"license_id":  license.ID,
+		"lease_id":    leaseID,
+		"device_name": req.DeviceInfo.Hostname,
+	}).Info("Floating lease checked out")
+
+	lease := models.LicenseLease{
+		ID:                leaseID,
+		LicenseID:         license.ID,
+		DeviceFingerprint: fingerprint,
+		DeviceName:        req.DeviceInfo.Hostname,
+		Status:            models.LeaseStatusActive,
+		CheckedOutAt:      nowStr,
+		LastHeartbeatAt:   nowStr,
+		ExpiresAt:         expiresAt,
+	}
+	lease.GraceEndsAt, lease.GraceRemainingSeconds = licenseGrace(license)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.SuccessResponse("Lease checked out", lease))
+}
+
+// RenewLease는 heartbeat로 임대 만료 시각을 연장합니다.
+// @Summary 플로팅 라이선스 임대 갱신
+// @Description 활성 임대의 만료 시각을 연장합니다. 이미 만료·반납된 임대는 다시 checkout 해야 합니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Produce json
+// @Param request body models.LeaseRenewRequest true "갱신 요청 본문"
+// @Success 200 {object} models.APIResponse "갱신 성공 (유예 기간 중이면 grace_ends_at, grace_remaining_seconds 포함)"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "라이선스 비활성/만료(유예 기간 종료)"
+// @Failure 404 {object} models.APIResponse "라이선스 또는 활성 임대 없음"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/lease/renew [post]
+func RenewLease(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.LeaseRenewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.LicenseKey == "" || req.LeaseID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+	if keyErr := normalizeLicenseKey(r.Context().Value("request_id"), &req.LicenseKey); keyErr != nil {
+		keyErr.write(w)
+		return
+	}
+
+	license, ok := leaseLicense(w, database.DB, req.LicenseKey, false)
+	if !ok {
+		return
+	}
+
+	now := utils.NowSeoul()
+	nowStr := utils.FormatDateTimeForDB(now)
+	expiresAt := utils.FormatDateTimeForDB(now.Add(leaseTTL(req.TTLSeconds)))
+
+	result, err := database.DB.Exec(`UPDATE license_leases SET last_heartbeat_at = ?, expires_at = ?
+		WHERE id = ? AND license_id = ? AND status = ? AND expires_at > ?`,
+		nowStr, expiresAt, req.LeaseID, license.ID, models.LeaseStatusActive, nowStr)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to renew lease", err))
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Active lease not found", nil))
+		return
+	}
+
+	response := map[string]interface{}{
+		"lease_id":   req.LeaseID,
+		"expires_at": expiresAt,
+	}
+	if graceEndsAt, remaining := licenseGrace(license); graceEndsAt != nil {
+		response["grace_ends_at"] = *graceEndsAt
+		response["grace_remaining_seconds"] = *remaining
+	}
+	json.NewEncoder(w).Encode(models.SuccessResponse("Lease renewed", response))
+}
+
+// ReleaseLease는 임대한 좌석을 즉시 반납합니다.
+// @Summary 플로팅 라이선스 임대 반납
+// @Description 클라이언트 종료 시 좌석을 풀에 반환합니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Produce json
+// @Param request body models.LeaseReleaseRequest true "반납 요청 본문"
+// @Success 200 {object} models.APIResponse "반납 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "라이선스 또는 활성 임대 없음"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/lease/release [post]
+func ReleaseLease(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.LeaseReleaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.LicenseKey == "" || req.LeaseID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+	if keyErr := normalizeLicenseKey(r.Context().Value("request_id"), &req.LicenseKey); keyErr != nil {
+		keyErr.write(w)
+		return
+	}
+
+	var licenseID string
+	err := database.DB.QueryRow("SELECT id FROM licenses WHERE license_key = ?", utils.LicenseKeyLookup(req.LicenseKey)).Scan(&licenseID)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query license", err))
+		return
+	}
+
+	now := utils.FormatDateTimeForDB(utils.NowSeoul())
+	result, err := database.DB.Exec(`UPDATE license_leases SET status = ?, released_at = ?
+		WHERE id = ? AND license_id = ? AND status = ?`,
+		models.LeaseStatusReleased, now, req.LeaseID, licenseID, models.LeaseStatusActive)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to release lease", err))
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Active lease not found", nil))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": r.Context().Value("request_id"),
+		"license_id": licenseID,
+		"lease_id":   req.LeaseID,
+	}).Info("Floating lease released")
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Lease released", nil))
+}
+
+// loadLicenseLeases는 관리자 화면용으로 라이선스의 최근 임대 목록을 조회합니다.
+func loadLicenseLeases(licenseID string) ([]models.LicenseLease, error) {
+	rows, err := database.DB.Query(`SELECT id, license_id, device_fingerprint, COALESCE(device_name, ''), status,
+		checked_out_at, last_heartbeat_at, expires_at, released_at
+		FROM license_leases WHERE license_id = ?
+		ORDER BY checked_out_at DESC LIMIT 100`, licenseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leases := []models.LicenseLease{}
+	for rows.Next() {
+		var (
+			lease      models.LicenseLease
+			releasedAt sql.NullString
+		)
+		if err := rows.Scan(&lease.ID, &lease.LicenseID, &lease.DeviceFingerprint, &lease.DeviceName, &lease.Status,
+			&lease.CheckedOutAt, &lease.LastHeartbeatAt, &lease.ExpiresAt, &releasedAt); err != nil {
+			return nil, err
+		}
+		if releasedAt.Valid {
+			lease.ReleasedAt = &releasedAt.String
+		}
+		leases = append(leases, lease)
+	}
+	return leases, rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"studiolicense/database"
 	"studiolicense/logger"
 	"studiolicense/models"
@@ -22,7 +23,7 @@ import (
 // @Success 201 {object} models.APIResponse "활성화 완료"
 // @Success 200 {object} models.APIResponse "이미 활성화된 디바이스"
 // @Failure 400 {object} models.APIResponse "잘못된 요청"
-// @Failure 403 {object} models.APIResponse "라이선스 비활성/만료 또는 디바이스 제한 초과"
+// @Failure 403 {object} models.APIResponse "라이선스 비활성/만료, 디바이스 제한 초과 또는 유예 기간 중 새 디바이스"
 // @Failure 404 {object} models.APIResponse "라이선스를 찾을 수 없음"
 // @Failure 500 {object} models.APIResponse "서버 내부 오류"
 // @Router /api/license/activate [post]
@@ -47,51 +48,210 @@ func ActivateLicense(w http.ResponseWriter, r *http.Request) {
 		"device_name": req.DeviceInfo.Hostname,
 	}).Info("License activation attempt")
 
+	result, requestErr := activateDevice(requestID, req.LicenseKey, req.DeviceInfo)
+	if requestErr != nil {
+		requestErr.write(w)
+		return
+	}
+	license, lifecycle := result.License, result.Lifecycle
+
+	policies := loadPoliciesForLicense(license.PolicyID)
+	entitlements := loadEntitlementsForLicense(license.ID)
+	productFiles := loadProductFilesForProduct(license.ProductID, assignedReleaseChannel(license.ID, result.DeviceID), result.DeviceID)
+	productIDValue := stringValue(license.ProductID)
+
+	if result.Existing {
+		response := map[string]interface{}{
+			"license_key":      license.LicenseKey,
+			"device_id":        result.DeviceID,
+			"expires_at":       license.ExpiresAt,
+			"product_id":       productIDValue,
+			"policies":         policies,
+			"entitlements":     entitlements,
+			"product_files":    productFiles,
+			"max_offline_days": lifecycle.MaxOfflineDays,
+			"certificate":      issueLicenseCertificate(license, result.DeviceID, result.Fingerprint, policies, entitlements, lifecycle),
+		}
+		// 유예 기간 중 재설치한 디바이스에는 validate와 같은 유예 정보를 함께 반환합니다.
+		if graceEndsAt, remaining := licenseGrace(license); graceEndsAt != nil {
+			response["status"] = models.LicenseStatusGrace
+			response["grace_ends_at"] = *graceEndsAt
+			response["grace_remaining_seconds"] = *remaining
+		}
+		json.NewEncoder(w).Encode(models.SuccessResponse("Device already activated", response))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.SuccessResponse("License activated successfully", map[string]interface{}{
+		"license_key":      license.LicenseKey,
+		"device_id":        result.DeviceID,
+		"product_id":       productIDValue,
+		"product_name":     license.ProductName,
+		"expires_at":       license.ExpiresAt,
+		"policies":         policies,
+		"entitlements":     entitlements,
+		"product_files":    productFiles,
+		"max_offline_days": lifecycle.MaxOfflineDays,
+		"certificate":      issueLicenseCertificate(license, result.DeviceID, result.Fingerprint, policies, entitlements, lifecycle),
+	}))
+}
+
+// deviceActivation 디바이스 활성화 처리 결과
+type deviceActivation struct {
+	License     models.License
+	Lifecycle   utils.LicenseLifecycle
+	DeviceID    string
+	Fingerprint string
+	Existing    bool // 이미 활성화되어 있던 디바이스인지 여부
+}
+
+// clientRequestError 클라이언트 요청 처리 실패 시 응답할 HTTP 상태와 메시지
+type clientRequestError struct {
+	status  int
+	message string
+	err     error
+	data    interface{} // 응답의 data 필드 (선택)
+}
+
+func (e *clientRequestError) write(w http.ResponseWriter) {
+	resp := models.ErrorResponse(e.message, e.err)
+	if e.data != nil {
+		resp.Data = e.data
+	}
+	w.WriteHeader(e.status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// startLicenseIfDue 시작일이 지난 scheduled 라이선스를 스케줄러를 기다리지 않고 바로 active로 전환합니다.
+// 라이선스 행을 잠근 트랜잭션 안에서 호출할 때는 그 트랜잭션을 db로 넘겨야 합니다.
+func startLicenseIfDue(db utils.QueryExecer, license *models.License) {
+	if license.Status != models.LicenseStatusScheduled {
+		return
+	}
+	started, err := utils.StartScheduledLicense(db, license.ID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"license_id": license.ID,
+			"error":      err.Error(),
+		}).Warn("Failed to start scheduled license")
+		return
+	}
+	if started {
+		license.Status = models.LicenseStatusActive
+	}
+}
+
+// inactiveLicenseMessage 사용할 수 없는 상태의 라이선스에 대한 403 응답 메시지
+// 아직 시작일이 되지 않은 라이선스는 다른 비활성 상태와 구분되도록 별도 메시지를 사용합니다.
+func inactiveLicenseMessage(status string) string {
+	if status == models.LicenseStatusScheduled {
+		return "License is not yet valid"
+	}
+	return "License is not active"
+}
+
+// inactiveLicenseData 사용할 수 없는 상태의 라이선스에 대한 403 응답의 data 필드
+// 일시 정지/폐기된 라이선스는 클라이언트가 안내 문구를 고를 수 있도록 사유 코드(일시 정지는 자동 재개일도)를 함께 반환하고,
+// 아직 시작되지 않은 라이선스는 시작 시각(starts_at)을 반환합니다.
+func inactiveLicenseData(licenseID, status string) map[string]interface{} {
+	data := map[string]interface{}{"status": status}
+	if status == models.LicenseStatusScheduled {
+		var startsAt sql.NullString
+		database.DB.QueryRow("SELECT starts_at FROM licenses WHERE id = ?", licenseID).Scan(&startsAt)
+		if startsAt.Valid {
+			data["starts_at"] = startsAt.String
+		}
+		return data
+	}
+	if status == models.LicenseStatusRevoked {
+		var reason sql.NullString
+		database.DB.QueryRow("SELECT revocation_reason FROM licenses WHERE id = ?", licenseID).Scan(&reason)
+		data["revocation_reason"] = reason.String
+		return data
+	}
+	if status != models.LicenseStatusSuspended {
+		return data
+	}
+
+	var reason, resumeAt sql.NullString
+	database.DB.QueryRow("SELECT suspension_reason, DATE_FORMAT(resume_at, '%Y-%m-%d') FROM licenses WHERE id = ?",
+		licenseID).Scan(&reason, &resumeAt)
+	data["suspension_reason"] = reason.String
+	if resumeAt.Valid {
+		data["resume_at"] = resumeAt.String
+	}
+	return data
+}
+
+// normalizeLicenseKey는 제품 키 템플릿에 맞춰 입력 키를 표준 형식으로 바꿉니다.
+// 체크섬이 틀린 키(오타)는 DB를 조회하지 않고 400으로 거부합니다.
+func normalizeLicenseKey(requestID interface{}, licenseKey *string) *clientRequestError {
+	normalized, err := utils.NormalizeLicenseKey(*licenseKey)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"license_key": *licenseKey,
+		}).Warn("License key checksum mismatch")
+		return &clientRequestError{status: http.StatusBadRequest, message: "Invalid license key format", err: err}
+	}
+	*licenseKey = normalized
+	return nil
+}
+
+// activateDevice는 라이선스 상태와 디바이스 수 제한을 확인한 뒤 디바이스를 활성화합니다.
+// 온라인 활성화와 오프라인(파일) 활성화가 같은 검사를 거치도록 공통으로 사용합니다.
+func activateDevice(requestID interface{}, licenseKey string, deviceInfo models.DeviceInfo) (*deviceActivation, *clientRequestError) {
+	if keyErr := normalizeLicenseKey(requestID, &licenseKey); keyErr != nil {
+		return nil, keyErr
+	}
+
 	// 라이선스 메타데이터를 조회합니다.
 	var license models.License
 	var productID sql.NullString
 	var policyID sql.NullString
-	query := `SELECT l.id, l.license_key, l.product_id, l.policy_id,
+	query := `SELECT l.id, l.license_key, l.product_id, l.policy_id, l.license_type,
 		COALESCE(prod.name, '') as product_name,
 		l.customer_name, l.max_devices, 
-		l.expires_at, l.status
+		l.expires_at, l.status, l.grace_period_days, l.max_offline_days
 		FROM licenses l
 		LEFT JOIN products prod ON l.product_id = prod.id
 		WHERE l.license_key = ?`
 
-	err := database.DB.QueryRow(query, req.LicenseKey).Scan(
+	err := database.DB.QueryRow(query, utils.LicenseKeyLookup(licenseKey)).Scan(
 		&license.ID,
 		&license.LicenseKey,
 		&productID,
 		&policyID,
+		&license.LicenseType,
 		&license.ProductName,
 		&license.CustomerName,
 		&license.MaxDevices,
 		&license.ExpiresAt,
 		&license.Status,
+		&license.GracePeriodDays,
+		&license.MaxOfflineDays,
 	)
+	// 해시 저장 모드에서는 DB 값이 해시이므로 응답과 인증서에는 클라이언트가 보낸 키를 사용합니다.
+	license.LicenseKey = licenseKey
 
 	if err == sql.ErrNoRows {
 		logger.WithFields(map[string]interface{}{
 			"request_id":  requestID,
-			"license_key": req.LicenseKey,
+			"license_key": licenseKey,
 		}).Warn("License not found")
 
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
-		return
+		return nil, &clientRequestError{status: http.StatusNotFound, message: "License not found", err: nil}
 	}
 
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id":  requestID,
-			"license_key": req.LicenseKey,
+			"license_key": licenseKey,
 			"error":       err.Error(),
 		}).Error("Failed to query license")
 
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query license", err))
-		return
+		return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to query license", err: err}
 	}
 
 	if productID.Valid {
@@ -104,170 +264,190 @@ func ActivateLicense(w http.ResponseWriter, r *http.Request) {
 		*license.PolicyID = policyID.String
 	}
 
-	// 라이선스가 활성 상태이며 만료되지 않았는지 확인합니다.
-	if license.Status != models.LicenseStatusActive {
+	// 라이선스가 활성(또는 유예) 상태이며 유예 기간까지 끝나지 않았는지 확인합니다.
+	startLicenseIfDue(database.DB, &license)
+	if license.Status != models.LicenseStatusActive && license.Status != models.LicenseStatusGrace {
 		logger.WithFields(map[string]interface{}{
 			"request_id":  requestID,
-			"license_key": req.LicenseKey,
+			"license_key": licenseKey,
 			"status":      license.Status,
 		}).Warn("License is not active")
 
-		w.WriteHeader(http.StatusForbidden)
-		json.NewEncoder(w).Encode(models.ErrorResponse("License is not active", nil))
-		return
+		return nil, &clientRequestError{status: http.StatusForbidden, message: inactiveLicenseMessage(license.Status), err: nil,
+			data: inactiveLicenseData(license.ID, license.Status)}
 	}
 
-	if license.IsExpired() {
+	if !licenseUsable(license) {
 		logger.WithFields(map[string]interface{}{
 			"request_id":  requestID,
-			"license_key": req.LicenseKey,
+			"license_key": licenseKey,
 			"expires_at":  license.ExpiresAt,
 		}).Warn("License has expired")
 
-		w.WriteHeader(http.StatusForbidden)
-		json.NewEncoder(w).Encode(models.ErrorResponse("License has expired", nil))
-		return
+		return nil, &clientRequestError{status: http.StatusForbidden, message: "License has expired", err: nil}
 	}
 
+	// 플로팅 라이선스는 디바이스 활성화 대신 좌석 임대(lease)를 사용합니다.
+	if license.LicenseType == models.LicenseTypeFloating {
+		return nil, &clientRequestError{status: http.StatusConflict, message: "Floating license requires lease checkout", err: nil}
+	}
+
+	lifecycle := utils.ResolveLicenseLifecycle(license.GracePeriodDays, license.MaxOfflineDays, license.PolicyID)
+
 	// 디바이스 정보를 이용해 핑거프린트를 생성합니다.
 	fingerprint := utils.GenerateDeviceFingerprint(
-		req.DeviceInfo.ClientID,
-		req.DeviceInfo.CPUID,
-		req.DeviceInfo.MotherboardSN,
-		req.DeviceInfo.MACAddress,
-		req.DeviceInfo.DiskSerial,
-		req.DeviceInfo.MachineID,
+		deviceInfo.ClientID,
+		deviceInfo.CPUID,
+		deviceInfo.MotherboardSN,
+		deviceInfo.MACAddress,
+		deviceInfo.DiskSerial,
+		deviceInfo.MachineID,
 	)
 
-	// 해당 디바이스가 이미 활성화되어 있는지 확인합니다.
-	var existingID string
-	checkQuery := "SELECT id FROM device_activations WHERE license_id = ? AND device_fingerprint = ? AND status = ?"
-	err = database.DB.QueryRow(checkQuery, license.ID, fingerprint, models.DeviceStatusActive).Scan(&existingID)
+	// 해당 디바이스가 이미 활성화되어 있는지 확인합니다. (일부 부품 교체는 허용)
+	existingID, err := findActiveDevice(license.ID, license.ProductID, license.PolicyID, deviceInfo, fingerprint)
 
 	if err == nil {
 		// 이미 활성화된 디바이스이므로 기존 정보를 그대로 반환합니다.
 		logger.WithFields(map[string]interface{}{
 			"request_id":  requestID,
-			"license_key": req.LicenseKey,
+			"license_key": licenseKey,
 			"device_id":   existingID,
 		}).Info("Device already activated")
 
-		json.NewEncoder(w).Encode(models.SuccessResponse("Device already activated", map[string]interface{}{
-			"license_key":   license.LicenseKey,
-			"device_id":     existingID,
-			"expires_at":    license.ExpiresAt,
-			"product_id":    stringValue(license.ProductID),
-			"policies":      loadPoliciesForLicense(license.PolicyID),
-			"product_files": loadProductFilesForProduct(license.ProductID),
-		}))
-		return
+		return &deviceActivation{
+			License:     license,
+			Lifecycle:   lifecycle,
+			DeviceID:    existingID,
+			Fingerprint: fingerprint,
+			Existing:    true,
+		}, nil
 	}
 
 	if err != nil && err != sql.ErrNoRows {
 		logger.WithFields(map[string]interface{}{
 			"request_id":  requestID,
-			"license_key": req.LicenseKey,
+			"license_key": licenseKey,
 			"error":       err.Error(),
 		}).Error("Failed to verify device activation")
 
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify device activation", err))
-		return
+		return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to verify device activation", err: err}
+	}
+
+	// 유예 기간에는 이미 활성화된 디바이스(재설치 등)만 허용하고 새 디바이스는 활성화하지 않습니다.
+	if license.IsExpired() {
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"license_key": licenseKey,
+			"expires_at":  license.ExpiresAt,
+		}).Warn("New device activation refused during grace period")
+
+		return nil, &clientRequestError{status: http.StatusForbidden, message: "New device activation is not available during the grace period", err: nil,
+			data: map[string]interface{}{"license_id": license.ID, "status": models.LicenseStatusGrace}}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to start transaction", err: err}
+	}
+	defer tx.Rollback()
+
+	// 라이선스 행을 잠가 동시 활성화나 제품 변경(디바이스 한도 축소)과 디바이스 수 계산이 겹치지 않도록 합니다.
+	// 잠근 뒤의 max_devices와 상태를 다시 읽어 그 사이 바뀐 값을 기준으로 판단합니다.
+	var lockedStatus string
+	err = tx.QueryRow("SELECT max_devices, status FROM licenses WHERE id = ? FOR UPDATE", license.ID).
+		Scan(&license.MaxDevices, &lockedStatus)
+	if err != nil {
+		return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to query license", err: err}
+	}
+	if lockedStatus != models.LicenseStatusActive {
+		return nil, &clientRequestError{status: http.StatusForbidden, message: inactiveLicenseMessage(lockedStatus), err: nil,
+			data: inactiveLicenseData(license.ID, lockedStatus)}
 	}
 
 	// 라이선스에 허용된 활성 디바이스 수를 초과했는지 검사합니다.
 	var activeCount int
 	countQuery := "SELECT COUNT(*) FROM device_activations WHERE license_id = ? AND status = ?"
-	err = database.DB.QueryRow(countQuery, license.ID, models.DeviceStatusActive).Scan(&activeCount)
+	err = tx.QueryRow(countQuery, license.ID, models.DeviceStatusActive).Scan(&activeCount)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id":  requestID,
-			"license_key": req.LicenseKey,
+			"license_key": licenseKey,
 			"error":       err.Error(),
 		}).Error("Failed to count active devices")
 
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to count active devices", err))
-		return
+		return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to count active devices", err: err}
 	}
 
 	if activeCount >= license.MaxDevices {
 		logger.WithFields(map[string]interface{}{
 			"request_id":   requestID,
-			"license_key":  req.LicenseKey,
+			"license_key":  licenseKey,
 			"active_count": activeCount,
 			"max_devices":  license.MaxDevices,
 		}).Warn("Maximum device limit reached")
 
-		w.WriteHeader(http.StatusForbidden)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Maximum device limit reached", nil))
-		return
+		return nil, &clientRequestError{status: http.StatusForbidden, message: "Maximum device limit reached", err: nil}
 	}
 
 	// 디바이스 정보를 JSON 문자열로 직렬화합니다.
-	deviceInfoJSON, _ := json.Marshal(req.DeviceInfo)
+	deviceInfoJSON, _ := json.Marshal(deviceInfo)
 
 	// 새로운 디바이스 활성화 데이터를 저장합니다.
 	deviceID, _ := utils.GenerateID("dev")
 	insertQuery := `INSERT INTO device_activations 
-		(id, license_id, device_fingerprint, device_info, device_name, status, activated_at, last_validated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+		(id, license_id, device_fingerprint, component_hashes, device_info, device_name, status, activated_at, last_validated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	now := time.Now().Format("2006-01-02 15:04:05")
-	_, err = database.DB.Exec(insertQuery,
-		deviceID, license.ID, fingerprint, string(deviceInfoJSON),
-		req.DeviceInfo.Hostname, models.DeviceStatusActive, now, now,
+	_, err = tx.Exec(insertQuery,
+		deviceID, license.ID, fingerprint, utils.GenerateFingerprintComponents(deviceInfo).String(), string(deviceInfoJSON),
+		deviceInfo.Hostname, models.DeviceStatusActive, now, now,
 	)
+	if err == nil {
+		err = tx.Commit()
+	}
 
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id":  requestID,
-			"license_key": req.LicenseKey,
+			"license_key": licenseKey,
 			"error":       err.Error(),
 		}).Error("Failed to activate device")
 
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to activate device", err))
-		return
+		return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to activate device", err: err}
 	}
 
 	logger.WithFields(map[string]interface{}{
 		"request_id":  requestID,
-		"license_key": req.LicenseKey,
+		"license_key": licenseKey,
 		"device_id":   deviceID,
-		"device_name": req.DeviceInfo.Hostname,
+		"device_name": deviceInfo.Hostname,
 		"customer":    license.CustomerName,
 	}).Info("License activated successfully")
 
 	// 디바이스 활동 로그를 남깁니다.
 	utils.LogDeviceActivity(deviceID, license.ID, models.DeviceActionActivated, "Device activated")
 
-	policies := loadPoliciesForLicense(license.PolicyID)
-	productFiles := loadProductFilesForProduct(license.ProductID)
-	productIDValue := stringValue(license.ProductID)
-
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(models.SuccessResponse("License activated successfully", map[string]interface{}{
-		"license_key":   license.LicenseKey,
-		"device_id":     deviceID,
-		"product_id":    productIDValue,
-		"product_name":  license.ProductName,
-		"expires_at":    license.ExpiresAt,
-		"policies":      policies,
-		"product_files": productFiles,
-	}))
+	return &deviceActivation{
+		License:     license,
+		Lifecycle:   lifecycle,
+		DeviceID:    deviceID,
+		Fingerprint: fingerprint,
+	}, nil
 }
 
 // ValidateLicense는 등록된 디바이스가 라이선스를 사용할 수 있는지 검증합니다.
 // @Summary 라이선스 검증
-// @Description 라이선스에 등록된 디바이스인지 확인하고 정책 및 제품 파일 정보를 반환합니다.
+// @Description 라이선스에 등록된 디바이스인지 확인하고 정책 및 제품 파일 정보를 반환합니다. 만료 후 유예 기간 중이면 status=grace와 남은 시간을 함께 반환합니다.
+// @Description app_version을 보내면 유지보수 종료일(maintenance_until)까지 출시된 버전인지 확인하고, 응답의 version에 사용 가능한 최신 버전을 담습니다.
 // @Tags 라이선스-클라이언트
 // @Accept json
 // @Produce json
 // @Param request body models.ValidateRequest true "검증 요청 본문"
 // @Success 200 {object} models.APIResponse "검증 성공"
 // @Failure 400 {object} models.APIResponse "잘못된 요청"
-// @Failure 403 {object} models.APIResponse "라이선스 비활성, 미등록 디바이스 또는 만료"
+// @Failure 403 {object} models.APIResponse "라이선스 비활성, 미등록 디바이스 또는 만료(유예 기간 종료), 사용량 한도 소진, 사용할 수 없는 버전"
 // @Failure 404 {object} models.APIResponse "라이선스를 찾을 수 없음"
 // @Failure 500 {object} models.APIResponse "서버 내부 오류"
 // @Router /api/license/validate [post]
@@ -286,26 +466,48 @@ func ValidateLicense(w http.ResponseWriter, r *http.Request) {
 		"license_key": req.LicenseKey,
 	}).Debug("License validation request")
 
+	if keyErr := normalizeLicenseKey(requestID, &req.LicenseKey); keyErr != nil {
+		keyErr.write(w)
+		return
+	}
+
+	req.AppVersion = strings.TrimSpace(req.AppVersion)
+	if req.AppVersion != "" {
+		if _, err := utils.ParseSemver(req.AppVersion); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid app_version", err))
+			return
+		}
+	}
+
 	// 검증을 위해 라이선스 메타데이터를 다시 조회합니다.
 	var license models.License
 	var productID sql.NullString
 	var policyID sql.NullString
-	query := `SELECT l.id, l.license_key, l.product_id, l.policy_id,
+	var usageQuotas sql.NullString
+	query := `SELECT l.id, l.license_key, l.product_id, l.policy_id, l.license_type,
 		COALESCE(prod.name, '') as product_name,
-		l.expires_at, l.status
+		l.expires_at, l.status, l.grace_period_days, l.max_offline_days, l.usage_quotas,
+		DATE_FORMAT(l.maintenance_until, '%Y-%m-%d')
 		FROM licenses l
 		LEFT JOIN products prod ON l.product_id = prod.id
 		WHERE l.license_key = ?`
 
-	err := database.DB.QueryRow(query, req.LicenseKey).Scan(
+	err := database.DB.QueryRow(query, utils.LicenseKeyLookup(req.LicenseKey)).Scan(
 		&license.ID,
 		&license.LicenseKey,
 		&productID,
 		&policyID,
+		&license.LicenseType,
 		&license.ProductName,
 		&license.ExpiresAt,
 		&license.Status,
+		&license.GracePeriodDays,
+		&license.MaxOfflineDays,
+		&usageQuotas,
+		&license.MaintenanceUntil,
 	)
+	license.LicenseKey = req.LicenseKey
 
 	if err == sql.ErrNoRows {
 		w.WriteHeader(http.StatusNotFound)
@@ -329,16 +531,34 @@ func ValidateLicense(w http.ResponseWriter, r *http.Request) {
 		*license.PolicyID = policyID.String
 	}
 
-	// 라이선스가 활성 상태이며 만료되지 않았는지 확인합니다.
-	if license.Status != models.LicenseStatusActive {
+	// 라이선스가 활성(또는 유예) 상태이며 만료되지 않았는지 확인합니다.
+	startLicenseIfDue(database.DB, &license)
+	if license.Status != models.LicenseStatusActive && license.Status != models.LicenseStatusGrace {
+		resp := models.ErrorResponse(inactiveLicenseMessage(license.Status), nil)
+		resp.Data = inactiveLicenseData(license.ID, license.Status)
 		w.WriteHeader(http.StatusForbidden)
-		json.NewEncoder(w).Encode(models.ErrorResponse("License is not active", nil))
+		json.NewEncoder(w).Encode(resp)
 		return
 	}
 
+	// 만료일이 지났더라도 유예 기간 내이면 grace 상태로 사용을 허용합니다.
+	lifecycle := utils.ResolveLicenseLifecycle(license.GracePeriodDays, license.MaxOfflineDays, license.PolicyID)
+	status := models.LicenseStatusActive
+	var graceEndsAt time.Time
 	if license.IsExpired() {
-		w.WriteHeader(http.StatusForbidden)
-		json.NewEncoder(w).Encode(models.ErrorResponse("License has expired", nil))
+		endsAt, err := lifecycle.GraceEndsAt(license.ExpiresAt)
+		if err != nil || lifecycle.GracePeriodDays <= 0 || !utils.NowSeoul().Before(endsAt) {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(models.ErrorResponse("License has expired", nil))
+			return
+		}
+		status = models.LicenseStatusGrace
+		graceEndsAt = endsAt
+	}
+
+	if license.LicenseType == models.LicenseTypeFloating {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Floating license requires lease checkout", nil))
 		return
 	}
 
@@ -352,10 +572,8 @@ func ValidateLicense(w http.ResponseWriter, r *http.Request) {
 		req.DeviceInfo.MachineID,
 	)
 
-	// 활성화된 디바이스인지 확인합니다.
-	var deviceID string
-	deviceQuery := "SELECT id FROM device_activations WHERE license_id = ? AND device_fingerprint = ? AND status = ?"
-	err = database.DB.QueryRow(deviceQuery, license.ID, fingerprint, models.DeviceStatusActive).Scan(&deviceID)
+	// 활성화된 디바이스인지 확인합니다. (일부 부품 교체는 허용)
+	deviceID, err := findActiveDevice(license.ID, license.ProductID, license.PolicyID, req.DeviceInfo, fingerprint)
 
 	if err == sql.ErrNoRows {
 		w.WriteHeader(http.StatusForbidden)
@@ -373,21 +591,257 @@ func ValidateLicense(w http.ResponseWriter, r *http.Request) {
 	updateQuery := "UPDATE device_activations SET last_validated_at = ? WHERE id = ?"
 	database.DB.Exec(updateQuery, time.Now().Format("2006-01-02 15:04:05"), deviceID)
 
-	policies := loadPoliciesForLicense(license.PolicyID)
-	productFiles := loadProductFilesForProduct(license.ProductID)
+	// 사용량 한도를 모두 사용한 측정 항목이 있으면 검증을 거부합니다.
+	usage := loadUsageForLicense(license.ID, utils.ResolveUsageQuotas(nullStringPtr(usageQuotas), license.PolicyID))
+	for _, item := range usage {
+		if item.Exhausted() {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"license_id": license.ID,
+				"metric":     item.Metric,
+			}).Warn("License validation refused: usage quota exhausted")
+
+			resp := models.ErrorResponse("Usage quota exhausted", nil)
+			resp.Data = map[string]interface{}{"usage": usage}
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+	}
+
+	// 유지보수 종료일 이후에 출시된 버전은 사용할 수 없습니다.
 	productIDValue := stringValue(license.ProductID)
+	releases, err := utils.LoadProductReleases(productIDValue, "")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to check product version", err))
+		return
+	}
+	version := utils.ResolveReleaseEntitlement(releases, license.MaintenanceUntil, req.AppVersion)
+	if !version.Entitled {
+		logger.WithFields(map[string]interface{}{
+			"request_id":        requestID,
+			"license_id":        license.ID,
+			"app_version":       req.AppVersion,
+			"maintenance_until": stringValue(license.MaintenanceUntil),
+		}).Warn("License validation refused: version not covered by maintenance")
+
+		resp := models.ErrorResponse("License is not entitled to this version", nil)
+		resp.Data = map[string]interface{}{"version": version}
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	policies := loadPoliciesForLicense(license.PolicyID)
+	entitlements := loadEntitlementsForLicense(license.ID)
+	productFiles := loadProductFilesForProduct(license.ProductID, assignedReleaseChannel(license.ID, deviceID), deviceID)
+
+	response := map[string]interface{}{
+		"license_key":      license.LicenseKey,
+		"product_id":       productIDValue,
+		"product_name":     license.ProductName,
+		"expires_at":       license.ExpiresAt,
+		"valid":            true,
+		"status":           status,
+		"max_offline_days": lifecycle.MaxOfflineDays,
+		"policies":         policies,
+		"entitlements":     entitlements,
+		"usage":            usage,
+		"product_files":    productFiles,
+		"version":          version,
+		"certificate":      issueLicenseCertificate(license, deviceID, fingerprint, policies, entitlements, lifecycle),
+	}
+	if status == models.LicenseStatusGrace {
+		response["grace_ends_at"] = graceEndsAt.Format(time.RFC3339)
+		response["grace_remaining_seconds"] = int64(time.Until(graceEndsAt).Seconds())
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("License is valid", response))
+}
 
-	json.NewEncoder(w).Encode(models.SuccessResponse("License is valid", map[string]interface{}{
-		"license_key":   license.LicenseKey,
-		"product_id":    productIDValue,
-		"product_name":  license.ProductName,
-		"expires_at":    license.ExpiresAt,
-		"valid":         true,
-		"policies":      policies,
-		"product_files": productFiles,
+// DeactivateLicense는 최종 사용자가 자신의 디바이스 활성화를 해제해 슬롯을 반환합니다.
+// 셀프 해제는 라이선스마다 기간당 횟수 제한이 있으며
+// SELF_DEACTIVATION_LIMIT(기본 3회), SELF_DEACTIVATION_PERIOD_DAYS(기본 30일) 환경변수로 조정합니다.
+// @Summary 라이선스 비활성화 (클라이언트)
+// @Description 요청한 디바이스의 활성화를 해제하여 다른 디바이스에서 사용할 수 있도록 슬롯을 반환합니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Produce json
+// @Param request body models.DeactivateRequest true "비활성화 요청 본문"
+// @Success 200 {object} models.APIResponse "비활성화 완료"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "라이선스 또는 활성 디바이스를 찾을 수 없음"
+// @Failure 429 {object} models.APIResponse "셀프 해제 횟수 초과"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/deactivate [post]
+func DeactivateLicense(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("request_id")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.DeactivateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	if req.LicenseKey == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License key is required", nil))
+		return
+	}
+	if keyErr := normalizeLicenseKey(requestID, &req.LicenseKey); keyErr != nil {
+		keyErr.write(w)
+		return
+	}
+
+	var (
+		licenseID string
+		productID sql.NullString
+		policyID  sql.NullString
+	)
+	err := database.DB.QueryRow("SELECT id, product_id, policy_id FROM licenses WHERE license_key = ?", utils.LicenseKeyLookup(req.LicenseKey)).Scan(&licenseID, &productID, &policyID)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query license", err))
+		return
+	}
+
+	// 활성화 때와 동일한 방식으로 핑거프린트를 다시 계산합니다.
+	fingerprint := utils.GenerateDeviceFingerprint(
+		req.DeviceInfo.ClientID,
+		req.DeviceInfo.CPUID,
+		req.DeviceInfo.MotherboardSN,
+		req.DeviceInfo.MACAddress,
+		req.DeviceInfo.DiskSerial,
+		req.DeviceInfo.MachineID,
+	)
+
+	deviceID, err := findActiveDevice(licenseID, nullStringPtr(productID), nullStringPtr(policyID), req.DeviceInfo, fingerprint)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Device not activated", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify device", err))
+		return
+	}
+
+	remaining, periodDays, requestErr := selfDeactivateDevice(requestID, licenseID, deviceID, "Deactivated by client")
+	if requestErr != nil {
+		requestErr.write(w)
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":  requestID,
+		"license_id":  licenseID,
+		"device_id":   deviceID,
+		"device_name": req.DeviceInfo.Hostname,
+	}).Info("Device deactivated by client")
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Device deactivated successfully", map[string]interface{}{
+		"device_id":                deviceID,
+		"remaining_deactivations":  remaining,
+		"deactivation_period_days": periodDays,
 	}))
 }
 
+// selfDeactivateDevice는 라이선스 행을 잠근 트랜잭션 안에서 셀프 해제 횟수 확인, 디바이스 해제, 활동 로그 기록을 함께 처리합니다.
+// 같은 라이선스의 다른 디바이스 해제 요청이 동시에 들어와도 기간당 한도를 넘지 않으며,
+// 이미 해제된 디바이스(동시에 들어온 같은 요청)는 404를 반환해 로그와 해제 횟수를 중복으로 남기지 않습니다.
+func selfDeactivateDevice(requestID interface{}, licenseID, deviceID, details string) (int, int, *clientRequestError) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, 0, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to start transaction", err: err}
+	}
+	defer tx.Rollback()
+
+	var lockedID string
+	if err := tx.QueryRow("SELECT id FROM licenses WHERE id = ? FOR UPDATE", licenseID).Scan(&lockedID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, &clientRequestError{status: http.StatusNotFound, message: "License not found"}
+		}
+		return 0, 0, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to query license", err: err}
+	}
+
+	remaining, periodDays, requestErr := checkSelfDeactivationLimit(tx, requestID, licenseID)
+	if requestErr != nil {
+		return 0, periodDays, requestErr
+	}
+
+	now := utils.FormatDateTimeForDB(utils.NowSeoul())
+	updateQuery := `UPDATE device_activations SET status = ?, deactivated_at = ? WHERE id = ? AND status = ?`
+	result, err := tx.Exec(updateQuery, models.DeviceStatusDeactivated, now, deviceID, models.DeviceStatusActive)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"device_id":  deviceID,
+			"error":      err.Error(),
+		}).Error("Failed to deactivate device")
+
+		return 0, periodDays, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to deactivate device", err: err}
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return 0, periodDays, &clientRequestError{status: http.StatusNotFound, message: "Device not activated"}
+	}
+
+	if err := utils.RecordDeviceActivity(tx, deviceID, licenseID, models.DeviceActionSelfDeactivated, details); err != nil {
+		return 0, periodDays, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to deactivate device", err: err}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, periodDays, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to deactivate device", err: err}
+	}
+	return remaining, periodDays, nil
+}
+
+// checkSelfDeactivationLimit는 기간 내 셀프 해제 횟수를 확인하고 이번 해제 후 남는 횟수를 반환합니다.
+// limit <= 0 이면 제한이 없으며 remaining은 -1입니다.
+func checkSelfDeactivationLimit(q utils.QueryExecer, requestID interface{}, licenseID string) (int, int, *clientRequestError) {
+	limit := utils.GetEnvInt("SELF_DEACTIVATION_LIMIT", 3)
+	periodDays := utils.GetEnvInt("SELF_DEACTIVATION_PERIOD_DAYS", 30)
+	if periodDays <= 0 {
+		periodDays = 30
+	}
+
+	remaining := -1
+	if limit > 0 {
+		since := utils.FormatDateTimeForDB(utils.NowSeoul().AddDate(0, 0, -periodDays))
+		var used int
+		countQuery := `SELECT COUNT(*) FROM device_activity_logs
+			WHERE license_id = ? AND action = ? AND created_at >= ?`
+		if err := q.QueryRow(countQuery, licenseID, models.DeviceActionSelfDeactivated, since).Scan(&used); err != nil {
+			return 0, periodDays, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to check deactivation limit", err: err}
+		}
+
+		if used >= limit {
+			logger.WithFields(map[string]interface{}{
+				"request_id":  requestID,
+				"license_id":  licenseID,
+				"used":        used,
+				"limit":       limit,
+				"period_days": periodDays,
+			}).Warn("Self-service deactivation limit reached")
+
+			return 0, periodDays, &clientRequestError{status: http.StatusTooManyRequests, message: "Self-service deactivation limit reached"}
+		}
+		remaining = limit - used - 1
+	}
+	return remaining, periodDays, nil
+}
+
 func loadPoliciesForLicense(policyID *string) []models.PolicyResponse {
 	if policyID == nil || *policyID == "" {
 		return nil
@@ -426,13 +880,60 @@ func loadPoliciesForLicense(policyID *string) []models.PolicyResponse {
 	return policies
 }
 
-func loadProductFilesForProduct(productID *string) []models.ProductFileResponse {
+// loadEntitlementsForLicense는 라이선스에 부여된 기능 중 만료되지 않은 것만 반환합니다.
+func loadEntitlementsForLicense(licenseID string) []models.EntitlementResponse {
+	rows, err := database.DB.Query(`SELECT f.feature_key, f.name, e.quantity, e.expires_at
+		FROM license_entitlements e
+		JOIN product_features f ON e.feature_id = f.id
+		WHERE e.license_id = ? AND (e.expires_at IS NULL OR e.expires_at > ?)
+		ORDER BY f.feature_key`, licenseID, utils.FormatDateTimeForDB(utils.NowSeoul()))
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"license_id": licenseID,
+			"error":      err.Error(),
+		}).Error("Failed to load entitlements for license")
+		return nil
+	}
+	defer rows.Close()
+
+	entitlements := []models.EntitlementResponse{}
+	for rows.Next() {
+		var (
+			e         models.EntitlementResponse
+			quantity  sql.NullInt64
+			expiresAt sql.NullTime
+		)
+		if err := rows.Scan(&e.FeatureKey, &e.Name, &quantity, &expiresAt); err != nil {
+			logger.Warn("Failed to scan entitlement row: %v", err)
+			continue
+		}
+		if quantity.Valid {
+			q := int(quantity.Int64)
+			e.Quantity = &q
+		}
+		if expiresAt.Valid {
+			ts := expiresAt.Time.Format(time.RFC3339)
+			e.ExpiresAt = &ts
+		}
+		entitlements = append(entitlements, e)
+	}
+
+	return entitlements
+}
+
+// loadProductFilesForProduct 디바이스의 릴리스 채널(비어 있으면 stable)과 단계적 배포 대상 여부에 맞는 활성 제품 파일을 반환합니다.
+func loadProductFilesForProduct(productID *string, channel, deviceID string) []models.ProductFileResponse {
 	if productID == nil || *productID == "" {
 		return nil
 	}
+	if channel == "" {
+		channel = models.ReleaseChannelStable
+	}
 
 	query := `SELECT pf.id, pf.file_id, pf.label, pf.description, pf.sort_order, pf.delivery_url, pf.updated_at,
-		f.mime_type, f.file_size, f.checksum, f.storage_path
+		f.mime_type, f.file_size, f.checksum, f.storage_path,
+		COALESCE(pf.version, ''), COALESCE(pf.os, ''), COALESCE(pf.arch, ''),
+		pf.channel, pf.rollout_percentage, pf.rollout_status
 		FROM product_files pf
 		JOIN files f ON pf.file_id = f.id
 		WHERE pf.product_id = ? AND pf.is_active = 1
@@ -451,10 +952,12 @@ func loadProductFilesForProduct(productID *string) []models.ProductFileResponse
 	files := []models.ProductFileResponse{}
 	for rows.Next() {
 		var (
-			item        models.ProductFileResponse
-			description sql.NullString
-			deliveryURL sql.NullString
-			checksum    sql.NullString
+			item              models.ProductFileResponse
+			description       sql.NullString
+			deliveryURL       sql.NullString
+			checksum          sql.NullString
+			rolloutPercentage int
+			rolloutStatus     string
 		)
 
 		if err := rows.Scan(
@@ -469,11 +972,23 @@ func loadProductFilesForProduct(productID *string) []models.ProductFileResponse
 			&item.FileSize,
 			&checksum,
 			&item.StoragePath,
+			&item.Version,
+			&item.OS,
+			&item.Arch,
+			&item.Channel,
+			&rolloutPercentage,
+			&rolloutStatus,
 		); err != nil {
 			logger.Warn("Failed to scan product file mapping: %v", err)
 			continue
 		}
 
+		// 디바이스 채널에 포함되지 않거나 단계적 배포 대상이 아닌 파일은 내려주지 않습니다.
+		if !models.ReleaseChannelIncludes(channel, item.Channel) ||
+			!utils.InRollout(rolloutStatus, rolloutPercentage, deviceID, utils.RolloutSalt(*productID, item.Version, item.ID)) {
+			continue
+		}
+
 		if description.Valid {
 			item.Description = description.String
 		}
@@ -486,34 +1001,44 @@ func loadProductFilesForProduct(productID *string) []models.ProductFileResponse
 			item.Checksum = checksum.String
 		}
 
-		if item.DeliveryURL != "" {
-			item.URL = item.DeliveryURL
-		}
-
-		if signedQuery, err := utils.GenerateSignedDownloadQuery(item.FileID, 5*time.Minute); err != nil {
-			logger.WithFields(map[string]interface{}{
-				"product_id": *productID,
-				"file_id":    item.FileID,
-				"error":      err.Error(),
-			}).Error("Failed to generate signed download URL for product file")
-			item.DownloadURL = ""
-			if item.URL == "" {
-				item.URL = ""
-			}
-		} else {
-			signedURL := fmt.Sprintf("/api/license/files/%s?%s", item.FileID, signedQuery)
-			item.DownloadURL = signedURL
-			if item.URL == "" {
-				item.URL = signedURL
-			}
-		}
-
+		applyProductFileURLs(&item, *productID)
 		files = append(files, item)
 	}
 
 	return files
 }
 
+// applyProductFileURLs 외부 배포 URL이 있으면 url로 쓰고, 서버 파일은 짧은 유효기간의 서명 다운로드 URL을 채웁니다.
+func applyProductFileURLs(item *models.ProductFileResponse, productID string) {
+	if item.DeliveryURL != "" {
+		item.URL = item.DeliveryURL
+	}
+
+	signedQuery, err := utils.GenerateSignedDownloadQuery(item.FileID, 5*time.Minute)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"product_id": productID,
+			"file_id":    item.FileID,
+			"error":      err.Error(),
+		}).Error("Failed to generate signed download URL for product file")
+		item.DownloadURL = ""
+		return
+	}
+
+	signedURL := fmt.Sprintf("/api/license/files/%s?%s", item.FileID, signedQuery)
+	item.DownloadURL = signedURL
+	if item.URL == "" {
+		item.URL = signedURL
+	}
+}
+
+func nullStringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
 func stringValue(ptr *string) string {
 	if ptr == nil {
 		return ""
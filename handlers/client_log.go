@@ -8,6 +8,7 @@ import (
 	"studiolicense/database"
 	"studiolicense/logger"
 	"studiolicense/models"
+	"studiolicense/utils"
 	"time"
 )
 
@@ -40,9 +41,13 @@ func SubmitClientLogs(w http.ResponseWriter, r *http.Request) {
 
 	// 라이선스 키 검증 (선택사항: 유효한 라이선스인지 확인)
 	if req.LicenseKey != "" {
+		if keyErr := normalizeLicenseKey(requestID, &req.LicenseKey); keyErr != nil {
+			keyErr.write(w)
+			return
+		}
 		var exists bool
 		checkQuery := "SELECT EXISTS(SELECT 1 FROM licenses WHERE license_key = ?)"
-		err := database.DB.QueryRow(checkQuery, req.LicenseKey).Scan(&exists)
+		err := database.DB.QueryRow(checkQuery, utils.LicenseKeyLookup(req.LicenseKey)).Scan(&exists)
 		if err != nil || !exists {
 			logger.WithFields(map[string]interface{}{
 				"request_id":  requestID,
@@ -90,7 +95,7 @@ func SubmitClientLogs(w http.ResponseWriter, r *http.Request) {
 		}
 
 		_, err := database.DB.Exec(insertQuery,
-			req.LicenseKey, req.DeviceID, level, category, log.Message,
+			utils.LicenseKeyLookup(req.LicenseKey), req.DeviceID, level, category, log.Message,
 			log.Details, log.StackTrace, log.AppVersion, log.OSVersion,
 			clientIP, timestamp, now,
 		)
@@ -177,7 +182,8 @@ func GetClientLogs(w http.ResponseWriter, r *http.Request) {
 
 	if licenseKey != "" {
 		baseQuery += " AND license_key = ?"
-		args = append(args, licenseKey)
+		normalized, _ := utils.NormalizeLicenseKey(licenseKey)
+		args = append(args, utils.LicenseKeyLookup(normalized))
 	}
 	if deviceID != "" {
 		baseQuery += " AND device_id = ?"
@@ -217,7 +223,10 @@ func GetClientLogs(w http.ResponseWriter, r *http.Request) {
 
 	// 로그 목록 조회
 	offset := (page - 1) * pageSize
-	selectQuery := `SELECT id, license_key, device_id, level, category, message, 
+	// 해시 저장 모드에서는 라이선스의 표시용 접두사로 보여줍니다.
+	selectQuery := `SELECT id,
+		COALESCE((SELECT CONCAT(l.license_key_prefix, '...') FROM licenses l WHERE l.license_key = client_logs.license_key), license_key),
+		device_id, level, category, message, 
 		details, stack_trace, app_version, os_version, client_ip, client_timestamp, created_at 
 		` + baseQuery + " ORDER BY created_at DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offset)
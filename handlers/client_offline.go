@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/licensecert"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/services"
+	"studiolicense/utils"
+)
+
+// offlineRequestMaxBytes 요청 파일 최대 크기
+const offlineRequestMaxBytes = 64 << 10
+
+// offlineRequestVerifier는 요청 파일의 제품 서명을 검증합니다. 주입하지 않으면 기본 DB로 만든 검증기를 사용합니다.
+var offlineRequestVerifier *services.RequestSignatureVerifier
+
+// SetOfflineRequestVerifier는 오프라인 요청 파일의 제품 서명 검증기를 주입합니다.
+func SetOfflineRequestVerifier(verifier *services.RequestSignatureVerifier) {
+	offlineRequestVerifier = verifier
+}
+
+// OfflineActivateLicense 오프라인(에어갭) 활성화
+// 서버에 접속할 수 없는 클라이언트가 만든 활성화 요청 파일을 받아 온라인 활성화와 같은 검사를 수행하고
+// 클라이언트가 가져갈 서명된 활성화 응답 파일을 반환합니다.
+// 요청 파일은 JSON 본문 또는 multipart 폼의 file 필드로 업로드합니다.
+// @Summary 오프라인 활성화
+// @Description 디바이스 키로 서명된 활성화 요청 파일을 처리하고 서명된 활성화 응답 파일(인증서 포함)을 반환합니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Accept mpfd
+// @Produce json
+// @Param request body licensecert.SignedOfflineRequest true "활성화 요청 파일"
+// @Success 200 {object} licensecert.SignedOfflineResponse "활성화 응답 파일"
+// @Failure 400 {object} models.APIResponse "잘못된 요청 파일"
+// @Failure 401 {object} models.APIResponse "제품 서명(product_signature) 누락 또는 불일치"
+// @Failure 403 {object} models.APIResponse "라이선스 비활성/만료 또는 디바이스 제한 초과"
+// @Failure 404 {object} models.APIResponse "라이선스를 찾을 수 없음"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/offline/activate [post]
+func OfflineActivateLicense(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("request_id")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, ok := readOfflineRequest(w, r, licensecert.OfflineActivation)
+	if !ok {
+		return
+	}
+
+	var deviceInfo models.DeviceInfo
+	if err := json.Unmarshal(req.DeviceInfo, &deviceInfo); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid device info in request file", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":  requestID,
+		"license_key": req.LicenseKey,
+		"device_name": deviceInfo.Hostname,
+	}).Info("Offline license activation attempt")
+
+	result, requestErr := activateDevice(requestID, req.LicenseKey, deviceInfo)
+	if requestErr != nil {
+		requestErr.write(w)
+		return
+	}
+
+	// 비활성화 요청을 검증할 수 있도록 디바이스 공개키를 기록합니다. (재설치로 키가 바뀌면 새 키로 교체)
+	if _, err := database.DB.Exec("UPDATE device_activations SET offline_public_key = ? WHERE id = ?",
+		req.DevicePublicKey, result.DeviceID); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record device key", err))
+		return
+	}
+	if !result.Existing {
+		utils.LogDeviceActivity(result.DeviceID, result.License.ID, models.DeviceActionActivated, "Activated by offline request file")
+	}
+
+	license := result.License
+	policies := loadPoliciesForLicense(license.PolicyID)
+	entitlements := loadEntitlementsForLicense(license.ID)
+	certificate := issueLicenseCertificate(license, result.DeviceID, result.Fingerprint, policies, entitlements, result.Lifecycle)
+	if certificate == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to issue license certificate", nil))
+		return
+	}
+
+	writeOfflineResponse(w, licensecert.OfflineResponse{
+		Type:         licensecert.OfflineActivation,
+		LicenseKey:   license.LicenseKey,
+		DeviceID:     result.DeviceID,
+		RequestNonce: req.Nonce,
+		Certificate:  certificate,
+		IssuedAt:     utils.NowSeoul(),
+	})
+}
+
+// OfflineDeactivateLicense 오프라인(에어갭) 비활성화
+// 클라이언트가 라이선스를 제거한 뒤 같은 디바이스 키로 서명한 비활성화 요청 파일을 받아 좌석을 반환합니다.
+// 활성화 때 기록한 디바이스 공개키와 일치해야 하며, 셀프 해제 횟수 제한이 동일하게 적용됩니다.
+// @Summary 오프라인 비활성화
+// @Description 디바이스 키로 서명된 비활성화 요청 파일(제거 증명)을 처리하고 서명된 비활성화 영수증 파일을 반환합니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Accept mpfd
+// @Produce json
+// @Param request body licensecert.SignedOfflineRequest true "비활성화 요청 파일"
+// @Success 200 {object} licensecert.SignedOfflineResponse "비활성화 응답 파일"
+// @Failure 400 {object} models.APIResponse "잘못된 요청 파일"
+// @Failure 401 {object} models.APIResponse "제품 서명(product_signature) 누락 또는 불일치"
+// @Failure 403 {object} models.APIResponse "디바이스 키 불일치"
+// @Failure 404 {object} models.APIResponse "라이선스 또는 활성 디바이스를 찾을 수 없음"
+// @Failure 429 {object} models.APIResponse "셀프 해제 횟수 초과"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/offline/deactivate [post]
+func OfflineDeactivateLicense(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("request_id")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, ok := readOfflineRequest(w, r, licensecert.OfflineDeactivation)
+	if !ok {
+		return
+	}
+	if strings.TrimSpace(req.DeviceID) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("device_id is required in deactivation request", nil))
+		return
+	}
+
+	var (
+		licenseID   string
+		deviceKey   sql.NullString
+		activatedAt time.Time
+	)
+	err := database.DB.QueryRow(`SELECT l.id, d.offline_public_key, d.activated_at
+		FROM device_activations d
+		JOIN licenses l ON d.license_id = l.id
+		WHERE l.license_key = ? AND d.id = ? AND d.status = ?`,
+		utils.LicenseKeyLookup(req.LicenseKey), req.DeviceID, models.DeviceStatusActive).Scan(&licenseID, &deviceKey, &activatedAt)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Device not activated", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify device", err))
+		return
+	}
+
+	// 활성화 이전에 만들어진 요청 파일은 재활성화된 디바이스를 해제하는 데 재사용할 수 없습니다.
+	if !deviceKey.Valid || deviceKey.String != req.DevicePublicKey || req.CreatedAt.Before(activatedAt) {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"license_id": licenseID,
+			"device_id":  req.DeviceID,
+		}).Warn("Offline deactivation proof rejected")
+
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Deactivation request does not match the activated device", nil))
+		return
+	}
+
+	// 온라인 해제와 같은 잠금 트랜잭션으로 처리해 동시 요청이 기간당 한도를 넘지 않게 합니다.
+	remaining, periodDays, requestErr := selfDeactivateDevice(requestID, licenseID, req.DeviceID, "Deactivated by offline request file")
+	if requestErr != nil {
+		requestErr.write(w)
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":              requestID,
+		"license_id":              licenseID,
+		"device_id":               req.DeviceID,
+		"remaining_deactivations": remaining,
+		"deactivation_period":     periodDays,
+	}).Info("Device deactivated by offline request")
+
+	writeOfflineResponse(w, licensecert.OfflineResponse{
+		Type:         licensecert.OfflineDeactivation,
+		LicenseKey:   req.LicenseKey,
+		DeviceID:     req.DeviceID,
+		RequestNonce: req.Nonce,
+		IssuedAt:     utils.NowSeoul(),
+	})
+}
+
+// readOfflineRequest는 업로드된 요청 파일을 읽고 디바이스 서명, 유형, 생성 시각을 확인합니다.
+// 요청 파일 유효 기간은 OFFLINE_REQUEST_MAX_AGE_DAYS(기본 30일)입니다.
+func readOfflineRequest(w http.ResponseWriter, r *http.Request, expectedType string) (licensecert.OfflineRequest, bool) {
+	raw, err := readOfflineRequestFile(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request file", err))
+		return licensecert.OfflineRequest{}, false
+	}
+
+	var signed licensecert.SignedOfflineRequest
+	if err := json.Unmarshal(raw, &signed); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request file", err))
+		return licensecert.OfflineRequest{}, false
+	}
+
+	req, err := licensecert.VerifyOfflineRequest(signed)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Request file signature is invalid", err))
+		return req, false
+	}
+
+	if req.Type != expectedType || strings.TrimSpace(req.LicenseKey) == "" || strings.TrimSpace(req.Nonce) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse(fmt.Sprintf("Expected a %s request file", expectedType), nil))
+		return req, false
+	}
+
+	if keyErr := normalizeLicenseKey(r.Context().Value("request_id"), &req.LicenseKey); keyErr != nil {
+		keyErr.write(w)
+		return req, false
+	}
+
+	// 요청 파일은 서명 미들웨어를 거치지 않으므로 서명이 필요한 제품은 파일 안의 제품 서명을 확인합니다.
+	verifier := offlineRequestVerifier
+	if verifier == nil {
+		verifier = services.NewRequestSignatureVerifier(database.DB, nil, 0)
+	}
+	if err := verifier.VerifyOfflineRequest(r.Context(), req.LicenseKey, signed); err != nil {
+		if errors.Is(err, services.ErrRequestProductUnknown) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+			return req, false
+		}
+		logger.WithFields(map[string]interface{}{
+			"request_id": r.Context().Value("request_id"),
+			"error":      err.Error(),
+		}).Warn("Offline request product signature rejected")
+
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Request signature rejected", err))
+		return req, false
+	}
+
+	maxAge := time.Duration(utils.GetEnvInt("OFFLINE_REQUEST_MAX_AGE_DAYS", 30)) * 24 * time.Hour
+	now := utils.NowSeoul()
+	if req.CreatedAt.IsZero() || req.CreatedAt.After(now.Add(time.Hour)) || (maxAge > 0 && now.Sub(req.CreatedAt) > maxAge) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Request file has expired", nil))
+		return req, false
+	}
+
+	return req, true
+}
+
+// readOfflineRequestFile은 multipart 업로드(file 필드) 또는 JSON 본문에서 요청 파일 내용을 읽습니다.
+func readOfflineRequestFile(r *http.Request) ([]byte, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(offlineRequestMaxBytes); err != nil {
+			return nil, err
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return readLimited(file)
+	}
+	return readLimited(r.Body)
+}
+
+func readLimited(reader io.Reader) ([]byte, error) {
+	raw, err := io.ReadAll(io.LimitReader(reader, offlineRequestMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > offlineRequestMaxBytes {
+		return nil, errors.New("request file is too large")
+	}
+	return raw, nil
+}
+
+// writeOfflineResponse는 응답 파일에 서명하고 다운로드 가능한 JSON 파일로 반환합니다.
+func writeOfflineResponse(w http.ResponseWriter, resp licensecert.OfflineResponse) {
+	signed, err := utils.SignOfflineResponse(resp)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"device_id": resp.DeviceID,
+			"error":     err.Error(),
+		}).Error("Failed to sign offline response")
+
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to sign response file", err))
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-response-%s.json"`, resp.Type, resp.DeviceID))
+	json.NewEncoder(w).Encode(signed)
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// RequestTrialLicense는 제품별 셀프 체험판 라이선스를 발급하고 요청한 디바이스를 바로 활성화합니다.
+// 같은 제품에 대해 디바이스 또는 이메일당 한 번만 발급합니다. 디바이스는 활성화와 같이 구성요소별로 비교하므로
+// 구성요소 일부(MAC, client_id 등)만 바꿔서는 새 체험판을 받을 수 없습니다.
+// 체험 기간은 제품의 trial_days, 없으면 TRIAL_DURATION_DAYS(기본 14일)를 사용합니다.
+// @Summary 체험판 라이선스 발급
+// @Description 요청한 디바이스에 바인딩된 단기 체험판 라이선스를 발급합니다. 디바이스/이메일당 제품별 1회로 제한됩니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Produce json
+// @Param request body models.TrialRequest true "체험판 요청 본문"
+// @Success 201 {object} models.APIResponse "발급 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "체험판을 제공하지 않는 제품"
+// @Failure 404 {object} models.APIResponse "제품을 찾을 수 없음"
+// @Failure 409 {object} models.APIResponse "이미 체험판을 사용함"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/trial [post]
+func RequestTrialLicense(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("request_id")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TrialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.ProductID = strings.TrimSpace(req.ProductID)
+	req.CustomerEmail = strings.ToLower(strings.TrimSpace(req.CustomerEmail))
+	if req.ProductID == "" || !strings.Contains(req.CustomerEmail, "@") {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product ID and a valid email are required", nil))
+		return
+	}
+	if strings.TrimSpace(req.CustomerName) == "" {
+		req.CustomerName = req.CustomerEmail
+	}
+
+	var (
+		productName string
+		trialDays   sql.NullInt64
+	)
+	err := database.DB.QueryRow("SELECT name, trial_days FROM products WHERE id = ? AND status = ?",
+		req.ProductID, models.ProductStatusActive).Scan(&productName, &trialDays)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product not found or inactive", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify product", err))
+		return
+	}
+
+	days := utils.GetEnvInt("TRIAL_DURATION_DAYS", 14)
+	if trialDays.Valid {
+		days = int(trialDays.Int64)
+	}
+	if days <= 0 {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Trials are not available for this product", nil))
+		return
+	}
+
+	components := utils.GenerateFingerprintComponents(req.DeviceInfo)
+	if len(components) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Device info is required", nil))
+		return
+	}
+	fingerprint := utils.GenerateDeviceFingerprint(
+		req.DeviceInfo.ClientID,
+		req.DeviceInfo.CPUID,
+		req.DeviceInfo.MotherboardSN,
+		req.DeviceInfo.MACAddress,
+		req.DeviceInfo.DiskSerial,
+		req.DeviceInfo.MachineID,
+	)
+	threshold := fingerprintThreshold(&req.ProductID, nil)
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	var claimed int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM trial_claims
+		WHERE product_id = ? AND (device_fingerprint = ? OR customer_email = ?)`,
+		req.ProductID, fingerprint, req.CustomerEmail).Scan(&claimed); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to check trial history", err))
+		return
+	}
+	if claimed == 0 {
+		deviceClaimed, err := trialClaimedByDevice(tx, req.ProductID, components, threshold)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to check trial history", err))
+			return
+		}
+		if deviceClaimed {
+			claimed = 1
+		}
+	}
+	if claimed > 0 {
+		writeTrialAlreadyUsed(w, requestID, req)
+		return
+	}
+
+	// 체험판도 이메일 기준으로 고객에 연결합니다 (없으면 새 고객 생성).
+	customer, ok := resolveLicenseCustomer(w, r, "", req.CustomerName, req.CustomerEmail, "system")
+	if !ok {
+		return
+	}
+	var customerID *string
+	if customer.ID != "" {
+		customerID = &customer.ID
+		req.CustomerName = customer.Name
+	}
+
+	licenseID, err := utils.GenerateID("lic")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate ID", err))
+		return
+	}
+	licenseKey, err := utils.GenerateProductLicenseKey(req.ProductID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to generate license key", err))
+		return
+	}
+	deviceID, _ := utils.GenerateID("dev")
+	claimID, _ := utils.GenerateID("trial")
+
+	now := utils.NowSeoul()
+	nowStr := utils.FormatDateTimeForDB(now)
+	expiresAt := utils.FormatDateOnly(now.AddDate(0, 0, days))
+	deviceInfoJSON, _ := json.Marshal(req.DeviceInfo)
+
+	// 셀프 발급 라이선스는 관리자 소유가 아니므로 created_by를 system으로 기록합니다.
+	if _, err := tx.Exec(`INSERT INTO licenses (id, license_key, license_key_prefix, product_id, customer_id, license_type, is_trial, customer_name,
+		customer_email, max_devices, expires_at, status, created_by, notes, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 1, ?, ?, 1, ?, ?, 'system', ?, ?, ?)`,
+		licenseID, utils.LicenseKeyLookup(licenseKey), utils.LicenseKeyPrefix(licenseKey, req.ProductID), req.ProductID, customerID, models.LicenseTypeNodeLocked, req.CustomerName,
+		req.CustomerEmail, expiresAt, models.LicenseStatusActive, "Self-service trial", nowStr, nowStr,
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create trial license", err))
+		return
+	}
+
+	if _, err := tx.Exec(`INSERT INTO device_activations
+		(id, license_id, device_fingerprint, component_hashes, device_info, device_name, status, activated_at, last_validated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		deviceID, licenseID, fingerprint, components.String(),
+		string(deviceInfoJSON), req.DeviceInfo.Hostname, models.DeviceStatusActive, nowStr, nowStr,
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to activate device", err))
+		return
+	}
+
+	if _, err := tx.Exec(`INSERT INTO trial_claims (id, product_id, license_id, device_fingerprint, component_hashes, customer_email, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		claimID, req.ProductID, licenseID, fingerprint, components.String(), req.CustomerEmail, nowStr,
+	); err != nil {
+		// 동시 요청으로 유니크 키가 충돌한 경우도 이미 사용한 것으로 처리합니다.
+		if strings.Contains(err.Error(), "Duplicate") {
+			writeTrialAlreadyUsed(w, requestID, req)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record trial", err))
+		return
+	}
+
+	snapshot, err := utils.LoadLicenseSnapshot(tx, licenseID)
+	if err == nil {
+		err = utils.RecordLicenseEvent(tx, models.LicenseEvent{
+			LicenseID: licenseID,
+			EventType: models.LicenseEventCreated,
+			Changes:   utils.DiffLicenseSnapshots(nil, snapshot),
+			Reason:    "Self-service trial",
+			ActorID:   "system",
+			ActorName: "System",
+		})
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record license history", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create trial license", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"license_id": licenseID,
+		"product_id": req.ProductID,
+		"email":      req.CustomerEmail,
+		"trial_days": days,
+	}).Info("Trial license issued")
+
+	utils.LogDeviceActivity(deviceID, licenseID, models.DeviceActionActivated, "Trial device activated")
+
+	productID := req.ProductID
+	license := models.License{
+		ID:          licenseID,
+		LicenseKey:  licenseKey,
+		ProductID:   &productID,
+		ProductName: productName,
+		LicenseType: models.LicenseTypeNodeLocked,
+		IsTrial:     true,
+		ExpiresAt:   expiresAt,
+		Status:      models.LicenseStatusActive,
+	}
+	lifecycle := utils.ResolveLicenseLifecycle(nil, nil, nil)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.SuccessResponse("Trial license issued", map[string]interface{}{
+		"license_key":      licenseKey,
+		"device_id":        deviceID,
+		"product_id":       productID,
+		"product_name":     productName,
+		"expires_at":       expiresAt,
+		"is_trial":         true,
+		"max_offline_days": lifecycle.MaxOfflineDays,
+		"certificate":      issueLicenseCertificate(license, deviceID, fingerprint, nil, nil, lifecycle),
+	}))
+}
+
+func writeTrialAlreadyUsed(w http.ResponseWriter, requestID interface{}, req models.TrialRequest) {
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"product_id": req.ProductID,
+		"email":      req.CustomerEmail,
+	}).Warn("Trial already claimed")
+
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(models.ErrorResponse("Trial already used for this device or email", nil))
+}
+
+// trialClaimedByDevice는 같은 제품의 이전 체험판 기록 중 구성요소가 임계값 이상 일치하는 디바이스가 있는지 확인합니다.
+func trialClaimedByDevice(tx *sql.Tx, productID string, components utils.FingerprintComponents, threshold int) (bool, error) {
+	rows, err := tx.Query(`SELECT component_hashes FROM trial_claims
+		WHERE product_id = ? AND component_hashes IS NOT NULL`, productID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return false, err
+		}
+		claimed, err := utils.ParseFingerprintComponents(raw)
+		if err != nil {
+			continue
+		}
+		if score, _ := claimed.MatchScore(components); score >= threshold {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
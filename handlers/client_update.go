@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// CheckForUpdate 업데이트 확인
+// 클라이언트의 현재 버전, 운영체제/아키텍처, 채널을 기준으로 설치할 수 있는 가장 최신 릴리스를 찾습니다.
+// 릴리스(product_releases) 중 같은 버전이 태그된 활성 제품 파일이 있는 것만 후보가 되며,
+// 라이선스에 유지보수 종료일이 있으면 그날까지 출시된 릴리스만 제공합니다.
+// 라이선스나 디바이스에 지정된 릴리스 채널이 있으면 요청한 채널 대신 그 채널을 사용하고,
+// 단계적 배포 중인 파일은 디바이스 ID 해시로 배정된 구간이 배포 비율 안에 있을 때만 제공합니다.
+// @Summary 업데이트 확인
+// @Description 현재 버전보다 새로운 릴리스 중 이 플랫폼용 파일이 있고 유지보수 기간에 포함되는 가장 최신 버전을 서명된 다운로드 URL, 체크섬, 릴리스 노트와 함께 반환합니다.
+// @Description 현재 버전과 반환된 버전 사이에 필수(mandatory) 릴리스가 있으면 release.mandatory가 true입니다. beta 채널은 stable 릴리스도 함께 받습니다.
+// @Description 현재 버전의 배포가 롤백되었으면 rollback=true와 함께 사용할 수 있는 이전 버전을 반환합니다. internal 채널은 관리자가 지정한 라이선스/디바이스만 사용할 수 있습니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateCheckRequest true "업데이트 확인 요청 본문"
+// @Success 200 {object} models.APIResponse{data=models.UpdateCheckResult} "확인 결과 (update_available=false이면 최신 버전)"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "라이선스 비활성 또는 디바이스 미활성화"
+// @Failure 404 {object} models.APIResponse "라이선스를 찾을 수 없음"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/update-check [post]
+func CheckForUpdate(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("request_id")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	if keyErr := normalizeLicenseKey(requestID, &req.LicenseKey); keyErr != nil {
+		keyErr.write(w)
+		return
+	}
+
+	currentVersion, err := utils.ParseSemver(req.CurrentVersion)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("current_version must be a semantic version (e.g. 2.3.0)", err))
+		return
+	}
+	req.OS = utils.NormalizeOS(req.OS)
+	req.Arch = utils.NormalizeArch(req.Arch)
+	if req.OS == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("os is required", nil))
+		return
+	}
+	req.Channel = strings.ToLower(strings.TrimSpace(req.Channel))
+	if req.Channel == "" {
+		req.Channel = models.ReleaseChannelStable
+	}
+	if !models.IsValidReleaseChannel(req.Channel) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("channel must be stable, beta or internal", nil))
+		return
+	}
+
+	var license models.License
+	err = database.DB.QueryRow(`SELECT id, license_key, product_id, policy_id, license_type, expires_at, status,
+		grace_period_days, max_offline_days, DATE_FORMAT(maintenance_until, '%Y-%m-%d')
+		FROM licenses WHERE license_key = ?`, utils.LicenseKeyLookup(req.LicenseKey)).Scan(
+		&license.ID, &license.LicenseKey, &license.ProductID, &license.PolicyID, &license.LicenseType,
+		&license.ExpiresAt, &license.Status, &license.GracePeriodDays, &license.MaxOfflineDays, &license.MaintenanceUntil,
+	)
+	license.LicenseKey = req.LicenseKey
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query license", err))
+		return
+	}
+
+	productID := stringValue(license.ProductID)
+	req.ProductID = strings.TrimSpace(req.ProductID)
+	if req.ProductID != "" && req.ProductID != productID {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License is not issued for this product", nil))
+		return
+	}
+
+	startLicenseIfDue(database.DB, &license)
+	if !licenseUsable(license) {
+		resp := models.ErrorResponse(inactiveLicenseMessage(license.Status), nil)
+		resp.Data = inactiveLicenseData(license.ID, license.Status)
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	// 다운로드 URL은 라이선스를 사용 중인 디바이스에만 발급합니다.
+	fingerprint := utils.GenerateDeviceFingerprint(
+		req.DeviceInfo.ClientID,
+		req.DeviceInfo.CPUID,
+		req.DeviceInfo.MotherboardSN,
+		req.DeviceInfo.MACAddress,
+		req.DeviceInfo.DiskSerial,
+		req.DeviceInfo.MachineID,
+	)
+	// 단계적 배포 구간은 디바이스 ID로 정합니다. 플로팅 라이선스는 디바이스 활성화가 없으므로 핑거프린트를 사용합니다.
+	deviceID, rolloutKey := "", fingerprint
+	if license.LicenseType == models.LicenseTypeFloating {
+		var leaseCount int
+		err = database.DB.QueryRow(`SELECT COUNT(*) FROM license_leases
+			WHERE license_id = ? AND device_fingerprint = ? AND status = ? AND expires_at > ?`,
+			license.ID, fingerprint, models.LeaseStatusActive, utils.FormatDateTimeForDB(utils.NowSeoul())).Scan(&leaseCount)
+		if err == nil && leaseCount == 0 {
+			err = sql.ErrNoRows
+		}
+	} else {
+		deviceID, err = findActiveDevice(license.ID, license.ProductID, license.PolicyID, req.DeviceInfo, fingerprint)
+		rolloutKey = deviceID
+	}
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Device not activated", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify device", err))
+		return
+	}
+
+	// 관리자가 지정한 채널이 클라이언트가 요청한 채널보다 우선합니다.
+	if assigned := assignedReleaseChannel(license.ID, deviceID); assigned != "" {
+		req.Channel = assigned
+	} else if req.Channel == models.ReleaseChannelInternal {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Internal channel is not assigned to this license or device", nil))
+		return
+	}
+
+	releases, err := utils.LoadProductReleases(productID, "")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load product releases", err))
+		return
+	}
+	files, rolledBack, err := loadReleaseFiles(productID, req.OS, req.Arch, req.Channel, rolloutKey)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load release files", err))
+		return
+	}
+
+	result := models.UpdateCheckResult{
+		CurrentVersion:   currentVersion.String(),
+		Channel:          req.Channel,
+		OS:               req.OS,
+		Arch:             req.Arch,
+		MaintenanceUntil: license.MaintenanceUntil,
+	}
+
+	// releases는 버전 오름차순이므로 마지막으로 찾은 후보가 가장 최신입니다.
+	var target *models.ProductRelease
+	for i := range releases {
+		release := releases[i]
+		if !models.ReleaseChannelIncludes(req.Channel, release.Channel) || utils.CompareVersions(release.Version, result.CurrentVersion) <= 0 {
+			continue
+		}
+		if len(files[release.Version]) == 0 {
+			continue
+		}
+		version := release.Version
+		result.LatestVersion = &version
+		if utils.ReleaseCovered(release, license.MaintenanceUntil) {
+			target = &releases[i]
+		}
+	}
+
+	// 현재 버전의 배포가 롤백되었고 더 새로운 버전도 없으면 사용할 수 있는 가장 최신의 이전 버전으로 되돌리도록 안내합니다.
+	if target == nil && rolledBack[result.CurrentVersion] && len(files[result.CurrentVersion]) == 0 {
+		for i := range releases {
+			release := releases[i]
+			if models.ReleaseChannelIncludes(req.Channel, release.Channel) && utils.CompareVersions(release.Version, result.CurrentVersion) < 0 &&
+				len(files[release.Version]) > 0 && utils.ReleaseCovered(release, license.MaintenanceUntil) {
+				target = &releases[i]
+			}
+		}
+		result.Rollback = target != nil
+	}
+
+	if target != nil {
+		mandatory := result.Rollback
+		for _, release := range releases {
+			if release.Mandatory && models.ReleaseChannelIncludes(req.Channel, release.Channel) &&
+				utils.CompareVersions(release.Version, result.CurrentVersion) > 0 &&
+				utils.CompareVersions(release.Version, target.Version) <= 0 {
+				mandatory = true
+				break
+			}
+		}
+
+		result.UpdateAvailable = true
+		result.Release = &models.UpdateRelease{
+			Version:     target.Version,
+			Channel:     target.Channel,
+			ReleaseDate: target.ReleaseDate,
+			Notes:       target.Notes,
+			Mandatory:   mandatory,
+			Files:       files[target.Version],
+		}
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":       requestID,
+		"license_id":       license.ID,
+		"current_version":  result.CurrentVersion,
+		"channel":          req.Channel,
+		"os":               req.OS,
+		"arch":             req.Arch,
+		"update_available": result.UpdateAvailable,
+		"rollback":         result.Rollback,
+	}).Debug("Update check")
+
+	message := "No update available"
+	switch {
+	case result.Rollback:
+		message = "Rollback required"
+	case result.UpdateAvailable:
+		message = "Update available"
+	}
+	json.NewEncoder(w).Encode(models.SuccessResponse(message, result))
+}
+
+// loadReleaseFiles 버전이 태그된 활성 제품 파일 중 운영체제/아키텍처가 맞는 파일을 버전별로 묶어 반환합니다.
+// os, arch가 비어 있는 파일은 모든 플랫폼용으로 봅니다. 채널에 포함되지 않거나 단계적 배포 대상이 아닌 파일은 제외하고,
+// 롤백된 파일이 있는 버전은 rolledBack에 표시합니다.
+func loadReleaseFiles(productID, osName, arch, channel, rolloutKey string) (map[string][]models.ProductFileResponse, map[string]bool, error) {
+	rows, err := database.DB.Query(`SELECT pf.id, pf.file_id, pf.label, COALESCE(pf.description, ''), pf.sort_order,
+		COALESCE(pf.delivery_url, ''), pf.updated_at, pf.version, COALESCE(pf.os, ''), COALESCE(pf.arch, ''),
+		pf.channel, pf.rollout_percentage, pf.rollout_status,
+		f.mime_type, f.file_size, COALESCE(f.checksum, ''), f.storage_path
+		FROM product_files pf
+		JOIN files f ON pf.file_id = f.id
+		WHERE pf.product_id = ? AND pf.is_active = 1 AND pf.version IS NOT NULL
+			AND (pf.os IS NULL OR pf.os = ?) AND (pf.arch IS NULL OR pf.arch = ?)
+		ORDER BY pf.sort_order ASC, pf.created_at DESC`, productID, osName, arch)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[string][]models.ProductFileResponse)
+	rolledBack := make(map[string]bool)
+	for rows.Next() {
+		var (
+			item              models.ProductFileResponse
+			rolloutPercentage int
+			rolloutStatus     string
+		)
+		if err := rows.Scan(&item.ID, &item.FileID, &item.Label, &item.Description, &item.SortOrder,
+			&item.DeliveryURL, &item.UpdatedAt, &item.Version, &item.OS, &item.Arch,
+			&item.Channel, &rolloutPercentage, &rolloutStatus,
+			&item.MimeType, &item.FileSize, &item.Checksum, &item.StoragePath); err != nil {
+			return nil, nil, err
+		}
+		if !models.ReleaseChannelIncludes(channel, item.Channel) {
+			continue
+		}
+		if rolloutStatus == models.RolloutStatusRolledBack {
+			rolledBack[item.Version] = true
+			continue
+		}
+		if !utils.InRollout(rolloutStatus, rolloutPercentage, rolloutKey, utils.RolloutSalt(productID, item.Version, item.ID)) {
+			continue
+		}
+		applyProductFileURLs(&item, productID)
+		files[item.Version] = append(files[item.Version], item)
+	}
+	return files, rolledBack, rows.Err()
+}
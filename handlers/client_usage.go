@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// ReportUsage 사용량 보고
+// 활성화된 디바이스(플로팅 라이선스는 임대 중인 디바이스)가 측정 항목의 사용량 증가분을 보고합니다.
+// 같은 event_id로 다시 보고하면 집계하지 않고 현재 사용량만 반환합니다.
+// @Summary 사용량 보고
+// @Description 측정 항목(metric)의 사용량을 보고합니다. event_id 기준으로 중복 보고는 한 번만 집계되며, 한도를 넘는 보고는 거부됩니다.
+// @Tags 라이선스-클라이언트
+// @Accept json
+// @Produce json
+// @Param request body models.UsageReportRequest true "사용량 보고 본문"
+// @Success 201 {object} models.APIResponse "집계 성공"
+// @Success 200 {object} models.APIResponse "이미 집계된 이벤트"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "라이선스 비활성/디바이스 미활성화/한도 초과"
+// @Failure 404 {object} models.APIResponse "라이선스를 찾을 수 없음"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/usage [post]
+func ReportUsage(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("request_id")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UsageReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	if keyErr := normalizeLicenseKey(r.Context().Value("request_id"), &req.LicenseKey); keyErr != nil {
+		keyErr.write(w)
+		return
+	}
+
+	req.EventID = strings.TrimSpace(req.EventID)
+	req.Metric = strings.ToLower(strings.TrimSpace(req.Metric))
+	if req.EventID == "" || len(req.EventID) > 100 || !featureKeyPattern.MatchString(req.Metric) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("event_id and a valid metric are required", nil))
+		return
+	}
+	if req.Quantity <= 0 {
+		req.Quantity = 1
+	}
+
+	var (
+		license     models.License
+		usageQuotas sql.NullString
+	)
+	err := database.DB.QueryRow(`SELECT id, license_key, product_id, policy_id, license_type, expires_at, status,
+		grace_period_days, max_offline_days, usage_quotas
+		FROM licenses WHERE license_key = ?`, utils.LicenseKeyLookup(req.LicenseKey)).Scan(
+		&license.ID, &license.LicenseKey, &license.ProductID, &license.PolicyID, &license.LicenseType,
+		&license.ExpiresAt, &license.Status, &license.GracePeriodDays, &license.MaxOfflineDays, &usageQuotas,
+	)
+	license.LicenseKey = req.LicenseKey
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query license", err))
+		return
+	}
+
+	startLicenseIfDue(database.DB, &license)
+	if !licenseUsable(license) {
+		resp := models.ErrorResponse(inactiveLicenseMessage(license.Status), nil)
+		resp.Data = inactiveLicenseData(license.ID, license.Status)
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	fingerprint := utils.GenerateDeviceFingerprint(
+		req.DeviceInfo.ClientID,
+		req.DeviceInfo.CPUID,
+		req.DeviceInfo.MotherboardSN,
+		req.DeviceInfo.MACAddress,
+		req.DeviceInfo.DiskSerial,
+		req.DeviceInfo.MachineID,
+	)
+
+	// 플로팅 라이선스는 디바이스 활성화 대신 유효한 임대가 있어야 합니다.
+	var deviceID *string
+	if license.LicenseType == models.LicenseTypeFloating {
+		var leaseCount int
+		err = database.DB.QueryRow(`SELECT COUNT(*) FROM license_leases
+			WHERE license_id = ? AND device_fingerprint = ? AND status = ? AND expires_at > ?`,
+			license.ID, fingerprint, models.LeaseStatusActive, utils.FormatDateTimeForDB(utils.NowSeoul())).Scan(&leaseCount)
+		if err == nil && leaseCount == 0 {
+			err = sql.ErrNoRows
+		}
+	} else {
+		var id string
+		id, err = findActiveDevice(license.ID, license.ProductID, license.PolicyID, req.DeviceInfo, fingerprint)
+		deviceID = &id
+	}
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Device not activated", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to verify device", err))
+		return
+	}
+
+	quotas := utils.ResolveUsageQuotas(nullStringPtr(usageQuotas), license.PolicyID)
+	quota, limited := quotas[req.Metric]
+	period := models.UsagePeriodMonthly
+	if limited {
+		period = quota.Period
+	}
+	now := utils.NowSeoul()
+	nowStr := utils.FormatDateTimeForDB(now)
+	periodKey := utils.UsagePeriodKey(period, now)
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	// 집계 행을 만들고 잠가 동시 보고가 한도를 함께 넘지 않도록 합니다.
+	if _, err := tx.Exec(`INSERT IGNORE INTO usage_counters (license_id, metric, period_key, used, updated_at)
+		VALUES (?, ?, ?, 0, ?)`, license.ID, req.Metric, periodKey, nowStr); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record usage", err))
+		return
+	}
+	var used int64
+	if err := tx.QueryRow(`SELECT used FROM usage_counters WHERE license_id = ? AND metric = ? AND period_key = ? FOR UPDATE`,
+		license.ID, req.Metric, periodKey).Scan(&used); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record usage", err))
+		return
+	}
+
+	eventRowID, _ := utils.GenerateID("use")
+	if _, err := tx.Exec(`INSERT INTO usage_events (id, license_id, device_id, event_id, metric, quantity, period_key, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		eventRowID, license.ID, deviceID, req.EventID, req.Metric, req.Quantity, periodKey, nowStr,
+	); err != nil {
+		if strings.Contains(err.Error(), "Duplicate") {
+			// 이미 집계된 이벤트이므로 현재 사용량만 반환합니다.
+			tx.Rollback()
+			json.NewEncoder(w).Encode(models.SuccessResponse("Usage event already recorded", map[string]interface{}{
+				"event_id":  req.EventID,
+				"duplicate": true,
+				"usage":     loadUsageStatus(license.ID, req.Metric, quotaPtr(quota, limited), now),
+			}))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record usage", err))
+		return
+	}
+
+	if limited && used+req.Quantity > quota.Limit {
+		tx.Rollback()
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"license_id": license.ID,
+			"metric":     req.Metric,
+			"used":       used,
+			"limit":      quota.Limit,
+		}).Warn("Usage quota exceeded")
+
+		resp := models.ErrorResponse("Usage quota exceeded", nil)
+		resp.Data = map[string]interface{}{
+			"usage": buildUsageStatus(req.Metric, &quota, used, now),
+		}
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	if _, err := tx.Exec(`UPDATE usage_counters SET used = used + ?, updated_at = ?
+		WHERE license_id = ? AND metric = ? AND period_key = ?`,
+		req.Quantity, nowStr, license.ID, req.Metric, periodKey); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record usage", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record usage", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"license_id": license.ID,
+		"metric":     req.Metric,
+		"quantity":   req.Quantity,
+		"period_key": periodKey,
+	}).Debug("Usage recorded")
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.SuccessResponse("Usage recorded", map[string]interface{}{
+		"event_id":  req.EventID,
+		"duplicate": false,
+		"usage":     buildUsageStatus(req.Metric, quotaPtr(quota, limited), used+req.Quantity, now),
+	}))
+}
+
+// licenseUsable는 라이선스가 활성 상태이거나 만료 후 유예 기간 내인지 확인합니다.
+func licenseUsable(license models.License) bool {
+	if license.Status != models.LicenseStatusActive && license.Status != models.LicenseStatusGrace {
+		return false
+	}
+	if !license.IsExpired() {
+		return true
+	}
+	lifecycle := utils.ResolveLicenseLifecycle(license.GracePeriodDays, license.MaxOfflineDays, license.PolicyID)
+	endsAt, err := lifecycle.GraceEndsAt(license.ExpiresAt)
+	return err == nil && lifecycle.GracePeriodDays > 0 && utils.NowSeoul().Before(endsAt)
+}
+
+func quotaPtr(quota models.UsageQuota, limited bool) *models.UsageQuota {
+	if !limited {
+		return nil
+	}
+	return &quota
+}
+
+// buildUsageStatus는 측정 항목의 현재 기간 사용량과 남은 한도를 계산합니다. quota가 nil이면 한도 없이 월 단위로 집계합니다.
+func buildUsageStatus(metric string, quota *models.UsageQuota, used int64, now time.Time) models.UsageStatus {
+	period := models.UsagePeriodMonthly
+	if quota != nil {
+		period = quota.Period
+	}
+
+	status := models.UsageStatus{
+		Metric:    metric,
+		Period:    period,
+		PeriodKey: utils.UsagePeriodKey(period, now),
+		Used:      used,
+	}
+	if quota != nil {
+		limit := quota.Limit
+		remaining := limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		status.Limit = &limit
+		status.Remaining = &remaining
+	}
+	if resetsAt, ok := utils.UsagePeriodResetsAt(period, now); ok {
+		value := resetsAt.Format(time.RFC3339)
+		status.ResetsAt = &value
+	}
+	return status
+}
+
+// loadUsageStatus는 측정 항목 하나의 현재 기간 사용량을 조회합니다.
+func loadUsageStatus(licenseID, metric string, quota *models.UsageQuota, now time.Time) models.UsageStatus {
+	status := buildUsageStatus(metric, quota, 0, now)
+
+	var used int64
+	err := database.DB.QueryRow(`SELECT used FROM usage_counters WHERE license_id = ? AND metric = ? AND period_key = ?`,
+		licenseID, metric, status.PeriodKey).Scan(&used)
+	if err != nil && err != sql.ErrNoRows {
+		logger.WithFields(map[string]interface{}{
+			"license_id": licenseID,
+			"metric":     metric,
+			"error":      err.Error(),
+		}).Error("Failed to load usage counter")
+	}
+	return buildUsageStatus(metric, quota, used, now)
+}
+
+// loadUsageForLicense는 한도가 정의된 모든 측정 항목의 현재 사용량을 반환합니다.
+func loadUsageForLicense(licenseID string, quotas map[string]models.UsageQuota) []models.UsageStatus {
+	metrics := make([]string, 0, len(quotas))
+	for metric := range quotas {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+
+	now := utils.NowSeoul()
+	usage := make([]models.UsageStatus, 0, len(metrics))
+	for _, metric := range metrics {
+		quota := quotas[metric]
+		usage = append(usage, loadUsageStatus(licenseID, metric, &quota, now))
+	}
+	return usage
+}
@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/services"
+	"studiolicense/utils"
+)
+
+var customerService services.CustomerService
+
+// SetCustomerService는 라이선스 발급 시 고객 연결에 사용할 고객 서비스를 주입한다.
+func SetCustomerService(svc services.CustomerService) {
+	customerService = svc
+}
+
+// CustomerHandler는 고객 관련 HTTP 요청을 처리한다.
+type CustomerHandler struct {
+	service       services.CustomerService
+	scopeResolver services.ResourceScopeResolver
+}
+
+// NewCustomerHandler는 고객 핸들러를 생성한다.
+func NewCustomerHandler(service services.CustomerService, resolver services.ResourceScopeResolver) *CustomerHandler {
+	return &CustomerHandler{
+		service:       service,
+		scopeResolver: resolver,
+	}
+}
+
+// Create 고객 생성
+// @Summary 고객 생성
+// @Description 새로운 고객(조직)을 등록합니다. 이메일과 외부 ID는 고객별로 고유합니다.
+// @Tags 고객
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateCustomerRequest true "고객 정보"
+// @Success 201 {object} models.APIResponse{data=models.Customer} "생성 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 401 {object} models.APIResponse "인증 필요"
+// @Failure 409 {object} models.APIResponse "중복 이메일 또는 외부 ID"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/customers [post]
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	creatorID, _ := r.Context().Value("admin_id").(string)
+	if strings.TrimSpace(creatorID) == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Missing creator context", nil))
+		return
+	}
+
+	customer, err := h.service.Create(r.Context(), req, creatorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCustomer):
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Customer name and a valid email are required", nil))
+		case errors.Is(err, services.ErrCustomerConflict):
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(models.ErrorResponse("이미 등록된 고객 이메일 또는 외부 ID입니다", nil))
+		default:
+			logger.WithFields(map[string]interface{}{"error": err.Error(), "email": req.Email}).Error("Failed to create customer")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create customer", err))
+		}
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"customer_id": customer.ID,
+		"email":       customer.Email,
+	}).Info("Customer created")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.SuccessResponse("Customer created successfully", customer))
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionCreateCustomer, "Customer created: "+customer.ID)
+	}
+}
+
+// List 고객 목록 조회
+// @Summary 고객 목록 조회
+// @Description 접근 가능한 고객 목록과 고객별 라이선스 수를 조회합니다
+// @Tags 고객
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "검색어 (이름, 회사, 이메일, 외부 ID)"
+// @Success 200 {object} models.APIResponse{data=[]models.Customer} "조회 성공"
+// @Failure 401 {object} models.APIResponse "인증 필요"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/customers [get]
+func (h *CustomerHandler) List(w http.ResponseWriter, r *http.Request) {
+	role := getRole(r)
+	adminID := getAdminID(r)
+	scope, isSuper, err := h.scopeResolver.Resolve(r.Context(), role, adminID, models.ResourceTypeCustomers)
+	if err != nil {
+		logger.Error("Failed to evaluate customer permissions: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to evaluate customer permissions", err))
+		return
+	}
+
+	customers, err := h.service.List(r.Context(), services.CustomerFilter{
+		Search:  r.URL.Query().Get("search"),
+		Scope:   scope,
+		IsSuper: isSuper,
+		AdminID: adminID,
+	})
+	if err != nil {
+		logger.Error("Failed to query customers: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to query customers", err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Customers retrieved", customers))
+}
+
+// Get 고객 상세 조회
+// @Summary 고객 상세 조회
+// @Description 특정 고객의 상세 정보와 담당자 목록을 조회합니다. 연결된 라이선스는 /api/admin/licenses?customer_id= 로 조회합니다.
+// @Tags 고객
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id query string true "고객 ID"
+// @Success 200 {object} models.APIResponse{data=models.Customer} "조회 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "고객 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/customers/ [get]
+func (h *CustomerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if strings.TrimSpace(id) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Customer ID is required", nil))
+		return
+	}
+
+	customer, ok := h.authorize(w, r, id)
+	if !ok {
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Customer retrieved", customer))
+}
+
+// Update 고객 수정
+// @Summary 고객 수정
+// @Description 고객 정보를 수정합니다. 이름/이메일 변경은 연결된 라이선스에도 반영됩니다.
+// @Tags 고객
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id query string true "고객 ID"
+// @Param request body models.UpdateCustomerRequest true "수정할 정보"
+// @Success 200 {object} models.APIResponse "수정 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "고객 없음"
+// @Failure 409 {object} models.APIResponse "중복 이메일 또는 외부 ID"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/customers/ [put]
+func (h *CustomerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if strings.TrimSpace(id) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Customer ID is required", nil))
+		return
+	}
+
+	var req models.UpdateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	if _, ok := h.authorize(w, r, id); !ok {
+		return
+	}
+
+	err := h.service.Update(r.Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCustomer):
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Customer name and a valid email are required", nil))
+		case errors.Is(err, services.ErrCustomerConflict):
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(models.ErrorResponse("이미 등록된 고객 이메일 또는 외부 ID입니다", nil))
+		case errors.Is(err, services.ErrCustomerNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Customer not found", nil))
+		default:
+			logger.WithFields(map[string]interface{}{
+				"error":       err.Error(),
+				"customer_id": id,
+			}).Error("Failed to update customer")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update customer", err))
+		}
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{"customer_id": id}).Info("Customer updated")
+	json.NewEncoder(w).Encode(models.SuccessResponse("Customer updated successfully", nil))
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionUpdateCustomer, "Customer updated: "+id)
+	}
+}
+
+// Delete 고객 삭제
+// @Summary 고객 삭제
+// @Description 고객을 삭제합니다. 연결된 라이선스가 있으면 삭제가 제한됩니다.
+// @Tags 고객
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id query string true "고객 ID"
+// @Success 200 {object} models.APIResponse "삭제 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 403 {object} models.APIResponse "권한 없음"
+// @Failure 404 {object} models.APIResponse "고객 없음"
+// @Failure 409 {object} models.APIResponse "연결된 라이선스로 인해 삭제 불가"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/customers/ [delete]
+func (h *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if strings.TrimSpace(id) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Customer ID is required", nil))
+		return
+	}
+
+	if _, ok := h.authorize(w, r, id); !ok {
+		return
+	}
+
+	err := h.service.Delete(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCustomerLinkedLicenses):
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Cannot delete customer: linked licenses exist", nil))
+		case errors.Is(err, services.ErrCustomerNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Customer not found", nil))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to delete customer", err))
+		}
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{"customer_id": id}).Info("Customer deleted")
+	json.NewEncoder(w).Encode(models.SuccessResponse("Customer deleted successfully", nil))
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionDeleteCustomer, "Customer deleted: "+id)
+	}
+}
+
+// authorize는 관리자의 고객 리소스 스코프로 고객에 접근할 수 있는지 확인합니다.
+// 접근할 수 없으면 응답을 작성하고 false를 반환합니다.
+func (h *CustomerHandler) authorize(w http.ResponseWriter, r *http.Request, id string) (models.Customer, bool) {
+	role := getRole(r)
+	adminID := getAdminID(r)
+	scope, isSuper, err := h.scopeResolver.Resolve(r.Context(), role, adminID, models.ResourceTypeCustomers)
+	if err != nil {
+		logger.Error("Failed to evaluate customer permissions: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to evaluate customer permissions", err))
+		return models.Customer{}, false
+	}
+
+	if !isSuper && strings.EqualFold(scope.Mode, models.ResourceModeNone) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Forbidden: customer access denied", nil))
+		return models.Customer{}, false
+	}
+
+	customer, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, services.ErrCustomerNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Customer not found", nil))
+			return models.Customer{}, false
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to retrieve customer", err))
+		return models.Customer{}, false
+	}
+
+	if !isSuper && !utils.CanAccessResource(scope, customer.ID, customer.CreatedBy, adminID) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Forbidden: customer access denied", nil))
+		return models.Customer{}, false
+	}
+	return customer, true
+}
+
+// resolveLicenseCustomer는 라이선스에 연결할 고객을 찾거나 만들고, 지정된 고객이면 스코프 접근 권한을 확인합니다.
+// 고객 서비스가 없거나 연결할 정보가 없으면 빈 고객을 반환합니다. 실패하면 응답을 작성하고 false를 반환합니다.
+func resolveLicenseCustomer(w http.ResponseWriter, r *http.Request, customerID, name, email, creatorID string) (models.Customer, bool) {
+	if customerService == nil {
+		return models.Customer{}, true
+	}
+
+	customer, err := customerService.ResolveForLicense(r.Context(), customerID, name, email, creatorID)
+	if err != nil {
+		if errors.Is(err, services.ErrCustomerNotFound) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Customer not found", nil))
+			return models.Customer{}, false
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to resolve customer", err))
+		return models.Customer{}, false
+	}
+
+	if strings.TrimSpace(customerID) != "" {
+		scope, isSuper, adminID, err := resolveResourceScope(r, models.ResourceTypeCustomers)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to evaluate customer permissions", err))
+			return models.Customer{}, false
+		}
+		if !isSuper && !utils.CanAccessResource(scope, customer.ID, customer.CreatedBy, adminID) {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Forbidden: customer access denied", nil))
+			return models.Customer{}, false
+		}
+	}
+	return customer, true
+}
@@ -7,6 +7,7 @@ import (
 
 	"studiolicense/database"
 	"studiolicense/models"
+	"studiolicense/utils"
 )
 
 // GetDashboardStats 대시보드 통계
@@ -14,11 +15,13 @@ func GetDashboardStats(w http.ResponseWriter, r *http.Request) {
 	stats := make(map[string]interface{})
 
 	// 총 라이선스 수
-	var totalLicenses, activeLicenses, expiredLicenses, revokedLicenses int
+	var totalLicenses, activeLicenses, expiredLicenses, revokedLicenses, suspendedLicenses, scheduledLicenses int
 	database.DB.QueryRow("SELECT COUNT(*) FROM licenses").Scan(&totalLicenses)
 	database.DB.QueryRow("SELECT COUNT(*) FROM licenses WHERE status = ?", models.LicenseStatusActive).Scan(&activeLicenses)
 	database.DB.QueryRow("SELECT COUNT(*) FROM licenses WHERE status = ?", models.LicenseStatusExpired).Scan(&expiredLicenses)
 	database.DB.QueryRow("SELECT COUNT(*) FROM licenses WHERE status = ?", models.LicenseStatusRevoked).Scan(&revokedLicenses)
+	database.DB.QueryRow("SELECT COUNT(*) FROM licenses WHERE status = ?", models.LicenseStatusSuspended).Scan(&suspendedLicenses)
+	database.DB.QueryRow("SELECT COUNT(*) FROM licenses WHERE status = ?", models.LicenseStatusScheduled).Scan(&scheduledLicenses)
 
 	// 총 활성화된 디바이스 수
 	var totalDevices int
@@ -28,8 +31,26 @@ func GetDashboardStats(w http.ResponseWriter, r *http.Request) {
 	stats["active_licenses"] = activeLicenses
 	stats["expired_licenses"] = expiredLicenses
 	stats["revoked_licenses"] = revokedLicenses
+	stats["suspended_licenses"] = suspendedLicenses
+	stats["scheduled_licenses"] = scheduledLicenses
 	stats["total_active_devices"] = totalDevices
 
+	// 체험판 현황 및 정식 전환 수
+	var trialLicenses, trialConversions, recentConversions int
+	database.DB.QueryRow("SELECT COUNT(*) FROM licenses WHERE is_trial = 1").Scan(&trialLicenses)
+	database.DB.QueryRow("SELECT COUNT(*) FROM licenses WHERE converted_at IS NOT NULL").Scan(&trialConversions)
+	database.DB.QueryRow("SELECT COUNT(*) FROM licenses WHERE converted_at >= ?",
+		utils.FormatDateTimeForDB(utils.NowSeoul().AddDate(0, 0, -30))).Scan(&recentConversions)
+
+	stats["trial_licenses"] = trialLicenses
+	stats["trial_conversions"] = trialConversions
+	stats["trial_conversions_30d"] = recentConversions
+	if total := trialLicenses + trialConversions; total > 0 {
+		stats["trial_conversion_rate"] = float64(trialConversions) / float64(total)
+	} else {
+		stats["trial_conversion_rate"] = 0.0
+	}
+
 	json.NewEncoder(w).Encode(models.SuccessResponse("Dashboard stats retrieved", stats))
 }
 
@@ -48,7 +69,7 @@ func GetRecentActivities(w http.ResponseWriter, r *http.Request) {
 	// 기본 쿼리 구성 (MySQL/SQLite 모두 호환)
 	baseDevice := `SELECT 'device' AS type, al.action, al.details, al.created_at AS created_at,
 			   CAST(al.id AS CHAR) AS sort_id,
-			   l.license_key, l.customer_name, COALESCE(prod.name, '') AS product_name,
+			   ` + utils.LicenseKeyDisplaySQL("l.license_key") + ` AS license_key, l.customer_name, COALESCE(prod.name, '') AS product_name,
 			   d.device_name, d.device_fingerprint,
 			   '' AS admin_username
 		FROM device_activity_logs al
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// fingerprintThreshold는 부분 일치를 허용할 최소 점수를 결정합니다.
+// 우선순위: 정책 데이터의 fingerprint_threshold → 제품 설정 → FINGERPRINT_MATCH_THRESHOLD(기본 4)
+func fingerprintThreshold(productID, policyID *string) int {
+	threshold := utils.GetEnvInt("FINGERPRINT_MATCH_THRESHOLD", 4)
+
+	if productID != nil && *productID != "" {
+		var value sql.NullInt64
+		if err := database.DB.QueryRow("SELECT fingerprint_threshold FROM products WHERE id = ?", *productID).Scan(&value); err == nil && value.Valid {
+			threshold = int(value.Int64)
+		}
+	}
+
+	if value, ok := utils.PolicyDataInt(policyID, "fingerprint_threshold"); ok {
+		threshold = value
+	}
+
+	if max := utils.FingerprintMaxScore(); threshold <= 0 || threshold > max {
+		threshold = max
+	}
+	return threshold
+}
+
+// findActiveDevice는 라이선스에 활성화된 디바이스를 찾습니다.
+// 전체 핑거프린트가 일치하지 않으면 구성요소별 해시를 비교해 임계값 이상 일치하는 디바이스를 찾고,
+// 찾은 경우 저장된 핑거프린트를 갱신한 뒤 변경 내역을 디바이스 활동 로그에 남깁니다.
+// 일치하는 디바이스가 없으면 sql.ErrNoRows를 반환합니다.
+func findActiveDevice(licenseID string, productID, policyID *string, info models.DeviceInfo, fingerprint string) (string, error) {
+	components := utils.GenerateFingerprintComponents(info)
+
+	var (
+		deviceID string
+		stored   sql.NullString
+	)
+	err := database.DB.QueryRow(
+		"SELECT id, component_hashes FROM device_activations WHERE license_id = ? AND device_fingerprint = ? AND status = ?",
+		licenseID, fingerprint, models.DeviceStatusActive,
+	).Scan(&deviceID, &stored)
+	if err == nil {
+		// 구성요소 해시 도입 이전에 활성화된 디바이스는 이번 검증 시 해시를 채워 둡니다.
+		if !stored.Valid || stored.String == "" {
+			database.DB.Exec("UPDATE device_activations SET component_hashes = ? WHERE id = ?", components.String(), deviceID)
+		}
+		return deviceID, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+
+	rows, err := database.DB.Query(
+		"SELECT id, component_hashes FROM device_activations WHERE license_id = ? AND status = ? AND component_hashes IS NOT NULL",
+		licenseID, models.DeviceStatusActive,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	threshold := fingerprintThreshold(productID, policyID)
+	bestScore := 0
+	var bestChanged []string
+	for rows.Next() {
+		var id, raw string
+		if err := rows.Scan(&id, &raw); err != nil {
+			rows.Close()
+			return "", err
+		}
+		candidate, err := utils.ParseFingerprintComponents(raw)
+		if err != nil {
+			continue
+		}
+		score, changed := candidate.MatchScore(components)
+		if score >= threshold && score > bestScore {
+			deviceID, bestScore, bestChanged = id, score, changed
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if deviceID == "" {
+		return "", sql.ErrNoRows
+	}
+
+	deviceInfoJSON, _ := json.Marshal(info)
+	if _, err := database.DB.Exec(
+		"UPDATE device_activations SET device_fingerprint = ?, component_hashes = ?, device_info = ? WHERE id = ?",
+		fingerprint, components.String(), string(deviceInfoJSON), deviceID,
+	); err != nil {
+		// 같은 핑거프린트의 비활성 레코드가 남아 있으면 갱신이 실패할 수 있으나 검증 자체는 허용합니다.
+		logger.WithFields(map[string]interface{}{
+			"device_id": deviceID,
+			"error":     err.Error(),
+		}).Warn("Failed to update drifted device fingerprint")
+	}
+
+	details := fmt.Sprintf("Fingerprint drift: %s (score %d/%d, threshold %d)",
+		strings.Join(bestChanged, ", "), bestScore, utils.FingerprintMaxScore(), threshold)
+	utils.LogDeviceActivity(deviceID, licenseID, models.DeviceActionFingerprintDrift, details)
+
+	logger.WithFields(map[string]interface{}{
+		"device_id":  deviceID,
+		"license_id": licenseID,
+		"changed":    bestChanged,
+		"score":      bestScore,
+	}).Info("Device matched with fingerprint drift")
+
+	return deviceID, nil
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"studiolicense/licensecert"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// issueLicenseCertificate는 활성화/검증 응답에 포함할 서명 인증서를 생성합니다.
+// 서명에 실패해도 온라인 검증 자체는 계속 진행할 수 있도록 nil을 반환하고 로그만 남깁니다.
+func issueLicenseCertificate(license models.License, deviceID, fingerprint string, policies []models.PolicyResponse, entitlements []models.EntitlementResponse, lifecycle utils.LicenseLifecycle) *licensecert.SignedCertificate {
+	cert := licensecert.Certificate{
+		Version:           licensecert.CurrentVersion,
+		LicenseID:         license.ID,
+		LicenseKey:        license.LicenseKey,
+		ProductID:         stringValue(license.ProductID),
+		ProductName:       license.ProductName,
+		DeviceID:          deviceID,
+		DeviceFingerprint: fingerprint,
+		IssuedAt:          utils.NowSeoul(),
+		GracePeriodDays:   lifecycle.GracePeriodDays,
+		MaxOfflineDays:    lifecycle.MaxOfflineDays,
+	}
+
+	// 만료일 당일까지 사용 가능하므로 다음 날 0시를 만료 시각으로 사용합니다.
+	if validUntil, err := utils.LicenseValidUntil(license.ExpiresAt); err == nil {
+		cert.ExpiresAt = validUntil
+	}
+
+	if len(policies) > 0 {
+		if raw, err := json.Marshal(policies); err == nil {
+			cert.Policies = raw
+		}
+	}
+	if len(entitlements) > 0 {
+		if raw, err := json.Marshal(entitlements); err == nil {
+			cert.Entitlements = raw
+		}
+	}
+
+	signed, err := utils.SignLicenseCertificate(cert)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"license_id": license.ID,
+			"device_id":  deviceID,
+			"error":      err.Error(),
+		}).Error("Failed to sign license certificate")
+		return nil
+	}
+	return &signed
+}
+
+// GetLicensePublicKeys 라이선스 인증서 검증용 공개키 목록
+// @Summary 라이선스 인증서 공개키 조회
+// @Description 오프라인 인증서 검증에 사용하는 Ed25519 공개키 목록을 반환합니다. 교체(retired)된 키도 포함됩니다.
+// @Tags 라이선스-클라이언트
+// @Produce json
+// @Success 200 {object} models.APIResponse{data=[]models.LicenseSigningKey} "조회 성공"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/public-keys [get]
+func GetLicensePublicKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	keys, err := utils.ListLicenseSigningKeys()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load public keys", err))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(models.SuccessResponse("Public keys retrieved", keys))
+}
+
+// GetRevocationList 서명된 폐기 목록
+// @Summary 라이선스 폐기 목록 조회
+// @Description 폐기된 라이선스 ID와 활성화가 해제된 디바이스 ID를 서버 서명 키로 서명해 반환합니다.
+// @Description 오프라인 클라이언트는 next_update 전에 다시 받아 보관 중인 인증서가 목록에 있는지 확인합니다. serial은 내용이 바뀔 때만 증가하며,
+// @Description If-None-Match에 이전 ETag를 보내면 내용이 같을 때 304를 반환합니다.
+// @Tags 라이선스-클라이언트
+// @Produce json
+// @Success 200 {object} models.APIResponse{data=licensecert.SignedRevocationList} "조회 성공"
+// @Success 304 "변경 없음"
+// @Failure 500 {object} models.APIResponse "서버 내부 오류"
+// @Router /api/license/revocations [get]
+func GetRevocationList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	list, err := utils.BuildRevocationList()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to build revocation list", err))
+		return
+	}
+
+	etag := fmt.Sprintf(`"crl-%d"`, list.Serial)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(utils.RevocationListTTL().Seconds())))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	signed, err := utils.SignRevocationList(list)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to sign revocation list", err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Revocation list retrieved", signed))
+}
+
+// ListSigningKeys 서명 키 목록 조회 (관리자)
+// @Summary 서명 키 목록 조회
+// @Description 라이선스 인증서 서명 키 목록과 상태를 조회합니다
+// @Tags 관리자 - 서명 키
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.LicenseSigningKey} "조회 성공"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/signing-keys [get]
+func ListSigningKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	keys, err := utils.ListLicenseSigningKeys()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load signing keys", err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Signing keys retrieved", keys))
+}
+
+// RotateSigningKey 서명 키 교체 (슈퍼 관리자)
+// @Summary 서명 키 교체
+// @Description 새 서명 키를 생성하고 기존 활성 키를 retired 상태로 전환합니다
+// @Tags 관리자 - 서명 키
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} models.APIResponse{data=models.LicenseSigningKey} "교체 성공"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/signing-keys/rotate [post]
+func RotateSigningKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, err := utils.RotateLicenseSigningKey()
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": r.Context().Value("request_id"),
+			"error":      err.Error(),
+		}).Error("Failed to rotate signing key")
+
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to rotate signing key", err))
+		return
+	}
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionRotateSigningKey, "Signing key rotated: "+key.ID)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.SuccessResponse("Signing key rotated", key))
+}
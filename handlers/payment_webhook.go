@@ -0,0 +1,532 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/services"
+	"studiolicense/utils"
+)
+
+// maxPaymentWebhookBody 결제 웹훅 본문 최대 크기 (1MB)
+const maxPaymentWebhookBody = 1 << 20
+
+var paymentProviders = services.NewPaymentProviderRegistry()
+
+// SetPaymentProviders는 결제 웹훅에 사용할 결제 어댑터 목록을 주입한다.
+func SetPaymentProviders(registry *services.PaymentProviderRegistry) {
+	paymentProviders = registry
+}
+
+// paymentEventOutcome 결제 이벤트 처리 결과
+type paymentEventOutcome struct {
+	result   string
+	licenses []models.License
+}
+
+// HandlePaymentWebhook 결제 서비스 웹훅 수신
+// @Summary 결제 웹훅 수신
+// @Description 결제 서비스의 이벤트로 라이선스를 자동 발급/갱신/폐기합니다. 서명은 결제 어댑터별 방식으로 검증합니다.
+// @Description order.created: 플랜 매핑에 따라 발급, subscription.renewed: 만료일 연장, order.refunded: 폐기, subscription.canceled: 일시 정지
+// @Description 같은 이벤트 ID는 한 번만 처리하며, 다시 받으면 처리하지 않고 이전 결과를 반환합니다.
+// @Tags 결제 연동
+// @Accept json
+// @Produce json
+// @Param provider path string true "결제 어댑터 이름 (예: generic)"
+// @Param X-Webhook-Timestamp header string false "generic 어댑터: 유닉스 시간(초)"
+// @Param X-Webhook-Signature header string false "generic 어댑터: hex(HMAC-SHA256(secret, timestamp + \".\" + body))"
+// @Success 200 {object} models.APIResponse "처리 성공 또는 이미 처리된 이벤트"
+// @Failure 400 {object} models.APIResponse "잘못된 본문"
+// @Failure 401 {object} models.APIResponse "서명 불일치"
+// @Failure 404 {object} models.APIResponse "설정되지 않은 결제 어댑터 또는 연결된 라이선스 없음"
+// @Failure 422 {object} models.APIResponse "플랜 매핑 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/webhooks/payments/{provider} [post]
+func HandlePaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("request_id")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/webhooks/payments/"), "/")
+	provider, ok := paymentProviders.Get(name)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Payment provider not configured", nil))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPaymentWebhookBody+1))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to read request body", err))
+		return
+	}
+	if len(body) > maxPaymentWebhookBody {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Request body too large", nil))
+		return
+	}
+
+	if err := provider.VerifyWebhook(r.Header, body); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"provider":   provider.Name(),
+			"error":      err.Error(),
+		}).Warn("Payment webhook signature rejected")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid webhook signature", err))
+		return
+	}
+
+	event, err := provider.ParseEvent(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid webhook payload", err))
+		return
+	}
+
+	// 주문 이벤트는 플랜 매핑과 고객을 트랜잭션 전에 확인합니다.
+	// 라이선스 트랜잭션이 실패해도 새로 만든 고객은 남지만 재전송된 이벤트에서 재사용됩니다.
+	var (
+		plan     models.PaymentPlan
+		customer models.Customer
+	)
+	if event.Type == models.PaymentEventOrderCreated {
+		plan, err = loadPaymentPlan(database.DB, provider.Name(), event.PlanCode)
+		if err == sql.ErrNoRows {
+			resp := models.ErrorResponse("No payment plan mapped for this plan code", nil)
+			resp.Data = map[string]interface{}{"provider": provider.Name(), "plan": event.PlanCode}
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load payment plan", err))
+			return
+		}
+		if event.CustomerEmail != "" && customerService != nil {
+			customer, err = customerService.ResolveForLicense(r.Context(), "", event.CustomerName, event.CustomerEmail, "system")
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(models.ErrorResponse("Failed to resolve customer", err))
+				return
+			}
+		}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to start transaction", err))
+		return
+	}
+	defer tx.Rollback()
+
+	// 이벤트를 먼저 기록해 같은 이벤트가 동시에 들어와도 한 번만 처리되도록 합니다.
+	// 처리에 실패하면 기록도 함께 취소되어 결제 서비스의 재전송 때 다시 처리됩니다.
+	result, err := tx.Exec(`INSERT INTO payment_events (provider, event_id, event_type, result, payload, received_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		provider.Name(), event.ID, event.Type, models.PaymentEventResultProcessing, string(body),
+		utils.FormatDateTimeForDB(utils.NowSeoul()),
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate") {
+			tx.Rollback()
+			writePaymentEventDuplicate(w, provider.Name(), event)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record payment event", err))
+		return
+	}
+	rowID, _ := result.LastInsertId()
+
+	outcome, requestErr := applyPaymentEvent(tx, provider.Name(), event, plan, customer)
+	if requestErr != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"provider":   provider.Name(),
+			"event_id":   event.ID,
+			"type":       event.Type,
+			"error":      requestErr.message,
+		}).Warn("Payment event not processed")
+		requestErr.write(w)
+		return
+	}
+
+	licenseIDs := make([]string, 0, len(outcome.licenses))
+	for _, license := range outcome.licenses {
+		licenseIDs = append(licenseIDs, license.ID)
+	}
+	licenseIDsJSON, _ := json.Marshal(licenseIDs)
+	if _, err := tx.Exec("UPDATE payment_events SET result = ?, license_ids = ? WHERE id = ?",
+		outcome.result, string(licenseIDsJSON), rowID); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to record payment event", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to process payment event", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":  requestID,
+		"provider":    provider.Name(),
+		"event_id":    event.ID,
+		"type":        event.Type,
+		"result":      outcome.result,
+		"license_ids": licenseIDs,
+	}).Info("Payment event processed")
+
+	if outcome.result == models.PaymentEventResultProcessed {
+		details := fmt.Sprintf("결제 이벤트: %s/%s (%s), 라이선스: %s",
+			provider.Name(), event.ID, event.Type, strings.Join(licenseIDs, ", "))
+		if event.OrderID != "" {
+			details += ", 주문: " + event.OrderID
+		}
+		utils.LogAdminActivity("system", "System", models.AdminActionPaymentWebhook, details)
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Payment event processed", map[string]interface{}{
+		"event_id": event.ID,
+		"type":     event.Type,
+		"result":   outcome.result,
+		"licenses": outcome.licenses,
+	}))
+}
+
+// writePaymentEventDuplicate 이미 받은 이벤트는 다시 처리하지 않고 이전 처리 결과를 반환합니다.
+func writePaymentEventDuplicate(w http.ResponseWriter, provider string, event models.PaymentEvent) {
+	var (
+		result     string
+		licenseIDs sql.NullString
+	)
+	database.DB.QueryRow("SELECT result, license_ids FROM payment_events WHERE provider = ? AND event_id = ?",
+		provider, event.ID).Scan(&result, &licenseIDs)
+
+	ids := []string{}
+	if licenseIDs.Valid {
+		json.Unmarshal([]byte(licenseIDs.String), &ids)
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Payment event already processed", map[string]interface{}{
+		"event_id":    event.ID,
+		"type":        event.Type,
+		"result":      result,
+		"duplicate":   true,
+		"license_ids": ids,
+	}))
+}
+
+// applyPaymentEvent 결제 이벤트 유형에 따라 라이선스를 발급하거나 상태를 바꿉니다.
+func applyPaymentEvent(tx *sql.Tx, provider string, event models.PaymentEvent,
+	plan models.PaymentPlan, customer models.Customer) (paymentEventOutcome, *clientRequestError) {
+	switch event.Type {
+	case models.PaymentEventOrderCreated:
+		return issuePaymentLicenses(tx, provider, event, plan, customer)
+	case models.PaymentEventSubscriptionRenewed:
+		return renewPaymentLicenses(tx, provider, event)
+	case models.PaymentEventOrderRefunded:
+		return transitionPaymentLicenses(tx, provider, event, models.LicenseEventRevoked, func(from string) utils.LicenseTransition {
+			return utils.LicenseTransition{From: from, To: models.LicenseStatusRevoked, RevocationReason: models.RevocationReasonRefund}
+		})
+	case models.PaymentEventSubscriptionCanceled:
+		return transitionPaymentLicenses(tx, provider, event, models.LicenseEventSuspended, func(from string) utils.LicenseTransition {
+			return utils.LicenseTransition{From: from, To: models.LicenseStatusSuspended, SuspensionReason: models.SuspensionReasonCustomerRequest}
+		})
+	}
+	return paymentEventOutcome{result: models.PaymentEventResultIgnored, licenses: []models.License{}}, nil
+}
+
+// issuePaymentLicenses 주문 수량만큼 플랜 매핑의 조건으로 라이선스를 발급합니다.
+func issuePaymentLicenses(tx *sql.Tx, provider string, event models.PaymentEvent,
+	plan models.PaymentPlan, customer models.Customer) (paymentEventOutcome, *clientRequestError) {
+	quantity := event.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if maxRows := utils.GetEnvInt("BULK_LICENSE_MAX_ROWS", 1000); quantity < 1 || quantity > maxRows {
+		return paymentEventOutcome{}, &clientRequestError{status: http.StatusBadRequest,
+			message: fmt.Sprintf("quantity must be between 1 and %d", maxRows)}
+	}
+	if event.OrderID == "" && event.SubscriptionID == "" {
+		return paymentEventOutcome{}, &clientRequestError{status: http.StatusBadRequest,
+			message: "order_id or subscription_id is required"}
+	}
+
+	now := utils.NowSeoul()
+	expiresAt := utils.FormatDateOnly(now.AddDate(0, 0, plan.DurationDays))
+	if event.PeriodEnd != "" {
+		value, err := parseLicenseExpiresAt(event.PeriodEnd)
+		if err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusBadRequest, message: "Invalid period_end date format", err: err}
+		}
+		expiresAt = value
+	}
+
+	customerName, customerEmail := event.CustomerName, event.CustomerEmail
+	var customerID *string
+	if customer.ID != "" {
+		customerID = &customer.ID
+		customerName, customerEmail = customer.Name, customer.Email
+	}
+	if customerName == "" {
+		customerName = customerEmail
+	}
+
+	keyTemplate, err := utils.LoadKeyTemplate(plan.ProductID)
+	if err != nil {
+		return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to load license key template", err: err}
+	}
+
+	nowStr := utils.FormatDateTimeForDB(now)
+	productID := plan.ProductID
+	notes := fmt.Sprintf("Payment %s order %s", provider, event.OrderID)
+	licenses := make([]models.License, 0, quantity)
+	for i := 0; i < quantity; i++ {
+		id, err := utils.GenerateID("lic")
+		if err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to generate ID", err: err}
+		}
+		licenseKey, err := keyTemplate.Generate()
+		if err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to generate license key", err: err}
+		}
+
+		// 결제로 발급된 라이선스는 관리자 소유가 아니므로 created_by를 system으로 기록합니다.
+		if _, err := tx.Exec(`INSERT INTO licenses (id, license_key, license_key_prefix, product_id, policy_id, customer_id, license_type,
+			customer_name, customer_email, max_devices, expires_at, status, created_by, notes,
+			payment_provider, payment_order_id, payment_subscription_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'system', ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)`,
+			id, utils.LicenseKeyLookup(licenseKey), utils.LicenseKeyPrefix(licenseKey, productID), productID, plan.PolicyID, customerID, plan.LicenseType,
+			customerName, customerEmail, plan.MaxDevices, expiresAt, models.LicenseStatusActive, notes,
+			provider, event.OrderID, event.SubscriptionID, nowStr, nowStr,
+		); err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to create license", err: err}
+		}
+
+		if err := recordPaymentLicenseEvent(tx, id, models.LicenseEventCreated, nil, provider, event); err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to record license history", err: err}
+		}
+
+		licenses = append(licenses, models.License{
+			ID:                    id,
+			LicenseKey:            licenseKey,
+			ProductID:             &productID,
+			PolicyID:              plan.PolicyID,
+			CustomerID:            customerID,
+			LicenseType:           plan.LicenseType,
+			ProductName:           plan.ProductName,
+			CustomerName:          customerName,
+			CustomerEmail:         customerEmail,
+			MaxDevices:            plan.MaxDevices,
+			ExpiresAt:             expiresAt,
+			Status:                models.LicenseStatusActive,
+			CreatedBy:             "system",
+			Notes:                 notes,
+			PaymentProvider:       &provider,
+			PaymentOrderID:        optionalString(event.OrderID),
+			PaymentSubscriptionID: optionalString(event.SubscriptionID),
+			CreatedAt:             nowStr,
+			UpdatedAt:             nowStr,
+		})
+	}
+
+	return paymentEventOutcome{result: models.PaymentEventResultProcessed, licenses: licenses}, nil
+}
+
+// renewPaymentLicenses 구독에 연결된 라이선스의 만료일을 결제된 기간 끝으로 연장합니다.
+// period_end가 없으면 플랜의 duration_days를 오늘과 기존 만료일 중 늦은 날부터 더합니다.
+// 새 만료일이 기존보다 이르면 줄이지 않습니다.
+func renewPaymentLicenses(tx *sql.Tx, provider string, event models.PaymentEvent) (paymentEventOutcome, *clientRequestError) {
+	var periodEnd string
+	if event.PeriodEnd != "" {
+		value, err := parseLicenseExpiresAt(event.PeriodEnd)
+		if err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusBadRequest, message: "Invalid period_end date format", err: err}
+		}
+		periodEnd = value
+	}
+	durationDays := 0
+	if periodEnd == "" {
+		plan, err := loadPaymentPlan(tx, provider, event.PlanCode)
+		if err == sql.ErrNoRows {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusUnprocessableEntity,
+				message: "period_end or a mapped plan is required for renewals",
+				data:    map[string]interface{}{"provider": provider, "plan": event.PlanCode}}
+		}
+		if err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to load payment plan", err: err}
+		}
+		durationDays = plan.DurationDays
+	}
+
+	linked, requestErr := lockPaymentLicenses(tx, provider, event)
+	if requestErr != nil {
+		return paymentEventOutcome{}, requestErr
+	}
+
+	today, _ := time.Parse("2006-01-02", utils.FormatDateOnly(utils.NowSeoul()))
+	licenses := []models.License{}
+	for _, license := range linked {
+		if license.Status == models.LicenseStatusRevoked {
+			continue
+		}
+		current, err := time.Parse("2006-01-02", normalizeDateOnly(license.ExpiresAt))
+		if err != nil {
+			current = today
+		}
+		next := periodEnd
+		if next == "" {
+			base := current
+			if base.Before(today) {
+				base = today
+			}
+			next = base.AddDate(0, 0, durationDays).Format("2006-01-02")
+		}
+		if next <= current.Format("2006-01-02") {
+			continue
+		}
+
+		before, err := utils.LoadLicenseSnapshot(tx, license.ID)
+		if err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to load license", err: err}
+		}
+		if _, err := tx.Exec("UPDATE licenses SET expires_at = ?, updated_at = ? WHERE id = ?",
+			next, utils.FormatDateTimeForDB(utils.NowSeoul()), license.ID); err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to update license", err: err}
+		}
+		// 스케줄러가 만료/유예 처리한 라이선스는 새 만료일로 다시 활성화합니다.
+		if license.Status == models.LicenseStatusExpired || license.Status == models.LicenseStatusGrace {
+			if err := utils.TransitionLicenseStatus(tx, license.ID, utils.LicenseTransition{From: license.Status, To: models.LicenseStatusActive}); err != nil {
+				return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to reactivate license", err: err}
+			}
+			license.Status = models.LicenseStatusActive
+		}
+		if err := recordPaymentLicenseEvent(tx, license.ID, models.LicenseEventRenewed, before, provider, event); err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to record license history", err: err}
+		}
+
+		license.ExpiresAt = next
+		licenses = append(licenses, license)
+	}
+
+	return paymentEventOutcome{result: models.PaymentEventResultProcessed, licenses: licenses}, nil
+}
+
+// transitionPaymentLicenses 주문/구독에 연결된 라이선스를 환불/해지에 맞는 상태로 바꿉니다.
+// 이미 같은 상태이거나 전환할 수 없는 상태(폐기 등)인 라이선스는 건너뜁니다.
+func transitionPaymentLicenses(tx *sql.Tx, provider string, event models.PaymentEvent, eventType string,
+	build func(from string) utils.LicenseTransition) (paymentEventOutcome, *clientRequestError) {
+	linked, requestErr := lockPaymentLicenses(tx, provider, event)
+	if requestErr != nil {
+		return paymentEventOutcome{}, requestErr
+	}
+
+	licenses := []models.License{}
+	for _, license := range linked {
+		before, err := utils.LoadLicenseSnapshot(tx, license.ID)
+		if err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to load license", err: err}
+		}
+		transition := build(license.Status)
+		err = utils.TransitionLicenseStatus(tx, license.ID, transition)
+		if errors.Is(err, utils.ErrInvalidLicenseTransition) {
+			continue
+		}
+		if err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to update license status", err: err}
+		}
+		if err := recordPaymentLicenseEvent(tx, license.ID, eventType, before, provider, event); err != nil {
+			return paymentEventOutcome{}, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to record license history", err: err}
+		}
+
+		license.Status = transition.To
+		licenses = append(licenses, license)
+	}
+
+	return paymentEventOutcome{result: models.PaymentEventResultProcessed, licenses: licenses}, nil
+}
+
+// lockPaymentLicenses 이벤트의 구독 ID(없으면 주문 ID)로 발급된 라이선스를 잠그고 조회합니다.
+// 연결된 라이선스가 없으면 404를 반환해 결제 서비스가 나중에 다시 보내도록 합니다
+// (주문 이벤트보다 갱신/환불 이벤트가 먼저 도착한 경우).
+func lockPaymentLicenses(tx *sql.Tx, provider string, event models.PaymentEvent) ([]models.License, *clientRequestError) {
+	column, value := "payment_subscription_id", event.SubscriptionID
+	if value == "" {
+		column, value = "payment_order_id", event.OrderID
+	}
+	if value == "" {
+		return nil, &clientRequestError{status: http.StatusBadRequest, message: "order_id or subscription_id is required"}
+	}
+
+	rows, err := tx.Query(`SELECT id, expires_at, status FROM licenses
+		WHERE payment_provider = ? AND `+column+` = ? ORDER BY created_at FOR UPDATE`, provider, value)
+	if err != nil {
+		return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to query licenses", err: err}
+	}
+	defer rows.Close()
+
+	licenses := []models.License{}
+	for rows.Next() {
+		var license models.License
+		if err := rows.Scan(&license.ID, &license.ExpiresAt, &license.Status); err != nil {
+			return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to query licenses", err: err}
+		}
+		license.ExpiresAt = normalizeDateOnly(license.ExpiresAt)
+		licenses = append(licenses, license)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &clientRequestError{status: http.StatusInternalServerError, message: "Failed to query licenses", err: err}
+	}
+	if len(licenses) == 0 {
+		return nil, &clientRequestError{status: http.StatusNotFound, message: "No license linked to this payment",
+			data: map[string]interface{}{"provider": provider, column: value}}
+	}
+	return licenses, nil
+}
+
+// recordPaymentLicenseEvent 결제 이벤트로 바뀐 라이선스를 변경 이력에 기록합니다. 주문 ID는 order_ref로 남깁니다.
+func recordPaymentLicenseEvent(tx *sql.Tx, licenseID, eventType string, before utils.LicenseSnapshot,
+	provider string, event models.PaymentEvent) error {
+	after, err := utils.LoadLicenseSnapshot(tx, licenseID)
+	if err != nil {
+		return err
+	}
+	licenseEvent := models.LicenseEvent{
+		LicenseID: licenseID,
+		EventType: eventType,
+		Changes:   utils.DiffLicenseSnapshots(before, after),
+		Reason:    fmt.Sprintf("Payment %s event %s (%s)", provider, event.ID, event.Type),
+		ActorID:   "system",
+		ActorName: "System",
+	}
+	if event.OrderID != "" {
+		orderRef := event.OrderID
+		licenseEvent.OrderRef = &orderRef
+	}
+	return utils.RecordLicenseEvent(tx, licenseEvent)
+}
+
+// optionalString 빈 문자열이면 nil을 반환합니다.
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/services"
+)
+
+// paymentEventsDriver는 payment_events 테이블만 흉내 내는 database/sql 드라이버입니다.
+// (provider, event_id) 유일 키를 MySQL과 같은 "Duplicate entry" 오류로 거부해 중복 수신 처리를 확인합니다.
+type paymentEventsDriver struct {
+	mu     sync.Mutex
+	events map[string][2]string // provider/event_id → result, license_ids
+	execs  []string
+}
+
+func (d *paymentEventsDriver) Open(string) (driver.Conn, error) {
+	return &paymentEventsConn{d: d}, nil
+}
+
+type paymentEventsConn struct {
+	d *paymentEventsDriver
+}
+
+func (c *paymentEventsConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *paymentEventsConn) Close() error { return nil }
+
+func (c *paymentEventsConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *paymentEventsConn) Commit() error { return nil }
+
+func (c *paymentEventsConn) Rollback() error { return nil }
+
+func (c *paymentEventsConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.execs = append(c.d.execs, query)
+
+	if strings.Contains(query, "INSERT INTO payment_events") {
+		key := args[0].Value.(string) + "/" + args[1].Value.(string)
+		if _, ok := c.d.events[key]; ok {
+			return nil, errors.New("Error 1062 (23000): Duplicate entry '" + key + "' for key 'uniq_provider_event'")
+		}
+		return nil, errors.New("unexpected new payment event " + key)
+	}
+	return nil, errors.New("unexpected exec: " + query)
+}
+
+func (c *paymentEventsConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+
+	if strings.Contains(query, "FROM payment_events") {
+		key := args[0].Value.(string) + "/" + args[1].Value.(string)
+		if stored, ok := c.d.events[key]; ok {
+			return &paymentEventsRows{values: [][]driver.Value{{stored[0], stored[1]}}}, nil
+		}
+		return &paymentEventsRows{}, nil
+	}
+	return nil, errors.New("unexpected query: " + query)
+}
+
+type paymentEventsRows struct {
+	values [][]driver.Value
+}
+
+func (r *paymentEventsRows) Columns() []string { return []string{"result", "license_ids"} }
+
+func (r *paymentEventsRows) Close() error { return nil }
+
+func (r *paymentEventsRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var paymentEventsTestDriver = &paymentEventsDriver{}
+
+func init() {
+	sql.Register("payment-events-test", paymentEventsTestDriver)
+}
+
+func TestHandlePaymentWebhookDuplicateEvent(t *testing.T) {
+	fake := paymentEventsTestDriver
+	fake.mu.Lock()
+	fake.events = map[string][2]string{"generic/evt_1": {"processed", `["lic-1","lic-2"]`}}
+	fake.execs = nil
+	fake.mu.Unlock()
+
+	db, err := sql.Open("payment-events-test", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	previousDB, previousProviders := database.DB, paymentProviders
+	database.DB = db
+	SetPaymentProviders(services.NewPaymentProviderRegistry(services.NewGenericPaymentProvider("whsec_test", time.Minute)))
+	defer func() {
+		database.DB = previousDB
+		paymentProviders = previousProviders
+	}()
+
+	body := `{"id":"evt_1","type":"subscription.renewed","data":{"subscription_id":"sub_1","period_end":"2027-01-31"}}`
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte(timestamp + "." + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/payments/generic", strings.NewReader(body))
+	req.Header.Set(services.HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(services.HeaderWebhookSignature, hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+
+	HandlePaymentWebhook(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Status string `json:"status"`
+		Data   struct {
+			EventID    string   `json:"event_id"`
+			Result     string   `json:"result"`
+			Duplicate  bool     `json:"duplicate"`
+			LicenseIDs []string `json:"license_ids"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Status != "success" || !resp.Data.Duplicate || resp.Data.EventID != "evt_1" || resp.Data.Result != "processed" ||
+		strings.Join(resp.Data.LicenseIDs, ",") != "lic-1,lic-2" {
+		t.Fatalf("unexpected duplicate response: %s", rec.Body.String())
+	}
+
+	// 중복 이벤트는 기록 시도 외에 라이선스를 바꾸는 쿼리를 실행하지 않아야 합니다.
+	for _, query := range fake.execs {
+		if !strings.Contains(query, "INSERT INTO payment_events") {
+			t.Errorf("duplicate event executed %q", query)
+		}
+	}
+}
+
+func TestHandlePaymentWebhookRejectsInvalidSignature(t *testing.T) {
+	previousProviders := paymentProviders
+	SetPaymentProviders(services.NewPaymentProviderRegistry(services.NewGenericPaymentProvider("whsec_test", time.Minute)))
+	defer func() { paymentProviders = previousProviders }()
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"unknown provider", "/api/webhooks/payments/stripe", http.StatusNotFound},
+		{"bad signature", "/api/webhooks/payments/generic", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"id":"evt_1","type":"order.created"}`))
+		req.Header.Set(services.HeaderWebhookTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+		req.Header.Set(services.HeaderWebhookSignature, "00")
+		rec := httptest.NewRecorder()
+
+		HandlePaymentWebhook(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -36,7 +36,7 @@ func ListProductFiles(w http.ResponseWriter, r *http.Request) {
 		return
 
 	case productID != "":
-		query := `SELECT pf.id, pf.product_id, pf.file_id, pf.label, pf.description, pf.sort_order, pf.is_active, pf.delivery_url, pf.created_at, pf.updated_at,
+		query := `SELECT pf.id, pf.product_id, pf.file_id, pf.label, pf.description, pf.sort_order, pf.is_active, pf.delivery_url, pf.version, pf.os, pf.arch, pf.channel, pf.rollout_percentage, pf.rollout_status, pf.rollout_updated_at, pf.created_at, pf.updated_at,
             f.original_name, f.stored_name, f.description, f.mime_type, f.file_size, f.checksum, f.storage_path, f.uploaded_by, f.uploaded_username, f.created_at, f.updated_at
             FROM product_files pf
             JOIN files f ON pf.file_id = f.id
@@ -84,6 +84,9 @@ func AttachProductFile(w http.ResponseWriter, r *http.Request) {
 	req.Label = strings.TrimSpace(req.Label)
 	req.Description = strings.TrimSpace(req.Description)
 	req.DeliveryURL = strings.TrimSpace(req.DeliveryURL)
+	req.OS = utils.NormalizeOS(req.OS)
+	req.Arch = utils.NormalizeArch(req.Arch)
+	req.Channel = strings.ToLower(strings.TrimSpace(req.Channel))
 
 	if req.ProductID == "" || req.FileID == "" || req.Label == "" {
 		w.WriteHeader(http.StatusBadRequest)
@@ -91,6 +94,34 @@ func AttachProductFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Files tagged with a version are offered by the update feed for that release.
+	version, err := normalizeProductFileVersion(req.Version)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("version must be a semantic version (e.g. 2.3.0)", err))
+		return
+	}
+
+	if req.Channel == "" {
+		req.Channel = models.ReleaseChannelStable
+	}
+	if !models.IsValidReleaseChannel(req.Channel) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("channel must be stable, beta or internal", nil))
+		return
+	}
+
+	// A new file version can start as a staged rollout instead of going out to every device at once.
+	rolloutPercentage := 100
+	if req.RolloutPercentage != nil {
+		rolloutPercentage = *req.RolloutPercentage
+		if rolloutPercentage < 0 || rolloutPercentage > 100 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("rollout_percentage must be between 0 and 100", nil))
+			return
+		}
+	}
+
 	// Ensure product exists
 	var productCount int
 	if err := database.DB.QueryRow("SELECT COUNT(*) FROM products WHERE id = ?", req.ProductID).Scan(&productCount); err != nil {
@@ -131,8 +162,9 @@ func AttachProductFile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	_, err = database.DB.Exec(
-		`INSERT INTO product_files (id, product_id, file_id, label, description, sort_order, is_active, delivery_url, created_at, updated_at)
-        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO product_files (id, product_id, file_id, label, description, sort_order, is_active, delivery_url, version, os, arch,
+            channel, rollout_percentage, rollout_status, rollout_updated_at, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		mappingID,
 		req.ProductID,
 		req.FileID,
@@ -141,6 +173,13 @@ func AttachProductFile(w http.ResponseWriter, r *http.Request) {
 		req.SortOrder,
 		isActive,
 		nullIfEmpty(req.DeliveryURL),
+		nullIfEmpty(version),
+		nullIfEmpty(req.OS),
+		nullIfEmpty(req.Arch),
+		req.Channel,
+		rolloutPercentage,
+		models.RolloutStatusActive,
+		createdAt,
 		createdAt,
 		createdAt,
 	)
@@ -225,6 +264,38 @@ func UpdateProductFile(w http.ResponseWriter, r *http.Request) {
 		args = append(args, nullIfEmpty(deliveryURL))
 	}
 
+	if req.Version != nil {
+		version, err := normalizeProductFileVersion(*req.Version)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("version must be a semantic version (e.g. 2.3.0)", err))
+			return
+		}
+		setClauses = append(setClauses, "version = ?")
+		args = append(args, nullIfEmpty(version))
+	}
+
+	if req.OS != nil {
+		setClauses = append(setClauses, "os = ?")
+		args = append(args, nullIfEmpty(utils.NormalizeOS(*req.OS)))
+	}
+
+	if req.Arch != nil {
+		setClauses = append(setClauses, "arch = ?")
+		args = append(args, nullIfEmpty(utils.NormalizeArch(*req.Arch)))
+	}
+
+	if req.Channel != nil {
+		channel := strings.ToLower(strings.TrimSpace(*req.Channel))
+		if !models.IsValidReleaseChannel(channel) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("channel must be stable, beta or internal", nil))
+			return
+		}
+		setClauses = append(setClauses, "channel = ?")
+		args = append(args, channel)
+	}
+
 	if req.IsActive != nil {
 		if *req.IsActive {
 			setClauses = append(setClauses, "is_active = 1")
@@ -326,8 +397,153 @@ func DeleteProductFile(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(models.SuccessResponse("Product file detached", nil))
 }
 
+// UpdateProductFileRollout changes the staged rollout of a product file.
+// A version-tagged mapping is controlled together with every other mapping of the same product version
+// (e.g. the Windows and macOS builds of 2.4.0), so a device is either in or out of the whole release.
+// @Summary 제품 파일 단계적 배포 제어
+// @Description set은 배포 비율(0~100)을 지정하고 배포를 재개합니다. pause는 모든 디바이스에 대한 배포를 멈추고, resume은 같은 비율로 다시 배포합니다.
+// @Description rollback은 배포를 중단하고 이 버전을 사용 중인 디바이스에 업데이트 확인에서 이전 릴리스를 안내합니다. 디바이스는 디바이스 ID 해시로 고정된 구간에 배정됩니다.
+// @Tags 관리자 - 제품 파일
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateProductFileRolloutRequest true "배포 제어 요청"
+// @Success 200 {object} models.APIResponse{data=[]models.ProductFile} "변경된 제품 파일 매핑"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "제품 파일 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/product-files/rollout [post]
+func UpdateProductFileRollout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateProductFileRolloutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+		return
+	}
+
+	req.ID = strings.TrimSpace(req.ID)
+	req.Action = strings.ToLower(strings.TrimSpace(req.Action))
+	if req.ID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("id is required", nil))
+		return
+	}
+
+	setClauses := []string{}
+	args := []interface{}{}
+	switch req.Action {
+	case models.RolloutActionSet:
+		if req.Percentage == nil || *req.Percentage < 0 || *req.Percentage > 100 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("percentage must be between 0 and 100", nil))
+			return
+		}
+		setClauses = append(setClauses, "rollout_percentage = ?", "rollout_status = ?")
+		args = append(args, *req.Percentage, models.RolloutStatusActive)
+	case models.RolloutActionPause:
+		setClauses = append(setClauses, "rollout_status = ?")
+		args = append(args, models.RolloutStatusPaused)
+	case models.RolloutActionResume:
+		setClauses = append(setClauses, "rollout_status = ?")
+		args = append(args, models.RolloutStatusActive)
+	case models.RolloutActionRollback:
+		setClauses = append(setClauses, "rollout_status = ?")
+		args = append(args, models.RolloutStatusRolledBack)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("action must be set, pause, resume or rollback", nil))
+		return
+	}
+
+	current, err := loadProductFileByID(req.ID)
+	if err == sql.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product file not found", nil))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load product file", err))
+		return
+	}
+
+	now := utils.FormatDateTimeForDB(utils.NowSeoul())
+	setClauses = append(setClauses, "rollout_updated_at = ?", "updated_at = ?")
+	args = append(args, now, now)
+
+	scope := "pf.id = ?"
+	scopeArgs := []interface{}{current.ID}
+	if current.Version != "" {
+		scope = "pf.product_id = ? AND pf.version = ?"
+		scopeArgs = []interface{}{current.ProductID, current.Version}
+	}
+
+	query := fmt.Sprintf("UPDATE product_files pf SET %s WHERE %s", strings.Join(setClauses, ", "), scope)
+	if _, err := database.DB.Exec(query, append(args, scopeArgs...)...); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to update rollout", err))
+		return
+	}
+
+	rows, err := database.DB.Query(`SELECT pf.id, pf.product_id, pf.file_id, pf.label, pf.description, pf.sort_order, pf.is_active, pf.delivery_url, pf.version, pf.os, pf.arch, pf.channel, pf.rollout_percentage, pf.rollout_status, pf.rollout_updated_at, pf.created_at, pf.updated_at,
+        f.original_name, f.stored_name, f.description, f.mime_type, f.file_size, f.checksum, f.storage_path, f.uploaded_by, f.uploaded_username, f.created_at, f.updated_at
+        FROM product_files pf
+        JOIN files f ON pf.file_id = f.id
+        WHERE `+scope+`
+        ORDER BY pf.sort_order ASC, pf.created_at DESC`, scopeArgs...)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load updated product files", err))
+		return
+	}
+	defer rows.Close()
+
+	items := []models.ProductFile{}
+	for rows.Next() {
+		item, err := scanProductFile(rows)
+		if err != nil {
+			logger.Warn("Failed to scan product file: %v", err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	if adminID, ok := r.Context().Value("admin_id").(string); ok && adminID != "" {
+		username, _ := r.Context().Value("username").(string)
+		target := "파일 " + current.ID
+		if current.Version != "" {
+			target = "버전 " + current.Version
+		}
+		detail := req.Action
+		if req.Action == models.RolloutActionSet {
+			detail = fmt.Sprintf("%s %d%%", req.Action, *req.Percentage)
+		}
+		utils.LogAdminActivity(adminID, username, models.AdminActionUpdateRollout, fmt.Sprintf("제품 %s의 %s 단계적 배포 변경 (%s)", current.ProductID, target, detail))
+	}
+
+	json.NewEncoder(w).Encode(models.SuccessResponse("Product file rollout updated", items))
+}
+
+// normalizeProductFileVersion validates an optional release version and returns its canonical form.
+func normalizeProductFileVersion(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	version, err := utils.ParseSemver(value)
+	if err != nil {
+		return "", err
+	}
+	return version.String(), nil
+}
+
 func loadProductFileByID(id string) (models.ProductFile, error) {
-	query := `SELECT pf.id, pf.product_id, pf.file_id, pf.label, pf.description, pf.sort_order, pf.is_active, pf.delivery_url, pf.created_at, pf.updated_at,
+	query := `SELECT pf.id, pf.product_id, pf.file_id, pf.label, pf.description, pf.sort_order, pf.is_active, pf.delivery_url, pf.version, pf.os, pf.arch, pf.channel, pf.rollout_percentage, pf.rollout_status, pf.rollout_updated_at, pf.created_at, pf.updated_at,
         f.original_name, f.stored_name, f.description, f.mime_type, f.file_size, f.checksum, f.storage_path, f.uploaded_by, f.uploaded_username, f.created_at, f.updated_at
         FROM product_files pf
         JOIN files f ON pf.file_id = f.id
@@ -344,6 +560,10 @@ func scanProductFile(scanner interface {
 		pf               models.ProductFile
 		description      sql.NullString
 		deliveryURL      sql.NullString
+		version          sql.NullString
+		osName           sql.NullString
+		arch             sql.NullString
+		rolloutUpdatedAt sql.NullString
 		fileDescription  sql.NullString
 		fileChecksum     sql.NullString
 		uploadedBy       sql.NullString
@@ -367,6 +587,13 @@ func scanProductFile(scanner interface {
 		&pf.SortOrder,
 		&isActive,
 		&deliveryURL,
+		&version,
+		&osName,
+		&arch,
+		&pf.Channel,
+		&pf.RolloutPercentage,
+		&pf.RolloutStatus,
+		&rolloutUpdatedAt,
 		&pf.CreatedAt,
 		&pf.UpdatedAt,
 		&originalName,
@@ -387,6 +614,12 @@ func scanProductFile(scanner interface {
 	pf.Description = stringIfValid(description)
 	pf.IsActive = isActive != 0
 	pf.DeliveryURL = stringIfValid(deliveryURL)
+	pf.Version = stringIfValid(version)
+	pf.OS = stringIfValid(osName)
+	pf.Arch = stringIfValid(arch)
+	if rolloutUpdatedAt.Valid {
+		pf.RolloutUpdatedAt = &rolloutUpdatedAt.String
+	}
 
 	asset := &models.FileAsset{
 		ID:               pf.FileID,
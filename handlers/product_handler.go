@@ -62,6 +62,15 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 			json.NewEncoder(w).Encode(models.ErrorResponse("이미 존재하는 제품명입니다", nil))
 			return
 		}
+		if errors.Is(err, services.ErrInvalidRequestSigning) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request signing configuration", err))
+			return
+		}
+		if errors.Is(err, services.ErrInvalidKeyTemplate) {
+			writeInvalidKeyTemplate(w, err)
+			return
+		}
 		logger.WithFields(map[string]interface{}{"error": err.Error(), "name": req.Name}).Error("Failed to create product")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to create product", err))
@@ -72,6 +81,9 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 		"product_id": product.ID,
 		"name":       product.Name,
 	}).Info("Product created")
+	if req.KeyTemplate != nil {
+		utils.InvalidateKeyTemplateCache()
+	}
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(models.SuccessResponse("Product created successfully", product))
 
@@ -223,6 +235,13 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode(models.ErrorResponse("Product not found", nil))
 			return
+		case errors.Is(err, services.ErrInvalidRequestSigning):
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request signing configuration", err))
+			return
+		case errors.Is(err, services.ErrInvalidKeyTemplate):
+			writeInvalidKeyTemplate(w, err)
+			return
 		default:
 			logger.WithFields(map[string]interface{}{
 				"error":      err.Error(),
@@ -235,6 +254,9 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	logger.WithFields(map[string]interface{}{"product_id": id}).Info("Product updated")
+	if req.KeyTemplate != nil {
+		utils.InvalidateKeyTemplateCache()
+	}
 	json.NewEncoder(w).Encode(models.SuccessResponse("Product updated successfully", nil))
 
 	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
@@ -294,6 +316,56 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// RotateClientSecret 클라이언트 요청 서명 비밀키 발급
+// @Summary 클라이언트 비밀키 발급/교체
+// @Description hmac 요청 서명에 사용할 새 비밀키를 발급합니다. 비밀키는 이 응답에서만 확인할 수 있으며 기존 비밀키는 즉시 무효화됩니다.
+// @Tags 제품
+// @Produce json
+// @Security BearerAuth
+// @Param id query string true "제품 ID"
+// @Success 200 {object} models.APIResponse "발급 성공"
+// @Failure 400 {object} models.APIResponse "잘못된 요청"
+// @Failure 404 {object} models.APIResponse "제품 없음"
+// @Failure 500 {object} models.APIResponse "서버 에러"
+// @Router /api/admin/products/client-secret [post]
+func (h *ProductHandler) RotateClientSecret(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if strings.TrimSpace(id) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Product ID is required", nil))
+		return
+	}
+
+	secret, err := h.service.RotateClientSecret(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(models.ErrorResponse("Product not found", nil))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to rotate client secret", err))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{"product_id": id}).Info("Product client secret rotated")
+	json.NewEncoder(w).Encode(models.SuccessResponse("Client secret rotated", map[string]interface{}{
+		"product_id":    id,
+		"client_secret": secret,
+	}))
+
+	if adminIDRaw := r.Context().Value("admin_id"); adminIDRaw != nil {
+		adminID := adminIDRaw.(string)
+		username, _ := r.Context().Value("username").(string)
+		utils.LogAdminActivity(adminID, username, models.AdminActionRotateSecret, "Product client secret rotated: "+id)
+	}
+}
+
 func getRole(r *http.Request) string {
 	role, _ := r.Context().Value("role").(string)
 	return role
@@ -303,3 +375,11 @@ func getAdminID(r *http.Request) string {
 	adminID, _ := r.Context().Value("admin_id").(string)
 	return adminID
 }
+
+// writeInvalidKeyTemplate 키 템플릿 검증 실패 사유를 data.reason에 담아 400으로 응답합니다.
+func writeInvalidKeyTemplate(w http.ResponseWriter, err error) {
+	resp := models.ErrorResponse("Invalid license key template", err)
+	resp.Data = map[string]string{"reason": err.Error()}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
+	"encoding/json"
 	"net/http"
 
+	"studiolicense/database"
 	"studiolicense/models"
 	"studiolicense/services"
+	"studiolicense/utils"
 )
 
 var scopeResolver services.ResourceScopeResolver = services.NoopResourceScopeResolver{}
@@ -24,3 +28,33 @@ func resolveResourceScope(r *http.Request, resourceType string) (models.AdminRes
 	scope, isSuper, err := scopeResolver.Resolve(r.Context(), role, adminID, resourceType)
 	return scope, isSuper, adminID, err
 }
+
+// authorizeLicenseAccess는 관리자의 리소스 스코프로 특정 라이선스에 접근할 수 있는지 확인합니다.
+// 접근할 수 없으면 응답을 작성하고 false를 반환합니다.
+func authorizeLicenseAccess(w http.ResponseWriter, r *http.Request, licenseID string) bool {
+	scope, isSuper, adminID, err := resolveResourceScope(r, models.ResourceTypeLicenses)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to evaluate license permissions", err))
+		return false
+	}
+
+	var owner sql.NullString
+	if err := database.DB.QueryRow("SELECT created_by FROM licenses WHERE id = ?", licenseID).Scan(&owner); err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(models.ErrorResponse("License not found", nil))
+			return false
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Failed to load license", err))
+		return false
+	}
+
+	if !isSuper && !utils.CanAccessResource(scope, licenseID, owner.String, adminID) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(models.ErrorResponse("Forbidden: license access denied", nil))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+// Package licensecert는 서버가 발급하는 Ed25519 서명 라이선스 인증서의 형식과
+// 서명/검증 로직을 제공합니다. 데이터베이스에 의존하지 않으므로 클라이언트가
+// 그대로 가져다 오프라인 검증에 사용할 수 있습니다.
+package licensecert
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AlgorithmEd25519 는 현재 지원하는 유일한 서명 알고리즘입니다.
+const AlgorithmEd25519 = "Ed25519"
+
+// CurrentVersion 은 인증서 페이로드 형식 버전입니다.
+const CurrentVersion = 1
+
+var (
+	// ErrUnsupportedAlgorithm 은 알 수 없는 서명 알고리즘일 때 반환됩니다.
+	ErrUnsupportedAlgorithm = errors.New("unsupported certificate algorithm")
+	// ErrUnknownKey 는 서명 키 ID에 해당하는 공개키가 없을 때 반환됩니다.
+	ErrUnknownKey = errors.New("unknown certificate signing key")
+	// ErrInvalidSignature 는 서명이 일치하지 않을 때 반환됩니다.
+	ErrInvalidSignature = errors.New("invalid certificate signature")
+	// ErrExpired 는 인증서의 만료 시각이 지났을 때 반환됩니다.
+	ErrExpired = errors.New("license certificate has expired")
+	// ErrFingerprintMismatch 는 인증서가 다른 디바이스에 발급되었을 때 반환됩니다.
+	ErrFingerprintMismatch = errors.New("license certificate issued for another device")
+	// ErrOfflineLimitExceeded 는 마지막 온라인 검증 후 허용된 오프라인 기간이 지났을 때 반환됩니다.
+	ErrOfflineLimitExceeded = errors.New("license certificate offline allowance exceeded")
+	// ErrNotDeviceBound 는 인증서에 디바이스 핑거프린트가 없을 때 반환됩니다.
+	ErrNotDeviceBound = errors.New("license certificate is not bound to a device")
+)
+
+// 서명 대상 형식. 같은 서버 키로 인증서, 오프라인 응답, 폐기 목록에 서명하므로
+// 서명 입력 앞에 "<형식>\n"을 붙여 한 형식의 서명을 다른 형식으로 바꿔 제출할 수 없게 합니다.
+const (
+	payloadTypeCertificate     = "license-certificate"
+	payloadTypeOfflineRequest  = "offline-request"
+	payloadTypeOfflineResponse = "offline-response"
+	payloadTypeRevocationList  = "revocation-list"
+)
+
+// signingInput 은 형식 이름과 페이로드를 합친 실제 서명 대상 바이트를 반환합니다.
+func signingInput(payloadType string, payload []byte) []byte {
+	input := make([]byte, 0, len(payloadType)+1+len(payload))
+	input = append(input, payloadType...)
+	input = append(input, '\n')
+	return append(input, payload...)
+}
+
+// Certificate 는 서명 대상이 되는 라이선스 인증서 본문입니다.
+type Certificate struct {
+	Version           int             `json:"version"`
+	LicenseID         string          `json:"license_id"`
+	LicenseKey        string          `json:"license_key"`
+	ProductID         string          `json:"product_id"`
+	ProductName       string          `json:"product_name,omitempty"`
+	DeviceID          string          `json:"device_id"`
+	DeviceFingerprint string          `json:"device_fingerprint"`
+	Policies          json.RawMessage `json:"policies,omitempty"`
+	Entitlements      json.RawMessage `json:"entitlements,omitempty"`
+	IssuedAt          time.Time       `json:"issued_at"`
+	ExpiresAt         time.Time       `json:"expires_at"`
+	// GracePeriodDays 는 ExpiresAt 이후에도 사용을 허용하는 일수입니다.
+	GracePeriodDays int `json:"grace_period_days,omitempty"`
+	// MaxOfflineDays 는 IssuedAt 이후 서버 검증 없이 사용할 수 있는 일수입니다. 0이면 제한이 없습니다.
+	MaxOfflineDays int `json:"max_offline_days,omitempty"`
+}
+
+// SignedCertificate 는 클라이언트에 전달되는 서명 봉투입니다.
+// Payload 는 Certificate 의 JSON 바이트를 base64(raw URL) 인코딩한 값이며,
+// Signature 는 "license-certificate\n" 뒤에 디코딩된 Payload 바이트를 이어 붙인 값에 대한 Ed25519 서명입니다.
+type SignedCertificate struct {
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// KeySet 은 키 ID별 공개키 모음입니다.
+type KeySet map[string]ed25519.PublicKey
+
+var encoding = base64.RawURLEncoding
+
+// Sign 은 인증서를 직렬화하고 주어진 개인키로 서명합니다.
+func Sign(cert Certificate, keyID string, privateKey ed25519.PrivateKey) (SignedCertificate, error) {
+	if keyID == "" {
+		return SignedCertificate{}, errors.New("key id is required")
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return SignedCertificate{}, errors.New("invalid ed25519 private key")
+	}
+	if cert.Version == 0 {
+		cert.Version = CurrentVersion
+	}
+
+	payload, err := json.Marshal(cert)
+	if err != nil {
+		return SignedCertificate{}, fmt.Errorf("failed to encode certificate: %w", err)
+	}
+
+	signature := ed25519.Sign(privateKey, signingInput(payloadTypeCertificate, payload))
+	return SignedCertificate{
+		KeyID:     keyID,
+		Algorithm: AlgorithmEd25519,
+		Payload:   encoding.EncodeToString(payload),
+		Signature: encoding.EncodeToString(signature),
+	}, nil
+}
+
+// Verify 는 서명을 검증하고 인증서 본문을 반환합니다.
+// 만료 여부와 디바이스 일치 여부는 Check 로 별도 확인합니다.
+func Verify(signed SignedCertificate, keys KeySet) (Certificate, error) {
+	if signed.Algorithm != AlgorithmEd25519 {
+		return Certificate{}, ErrUnsupportedAlgorithm
+	}
+
+	publicKey, ok := keys[signed.KeyID]
+	if !ok || len(publicKey) != ed25519.PublicKeySize {
+		return Certificate{}, ErrUnknownKey
+	}
+
+	payload, err := decodeSigned(payloadTypeCertificate, signed.Payload, signed.Signature, publicKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	var cert Certificate
+	if err := json.Unmarshal(payload, &cert); err != nil {
+		return Certificate{}, fmt.Errorf("invalid certificate payload: %w", err)
+	}
+	return cert, nil
+}
+
+// Check 는 인증서가 지정한 디바이스와 시각에 유효한지 확인합니다.
+// fingerprint 가 빈 문자열이면 디바이스 비교를 생략하지만, 디바이스에 묶이지 않은 인증서는 항상 거부합니다.
+func (c Certificate) Check(fingerprint string, now time.Time) error {
+	if c.DeviceFingerprint == "" {
+		return ErrNotDeviceBound
+	}
+	if fingerprint != "" && fingerprint != c.DeviceFingerprint {
+		return ErrFingerprintMismatch
+	}
+	if !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt.AddDate(0, 0, c.GracePeriodDays)) {
+		return ErrExpired
+	}
+	if c.MaxOfflineDays > 0 && !now.Before(c.IssuedAt.AddDate(0, 0, c.MaxOfflineDays)) {
+		return ErrOfflineLimitExceeded
+	}
+	return nil
+}
+
+// InGrace 는 인증서가 만료 후 유예 기간 중인지 여부를 반환합니다.
+func (c Certificate) InGrace(now time.Time) bool {
+	if c.ExpiresAt.IsZero() || now.Before(c.ExpiresAt) {
+		return false
+	}
+	return now.Before(c.ExpiresAt.AddDate(0, 0, c.GracePeriodDays))
+}
+
+// EncodePublicKey 는 공개키를 배포용 문자열로 인코딩합니다.
+func EncodePublicKey(publicKey ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(publicKey)
+}
+
+// ParsePublicKey 는 EncodePublicKey 로 인코딩된 공개키를 복원합니다.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid ed25519 public key length")
+	}
+	return ed25519.PublicKey(raw), nil
+}
@@ -0,0 +1,140 @@
+package licensecert
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return publicKey, privateKey
+}
+
+func testCertificate() Certificate {
+	issuedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return Certificate{
+		LicenseID:         "lic-1",
+		LicenseKey:        "ABCD-EFGH-IJKL-MNOP",
+		ProductID:         "prod-1",
+		DeviceID:          "dev-1",
+		DeviceFingerprint: "fp-1",
+		IssuedAt:          issuedAt,
+		ExpiresAt:         issuedAt.AddDate(0, 1, 0),
+	}
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	cert := testCertificate()
+
+	signed, err := Sign(cert, "key-1", privateKey)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	got, err := Verify(signed, KeySet{"key-1": publicKey})
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got.Version != CurrentVersion || got.LicenseID != cert.LicenseID || got.DeviceFingerprint != cert.DeviceFingerprint ||
+		!got.ExpiresAt.Equal(cert.ExpiresAt) {
+		t.Fatalf("Verify = %+v, want %+v", got, cert)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	otherPublicKey, otherPrivateKey := newTestKey(t)
+	keys := KeySet{"key-1": publicKey, "key-2": otherPublicKey}
+
+	signed, err := Sign(testCertificate(), "key-1", privateKey)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	tampered, err := Sign(Certificate{LicenseID: "lic-2", DeviceFingerprint: "fp-1"}, "key-1", otherPrivateKey)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(s SignedCertificate) SignedCertificate
+		want   error
+	}{
+		{"unsupported algorithm", func(s SignedCertificate) SignedCertificate { s.Algorithm = "RS256"; return s }, ErrUnsupportedAlgorithm},
+		{"unknown key id", func(s SignedCertificate) SignedCertificate { s.KeyID = "retired"; return s }, ErrUnknownKey},
+		// 키를 교체한 뒤에도 다른 키 ID로 검증하면 거부되어야 합니다.
+		{"signed by another key", func(s SignedCertificate) SignedCertificate { s.KeyID = "key-2"; return s }, ErrInvalidSignature},
+		{"payload swapped", func(s SignedCertificate) SignedCertificate { s.Payload = tampered.Payload; return s }, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(tt.modify(signed), keys); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Sign(testCertificate(), "", privateKey); err == nil {
+		t.Error("Sign accepted an empty key id")
+	}
+	if _, err := Sign(testCertificate(), "key-1", privateKey[:10]); err == nil {
+		t.Error("Sign accepted a short private key")
+	}
+}
+
+func TestCertificateCheck(t *testing.T) {
+	cert := testCertificate()
+	cert.GracePeriodDays = 7
+	cert.MaxOfflineDays = 45
+
+	unbound := cert
+	unbound.DeviceFingerprint = ""
+
+	tests := []struct {
+		name        string
+		cert        Certificate
+		fingerprint string
+		now         time.Time
+		want        error
+		inGrace     bool
+	}{
+		{"valid", cert, "fp-1", cert.ExpiresAt.Add(-time.Hour), nil, false},
+		{"fingerprint omitted", cert, "", cert.ExpiresAt.Add(-time.Hour), nil, false},
+		{"other device", cert, "fp-2", cert.ExpiresAt.Add(-time.Hour), ErrFingerprintMismatch, false},
+		{"not device bound", unbound, "", cert.ExpiresAt.Add(-time.Hour), ErrNotDeviceBound, false},
+		{"in grace", cert, "fp-1", cert.ExpiresAt.AddDate(0, 0, 3), nil, true},
+		{"grace ended", cert, "fp-1", cert.ExpiresAt.AddDate(0, 0, 7), ErrExpired, false},
+		{"offline allowance exceeded", Certificate{DeviceFingerprint: "fp-1", IssuedAt: cert.IssuedAt, MaxOfflineDays: 10},
+			"fp-1", cert.IssuedAt.AddDate(0, 0, 10), ErrOfflineLimitExceeded, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cert.Check(tt.fingerprint, tt.now); !errors.Is(err, tt.want) {
+				t.Fatalf("Check error = %v, want %v", err, tt.want)
+			}
+			if got := tt.cert.InGrace(tt.now); got != tt.inGrace {
+				t.Fatalf("InGrace = %v, want %v", got, tt.inGrace)
+			}
+		})
+	}
+}
+
+func TestPublicKeyEncoding(t *testing.T) {
+	publicKey, _ := newTestKey(t)
+
+	parsed, err := ParsePublicKey(EncodePublicKey(publicKey))
+	if err != nil || !parsed.Equal(publicKey) {
+		t.Fatalf("ParsePublicKey = %x, %v", parsed, err)
+	}
+	for _, encoded := range []string{"not base64!", "c2hvcnQ="} {
+		if _, err := ParsePublicKey(encoded); err == nil {
+			t.Errorf("ParsePublicKey(%q) succeeded", encoded)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package licensecert
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 오프라인 요청/응답 파일 유형
+const (
+	OfflineActivation   = "activation"
+	OfflineDeactivation = "deactivation"
+)
+
+// ErrInvalidDeviceKey 는 요청 파일의 디바이스 공개키가 올바르지 않을 때 반환됩니다.
+var ErrInvalidDeviceKey = errors.New("invalid offline request device key")
+
+// OfflineRequest 는 서버에 접속할 수 없는 클라이언트가 생성하는 요청 파일 본문입니다.
+// 클라이언트는 설치 시 Ed25519 디바이스 키를 만들어 보관하고, 활성화/비활성화 요청에 같은 키로 서명합니다.
+// 서버는 활성화 때 받은 공개키를 기록해 두었다가 비활성화 요청이 같은 디바이스에서 만들어졌는지 확인합니다.
+type OfflineRequest struct {
+	Version    int             `json:"version"`
+	Type       string          `json:"type"` // activation, deactivation
+	LicenseKey string          `json:"license_key"`
+	DeviceInfo json.RawMessage `json:"device_info"`
+	// DeviceID 는 비활성화 요청에서 활성화 응답으로 받은 디바이스 ID입니다.
+	DeviceID        string    `json:"device_id,omitempty"`
+	DevicePublicKey string    `json:"device_public_key"`
+	Nonce           string    `json:"nonce"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// SignedOfflineRequest 는 디바이스 키로 서명된 요청 파일 형식입니다.
+// 제품이 요청 서명(request_signing)을 사용하면 ProductSignature 에 제품 키로 ProductSigningInput 을 서명해야 합니다
+// (hmac: hex, ed25519: base64). 온라인 요청의 서명 헤더와 같은 역할입니다.
+type SignedOfflineRequest struct {
+	Algorithm        string `json:"alg"`
+	Payload          string `json:"payload"`
+	Signature        string `json:"signature"`
+	ProductSignature string `json:"product_signature,omitempty"`
+}
+
+// ProductSigningInput 은 제품 서명 대상 바이트를 반환합니다. 디바이스 서명과 같은 payload 문자열을 대상으로 하므로
+// 요청 파일 본문을 바꾸면 두 서명이 모두 무효가 됩니다.
+func (s SignedOfflineRequest) ProductSigningInput() []byte {
+	return signingInput(payloadTypeOfflineRequest, []byte(s.Payload))
+}
+
+// OfflineResponse 는 서버가 요청 파일을 처리하고 발급하는 응답 파일 본문입니다.
+// 활성화 응답에는 오프라인 검증용 인증서가 포함되며, 비활성화 응답은 좌석 반납 영수증 역할을 합니다.
+type OfflineResponse struct {
+	Version      int                `json:"version"`
+	Type         string             `json:"type"`
+	LicenseKey   string             `json:"license_key"`
+	DeviceID     string             `json:"device_id"`
+	RequestNonce string             `json:"request_nonce"`
+	Certificate  *SignedCertificate `json:"certificate,omitempty"`
+	IssuedAt     time.Time          `json:"issued_at"`
+}
+
+// SignedOfflineResponse 는 서버 서명 키로 서명된 응답 파일 형식입니다.
+type SignedOfflineResponse struct {
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// SignOfflineRequest 는 요청에 디바이스 공개키를 채우고 디바이스 개인키로 서명합니다.
+func SignOfflineRequest(req OfflineRequest, privateKey ed25519.PrivateKey) (SignedOfflineRequest, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return SignedOfflineRequest{}, errors.New("invalid ed25519 private key")
+	}
+	if req.Version == 0 {
+		req.Version = CurrentVersion
+	}
+	req.DevicePublicKey = EncodePublicKey(privateKey.Public().(ed25519.PublicKey))
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return SignedOfflineRequest{}, fmt.Errorf("failed to encode offline request: %w", err)
+	}
+
+	return SignedOfflineRequest{
+		Algorithm: AlgorithmEd25519,
+		Payload:   encoding.EncodeToString(payload),
+		Signature: encoding.EncodeToString(ed25519.Sign(privateKey, signingInput(payloadTypeOfflineRequest, payload))),
+	}, nil
+}
+
+// VerifyOfflineRequest 는 요청에 포함된 디바이스 공개키로 서명을 검증하고 본문을 반환합니다.
+// 공개키가 실제로 해당 디바이스의 것인지는 서버가 활성화 기록과 비교해 확인해야 합니다.
+func VerifyOfflineRequest(signed SignedOfflineRequest) (OfflineRequest, error) {
+	if signed.Algorithm != AlgorithmEd25519 {
+		return OfflineRequest{}, ErrUnsupportedAlgorithm
+	}
+
+	payload, err := decodeSigned(payloadTypeOfflineRequest, signed.Payload, signed.Signature, nil)
+	if err != nil {
+		return OfflineRequest{}, err
+	}
+
+	var req OfflineRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return OfflineRequest{}, fmt.Errorf("invalid offline request payload: %w", err)
+	}
+
+	publicKey, err := ParsePublicKey(req.DevicePublicKey)
+	if err != nil {
+		return OfflineRequest{}, ErrInvalidDeviceKey
+	}
+	if _, err := decodeSigned(payloadTypeOfflineRequest, signed.Payload, signed.Signature, publicKey); err != nil {
+		return OfflineRequest{}, err
+	}
+	return req, nil
+}
+
+// SignOfflineResponse 는 응답 파일 본문을 서버 개인키로 서명합니다.
+func SignOfflineResponse(resp OfflineResponse, keyID string, privateKey ed25519.PrivateKey) (SignedOfflineResponse, error) {
+	if keyID == "" {
+		return SignedOfflineResponse{}, errors.New("key id is required")
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return SignedOfflineResponse{}, errors.New("invalid ed25519 private key")
+	}
+	if resp.Version == 0 {
+		resp.Version = CurrentVersion
+	}
+
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		return SignedOfflineResponse{}, fmt.Errorf("failed to encode offline response: %w", err)
+	}
+
+	return SignedOfflineResponse{
+		KeyID:     keyID,
+		Algorithm: AlgorithmEd25519,
+		Payload:   encoding.EncodeToString(payload),
+		Signature: encoding.EncodeToString(ed25519.Sign(privateKey, signingInput(payloadTypeOfflineResponse, payload))),
+	}, nil
+}
+
+// VerifyOfflineResponse 는 서버 공개키로 응답 파일의 서명을 검증하고 본문을 반환합니다.
+// 활성화 응답의 인증서는 Verify 와 Check 로 별도 검증합니다.
+func VerifyOfflineResponse(signed SignedOfflineResponse, keys KeySet) (OfflineResponse, error) {
+	if signed.Algorithm != AlgorithmEd25519 {
+		return OfflineResponse{}, ErrUnsupportedAlgorithm
+	}
+
+	publicKey, ok := keys[signed.KeyID]
+	if !ok || len(publicKey) != ed25519.PublicKeySize {
+		return OfflineResponse{}, ErrUnknownKey
+	}
+
+	payload, err := decodeSigned(payloadTypeOfflineResponse, signed.Payload, signed.Signature, publicKey)
+	if err != nil {
+		return OfflineResponse{}, err
+	}
+
+	var resp OfflineResponse
+	if err := json.Unmarshal(payload, &resp); err != nil {
+		return OfflineResponse{}, fmt.Errorf("invalid offline response payload: %w", err)
+	}
+	return resp, nil
+}
+
+// decodeSigned 는 payload/signature 를 디코딩하고 publicKey 가 주어지면 payloadType 형식으로 서명되었는지 검증합니다.
+func decodeSigned(payloadType, encodedPayload, encodedSignature string, publicKey ed25519.PublicKey) ([]byte, error) {
+	payload, err := encoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if publicKey != nil && !ed25519.Verify(publicKey, signingInput(payloadType, payload), signature) {
+		return nil, ErrInvalidSignature
+	}
+	return payload, nil
+}
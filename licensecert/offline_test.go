@@ -0,0 +1,151 @@
+package licensecert
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testOfflineRequest() OfflineRequest {
+	return OfflineRequest{
+		Type:       OfflineActivation,
+		LicenseKey: "ABCD-EFGH-IJKL-MNOP",
+		DeviceInfo: []byte(`{"client_id":"c-1"}`),
+		Nonce:      "nonce-1",
+		CreatedAt:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestOfflineRequestRoundTrip(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+
+	signed, err := SignOfflineRequest(testOfflineRequest(), privateKey)
+	if err != nil {
+		t.Fatalf("SignOfflineRequest: %v", err)
+	}
+	got, err := VerifyOfflineRequest(signed)
+	if err != nil {
+		t.Fatalf("VerifyOfflineRequest: %v", err)
+	}
+	if got.DevicePublicKey != EncodePublicKey(publicKey) || got.Nonce != "nonce-1" || got.Version != CurrentVersion {
+		t.Fatalf("VerifyOfflineRequest = %+v", got)
+	}
+
+	// 제품 서명 대상은 형식 이름과 인코딩된 payload 문자열입니다.
+	if want := []byte("offline-request\n" + signed.Payload); !bytes.Equal(signed.ProductSigningInput(), want) {
+		t.Fatalf("ProductSigningInput = %q, want %q", signed.ProductSigningInput(), want)
+	}
+}
+
+func TestOfflineRequestRejectsTampering(t *testing.T) {
+	_, privateKey := newTestKey(t)
+	_, otherPrivateKey := newTestKey(t)
+
+	signed, err := SignOfflineRequest(testOfflineRequest(), privateKey)
+	if err != nil {
+		t.Fatalf("SignOfflineRequest: %v", err)
+	}
+	other := testOfflineRequest()
+	other.LicenseKey = "ZZZZ-EFGH-IJKL-MNOP"
+	otherSigned, err := SignOfflineRequest(other, otherPrivateKey)
+	if err != nil {
+		t.Fatalf("SignOfflineRequest: %v", err)
+	}
+
+	// 다른 디바이스 키로 서명한 본문에 원래 서명을 붙이면 거부됩니다.
+	forged := otherSigned
+	forged.Signature = signed.Signature
+	if _, err := VerifyOfflineRequest(forged); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("forged request error = %v, want ErrInvalidSignature", err)
+	}
+
+	unsupported := signed
+	unsupported.Algorithm = "HS256"
+	if _, err := VerifyOfflineRequest(unsupported); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Fatalf("unsupported algorithm error = %v", err)
+	}
+}
+
+func TestOfflineResponseRoundTrip(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	cert, err := Sign(testCertificate(), "key-1", privateKey)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	signed, err := SignOfflineResponse(OfflineResponse{
+		Type:         OfflineActivation,
+		LicenseKey:   "ABCD-EFGH-IJKL-MNOP",
+		DeviceID:     "dev-1",
+		RequestNonce: "nonce-1",
+		Certificate:  &cert,
+		IssuedAt:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, "key-1", privateKey)
+	if err != nil {
+		t.Fatalf("SignOfflineResponse: %v", err)
+	}
+
+	got, err := VerifyOfflineResponse(signed, KeySet{"key-1": publicKey})
+	if err != nil {
+		t.Fatalf("VerifyOfflineResponse: %v", err)
+	}
+	if got.RequestNonce != "nonce-1" || got.Certificate == nil {
+		t.Fatalf("VerifyOfflineResponse = %+v", got)
+	}
+	if _, err := Verify(*got.Certificate, KeySet{"key-1": publicKey}); err != nil {
+		t.Fatalf("embedded certificate: %v", err)
+	}
+}
+
+// 같은 서버 키로 서명한 인증서, 오프라인 응답, 폐기 목록은 서로의 형식으로 검증되지 않아야 합니다.
+func TestSignaturesAreBoundToPayloadType(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	keys := KeySet{"key-1": publicKey}
+
+	cert, err := Sign(testCertificate(), "key-1", privateKey)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	response, err := SignOfflineResponse(OfflineResponse{Type: OfflineDeactivation, DeviceID: "dev-1"}, "key-1", privateKey)
+	if err != nil {
+		t.Fatalf("SignOfflineResponse: %v", err)
+	}
+	list, err := SignRevocationList(RevocationList{Serial: 1}, "key-1", privateKey)
+	if err != nil {
+		t.Fatalf("SignRevocationList: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		verify func() error
+	}{
+		{"certificate as offline response", func() error {
+			_, err := VerifyOfflineResponse(SignedOfflineResponse(cert), keys)
+			return err
+		}},
+		{"certificate as revocation list", func() error {
+			_, err := VerifyRevocationList(SignedRevocationList(cert), keys)
+			return err
+		}},
+		{"offline response as certificate", func() error {
+			_, err := Verify(SignedCertificate(response), keys)
+			return err
+		}},
+		{"revocation list as certificate", func() error {
+			_, err := Verify(SignedCertificate(list), keys)
+			return err
+		}},
+		{"revocation list as offline response", func() error {
+			_, err := VerifyOfflineResponse(SignedOfflineResponse(list), keys)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.verify(); !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
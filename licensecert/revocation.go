@@ -0,0 +1,106 @@
+package licensecert
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RevocationList 는 오프라인 클라이언트가 주기적으로 받아 보관하는 폐기 목록 본문입니다.
+// Serial 은 목록 내용이 바뀔 때마다 증가하므로, 클라이언트는 보관 중인 목록보다 Serial 이 작은 목록을 무시해야 합니다.
+// NextUpdate 가 지나면 목록을 새로 받아야 하며, 받지 못하는 동안의 처리(허용/차단)는 클라이언트 정책에 따릅니다.
+type RevocationList struct {
+	Version    int              `json:"version"`
+	Serial     int64            `json:"serial"`
+	IssuedAt   time.Time        `json:"issued_at"`
+	NextUpdate time.Time        `json:"next_update"`
+	Licenses   []RevokedLicense `json:"licenses"`
+	Devices    []RevokedDevice  `json:"devices"`
+}
+
+// RevokedLicense 는 폐기된 라이선스 항목입니다.
+type RevokedLicense struct {
+	LicenseID string    `json:"license_id"`
+	Reason    string    `json:"reason,omitempty"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// RevokedDevice 는 활성화가 해제된 디바이스 항목입니다. 해당 디바이스에 발급된 인증서는 더 이상 유효하지 않습니다.
+type RevokedDevice struct {
+	DeviceID  string    `json:"device_id"`
+	LicenseID string    `json:"license_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// SignedRevocationList 는 서버 서명 키로 서명된 폐기 목록 형식입니다.
+type SignedRevocationList struct {
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// SignRevocationList 는 폐기 목록을 직렬화하고 서버 개인키로 서명합니다.
+func SignRevocationList(list RevocationList, keyID string, privateKey ed25519.PrivateKey) (SignedRevocationList, error) {
+	if keyID == "" {
+		return SignedRevocationList{}, errors.New("key id is required")
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return SignedRevocationList{}, errors.New("invalid ed25519 private key")
+	}
+	if list.Version == 0 {
+		list.Version = CurrentVersion
+	}
+
+	payload, err := json.Marshal(list)
+	if err != nil {
+		return SignedRevocationList{}, fmt.Errorf("failed to encode revocation list: %w", err)
+	}
+
+	return SignedRevocationList{
+		KeyID:     keyID,
+		Algorithm: AlgorithmEd25519,
+		Payload:   encoding.EncodeToString(payload),
+		Signature: encoding.EncodeToString(ed25519.Sign(privateKey, signingInput(payloadTypeRevocationList, payload))),
+	}, nil
+}
+
+// VerifyRevocationList 는 서버 공개키로 폐기 목록의 서명을 검증하고 본문을 반환합니다.
+func VerifyRevocationList(signed SignedRevocationList, keys KeySet) (RevocationList, error) {
+	if signed.Algorithm != AlgorithmEd25519 {
+		return RevocationList{}, ErrUnsupportedAlgorithm
+	}
+
+	publicKey, ok := keys[signed.KeyID]
+	if !ok || len(publicKey) != ed25519.PublicKeySize {
+		return RevocationList{}, ErrUnknownKey
+	}
+
+	payload, err := decodeSigned(payloadTypeRevocationList, signed.Payload, signed.Signature, publicKey)
+	if err != nil {
+		return RevocationList{}, err
+	}
+
+	var list RevocationList
+	if err := json.Unmarshal(payload, &list); err != nil {
+		return RevocationList{}, fmt.Errorf("invalid revocation list payload: %w", err)
+	}
+	return list, nil
+}
+
+// Revokes 는 인증서가 목록에 있는 라이선스 또는 디바이스에 발급되었는지 확인합니다.
+func (l RevocationList) Revokes(cert Certificate) bool {
+	for _, license := range l.Licenses {
+		if license.LicenseID == cert.LicenseID {
+			return true
+		}
+	}
+	for _, device := range l.Devices {
+		if device.DeviceID == cert.DeviceID {
+			return true
+		}
+	}
+	return false
+}
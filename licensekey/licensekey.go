@@ -0,0 +1,226 @@
+// Package licensekey는 제품별 라이선스 키 템플릿(접두사, 길이, 문자 집합, 그룹 크기, 체크섬)에 따른
+// 키 생성과 형식/체크섬 검증을 제공합니다. 데이터베이스에 의존하지 않으므로 클라이언트가 그대로
+// 가져다 사용자가 입력한 키의 오타를 서버 요청 없이 확인할 수 있습니다.
+package licensekey
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// 이름으로 지정할 수 있는 문자 집합
+const (
+	// AlphabetHex 는 기존 키 형식에서 사용하는 16진수 대문자입니다.
+	AlphabetHex = "0123456789ABCDEF"
+	// AlphabetCrockford 는 혼동되는 문자(I, L, O, U)를 뺀 Crockford base32 문자 집합입니다.
+	AlphabetCrockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// 템플릿 제약
+const (
+	maxPrefixLength   = 16
+	maxKeyLength      = 64
+	maxChecksumLength = 8
+	// minEntropyBits 는 체크섬을 제외한 임의 문자의 최소 엔트로피입니다.
+	minEntropyBits = 48
+)
+
+var (
+	// ErrInvalidTemplate 는 템플릿 설정이 올바르지 않을 때 반환됩니다.
+	ErrInvalidTemplate = errors.New("invalid license key template")
+	// ErrMalformedKey 는 키가 템플릿의 형식(접두사, 길이, 문자 집합)과 맞지 않을 때 반환됩니다.
+	ErrMalformedKey = errors.New("license key does not match template")
+	// ErrChecksumMismatch 는 키의 체크섬이 일치하지 않을 때(오타) 반환됩니다.
+	ErrChecksumMismatch = errors.New("license key checksum mismatch")
+)
+
+// Template 은 라이선스 키 생성 규칙입니다.
+// 생성되는 키 형식: [PREFIX-]본문(GroupSize 단위로 '-' 구분)[-체크섬]
+type Template struct {
+	Prefix string `json:"prefix,omitempty"`
+	// Length 는 체크섬을 제외한 임의 문자 수입니다.
+	Length int `json:"length"`
+	// Alphabet 은 "hex", "crockford" 또는 사용할 문자를 그대로 나열한 문자열입니다. 비어 있으면 crockford 입니다.
+	Alphabet  string `json:"alphabet,omitempty"`
+	GroupSize int    `json:"group_size,omitempty"`
+	// ChecksumLength 는 마지막 그룹에 붙는 체크섬 문자 수입니다. 0이면 체크섬을 붙이지 않습니다.
+	ChecksumLength int `json:"checksum_length,omitempty"`
+}
+
+// Legacy 는 템플릿이 없는 제품에 사용하는 기존 키 형식(XXXX-XXXX-XXXX-XXXX, 16진수, 체크섬 없음)입니다.
+var Legacy = Template{Length: 16, Alphabet: "hex", GroupSize: 4}
+
+// Characters 는 템플릿이 사용하는 실제 문자 집합을 반환합니다.
+func (t Template) Characters() string {
+	switch strings.ToLower(strings.TrimSpace(t.Alphabet)) {
+	case "hex":
+		return AlphabetHex
+	case "", "crockford", "base32":
+		return AlphabetCrockford
+	default:
+		return t.Alphabet
+	}
+}
+
+// Validate 는 템플릿 설정을 검사합니다.
+func (t Template) Validate() error {
+	if len(t.Prefix) > maxPrefixLength {
+		return fmt.Errorf("%w: prefix must be at most %d characters", ErrInvalidTemplate, maxPrefixLength)
+	}
+	for _, r := range t.Prefix {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return fmt.Errorf("%w: prefix may contain only letters, digits and '-'", ErrInvalidTemplate)
+		}
+	}
+
+	chars := t.Characters()
+	if len(chars) < 2 || len(chars) > 64 {
+		return fmt.Errorf("%w: alphabet must have 2 to 64 characters", ErrInvalidTemplate)
+	}
+	seen := make(map[rune]bool, len(chars))
+	for _, r := range chars {
+		if r > 0x7f || r <= ' ' || r == '-' || seen[r] {
+			return fmt.Errorf("%w: alphabet must be unique printable ASCII characters without '-'", ErrInvalidTemplate)
+		}
+		seen[r] = true
+	}
+
+	if t.Length <= 0 || t.Length+t.ChecksumLength > maxKeyLength {
+		return fmt.Errorf("%w: length must be between 1 and %d", ErrInvalidTemplate, maxKeyLength-t.ChecksumLength)
+	}
+	if t.ChecksumLength < 0 || t.ChecksumLength > maxChecksumLength {
+		return fmt.Errorf("%w: checksum_length must be between 0 and %d", ErrInvalidTemplate, maxChecksumLength)
+	}
+	if t.GroupSize < 0 {
+		return fmt.Errorf("%w: group_size must not be negative", ErrInvalidTemplate)
+	}
+	if bits := float64(t.Length) * math.Log2(float64(len(chars))); bits < minEntropyBits {
+		return fmt.Errorf("%w: key has %.0f bits of randomness, at least %d required", ErrInvalidTemplate, bits, minEntropyBits)
+	}
+	return nil
+}
+
+// Generate 는 템플릿에 맞는 새 키를 생성합니다.
+func (t Template) Generate() (string, error) {
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+
+	chars := t.Characters()
+	limit := big.NewInt(int64(len(chars)))
+	body := make([]byte, t.Length)
+	for i := range body {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		body[i] = chars[n.Int64()]
+	}
+	return t.format(string(body)), nil
+}
+
+// Normalize 는 사용자가 입력한 키를 표준 형식으로 바꿉니다.
+// 공백과 '-'의 위치, 대소문자 차이를 무시하며 Crockford 문자 집합이면 O→0, I/L→1 로 읽습니다.
+// 체크섬은 검사하지 않습니다.
+func (t Template) Normalize(key string) (string, error) {
+	chars := t.Characters()
+	compact := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(key))
+
+	prefix := strings.ToUpper(strings.ReplaceAll(t.Prefix, "-", ""))
+	if len(compact) < len(prefix) || !strings.EqualFold(compact[:len(prefix)], prefix) {
+		return "", ErrMalformedKey
+	}
+	compact = compact[len(prefix):]
+
+	if strings.ToUpper(chars) == chars {
+		compact = strings.ToUpper(compact)
+	}
+	if chars == AlphabetCrockford {
+		compact = strings.NewReplacer("O", "0", "I", "1", "L", "1").Replace(compact)
+	}
+
+	if len(compact) != t.Length+t.ChecksumLength {
+		return "", ErrMalformedKey
+	}
+	for i := 0; i < len(compact); i++ {
+		if strings.IndexByte(chars, compact[i]) < 0 {
+			return "", ErrMalformedKey
+		}
+	}
+
+	body, checksum := compact[:t.Length], compact[t.Length:]
+	formatted := t.formatBody(body)
+	if checksum != "" {
+		formatted += "-" + checksum
+	}
+	return formatted, nil
+}
+
+// Verify 는 키를 표준 형식으로 바꾸고 체크섬을 검사합니다. 체크섬이 없는 템플릿은 형식만 검사합니다.
+func (t Template) Verify(key string) (string, error) {
+	normalized, err := t.Normalize(key)
+	if err != nil {
+		return "", err
+	}
+	if t.ChecksumLength == 0 {
+		return normalized, nil
+	}
+
+	compact := strings.ReplaceAll(normalized, "-", "")
+	prefix := strings.ToUpper(strings.ReplaceAll(t.Prefix, "-", ""))
+	body := compact[len(prefix) : len(prefix)+t.Length]
+	if compact[len(prefix)+t.Length:] != t.checksum(body) {
+		return "", ErrChecksumMismatch
+	}
+	return normalized, nil
+}
+
+// format 은 본문에 체크섬을 계산해 붙입니다.
+func (t Template) format(body string) string {
+	key := t.formatBody(body)
+	if t.ChecksumLength > 0 {
+		key += "-" + t.checksum(body)
+	}
+	return key
+}
+
+// formatBody 는 접두사와 그룹 구분자를 붙인 본문을 만듭니다.
+func (t Template) formatBody(body string) string {
+	var b strings.Builder
+	if t.Prefix != "" {
+		b.WriteString(strings.ToUpper(t.Prefix))
+		b.WriteByte('-')
+	}
+	for i := 0; i < len(body); i++ {
+		if i > 0 && t.GroupSize > 0 && i%t.GroupSize == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(body[i])
+	}
+	return b.String()
+}
+
+// checksum 은 접두사와 본문의 CRC-32 값을 템플릿 문자 집합으로 ChecksumLength 자리만큼 나타냅니다.
+func (t Template) checksum(body string) string {
+	chars := t.Characters()
+	prefix := strings.ToUpper(strings.ReplaceAll(t.Prefix, "-", ""))
+	value := crc32.ChecksumIEEE([]byte(prefix + body))
+
+	base := uint32(len(chars))
+	out := make([]byte, t.ChecksumLength)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = chars[value%base]
+		value /= base
+	}
+	return string(out)
+}
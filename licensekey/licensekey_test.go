@@ -0,0 +1,127 @@
+package licensekey
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var checksumTemplate = Template{Prefix: "SL", Length: 16, Alphabet: "crockford", GroupSize: 4, ChecksumLength: 4}
+
+func TestGenerateMatchesTemplate(t *testing.T) {
+	pattern := regexp.MustCompile(`^SL-[0-9A-HJKMNP-TV-Z]{4}(-[0-9A-HJKMNP-TV-Z]{4}){4}$`)
+	for i := 0; i < 20; i++ {
+		key, err := checksumTemplate.Generate()
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if !pattern.MatchString(key) {
+			t.Fatalf("Generate = %q, does not match template format", key)
+		}
+		if normalized, err := checksumTemplate.Verify(key); err != nil || normalized != key {
+			t.Fatalf("Verify(%q) = %q, %v", key, normalized, err)
+		}
+	}
+
+	legacy, err := Legacy.Generate()
+	if err != nil {
+		t.Fatalf("Legacy.Generate: %v", err)
+	}
+	if !regexp.MustCompile(`^[0-9A-F]{4}(-[0-9A-F]{4}){3}$`).MatchString(legacy) {
+		t.Fatalf("Legacy.Generate = %q", legacy)
+	}
+}
+
+func TestVerifyNormalizesInput(t *testing.T) {
+	// README 예시 키
+	const key = "SL-954E-R0FH-Y2M6-90PH-86DT"
+
+	for _, input := range []string{
+		key,
+		"sl-954e-r0fh-y2m6-90ph-86dt",
+		"SL954ER0FHY2M690PH86DT",
+		" SL 954E R0FH Y2M6 90PH 86DT ",
+		"SL-954E-ROFH-Y2M6-9OPH-86DT", // O를 0으로 입력
+	} {
+		got, err := checksumTemplate.Verify(input)
+		if err != nil || got != key {
+			t.Errorf("Verify(%q) = %q, %v; want %q", input, got, err, key)
+		}
+	}
+}
+
+func TestVerifyDetectsTypos(t *testing.T) {
+	// 잘린 CRC는 드물게 오류를 놓칠 수 있으므로 무작위 키 대신 고정된 키로 확인합니다.
+	const key = "SL-954E-R0FH-Y2M6-90PH-86DT"
+	compact := []byte(strings.ReplaceAll(strings.TrimPrefix(key, "SL-"), "-", ""))
+	chars := checksumTemplate.Characters()
+
+	// 본문과 체크섬의 어느 한 글자를 다른 글자로 바꾸면 체크섬 불일치입니다.
+	for i := range compact {
+		for j := 0; j < len(chars); j++ {
+			if chars[j] == compact[i] {
+				continue
+			}
+			typo := append([]byte(nil), compact...)
+			typo[i] = chars[j]
+			if _, err := checksumTemplate.Verify("SL" + string(typo)); !errors.Is(err, ErrChecksumMismatch) {
+				t.Fatalf("Verify(typo at %d: %q) error = %v, want ErrChecksumMismatch", i, typo, err)
+			}
+		}
+	}
+
+	// 이웃한 두 글자가 뒤바뀐 경우
+	for i := 0; i+1 < checksumTemplate.Length; i++ {
+		if compact[i] == compact[i+1] {
+			continue
+		}
+		typo := append([]byte(nil), compact...)
+		typo[i], typo[i+1] = typo[i+1], typo[i]
+		if _, err := checksumTemplate.Verify("SL" + string(typo)); !errors.Is(err, ErrChecksumMismatch) {
+			t.Fatalf("Verify(transposed at %d: %q) error = %v, want ErrChecksumMismatch", i, typo, err)
+		}
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"XX-954E-R0FH-Y2M6-90PH-86DT", // 다른 접두사
+		"SL-954E-R0FH-Y2M6-90PH",      // 체크섬 누락
+		"SL-954E-R0FH-Y2M6-90PH-86DT-0",
+		"SL-954E-R0FH-Y2M6-90PH-86D!",
+		"SL-954E-R0FH-Y2M6-90PH-86DU", // U는 Crockford 문자 집합에 없음
+	} {
+		if _, err := checksumTemplate.Verify(input); !errors.Is(err, ErrMalformedKey) {
+			t.Errorf("Verify(%q) error = %v, want ErrMalformedKey", input, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		tmpl  Template
+		valid bool
+	}{
+		{"checksum template", checksumTemplate, true},
+		{"legacy", Legacy, true},
+		{"custom alphabet", Template{Length: 20, Alphabet: "ACDEFGHJKMNPRTWXY347"}, true},
+		{"too little entropy", Template{Length: 8, Alphabet: "hex"}, false},
+		{"checksum too long", Template{Length: 16, ChecksumLength: 9}, false},
+		{"invalid prefix", Template{Prefix: "SL_", Length: 16}, false},
+		{"duplicate alphabet characters", Template{Length: 40, Alphabet: "AAB"}, false},
+		{"alphabet with separator", Template{Length: 40, Alphabet: "AB-"}, false},
+		{"negative group size", Template{Length: 16, GroupSize: -1}, false},
+	}
+	for _, tt := range tests {
+		err := tt.tmpl.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: Validate = %v, want nil", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("%s: Validate = %v, want ErrInvalidTemplate", tt.name, err)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"studiolicense/models"
 	"studiolicense/scheduler"
 	"studiolicense/services"
+	"studiolicense/utils"
 	"syscall"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 )
 
 var productHTTPHandler *handlers.ProductHandler
+var customerHTTPHandler *handlers.CustomerHandler
 
 // @title Studio License Server API
 // @version 1.0
@@ -70,6 +72,14 @@ func main() {
 	}
 	defer database.Close()
 
+	// 라이선스 키 저장 방식 (hashed이면 기존 평문 키를 해시로 일괄 변환)
+	if err := utils.ConfigureLicenseKeyStorage(os.Getenv("LICENSE_KEY_STORAGE"), os.Getenv("LICENSE_KEY_HASH_SECRET")); err != nil {
+		logger.Fatal("Invalid license key storage configuration: %v", err)
+	}
+	if _, err := utils.MigrateLicenseKeysToHashed(); err != nil {
+		logger.Fatal("Failed to migrate license keys to hashed storage: %v", err)
+	}
+
 	// 서비스 계층 초기화
 	sqlExecutor := services.NewSQLExecutor(database.DB)
 	adminResourceService := services.NewAdminResourcePermissionService(sqlExecutor)
@@ -81,6 +91,102 @@ func main() {
 	productService := services.NewProductService(sqlExecutor)
 	productHTTPHandler = handlers.NewProductHandler(productService, scopeResolver)
 
+	customerService := services.NewCustomerService(sqlExecutor)
+	customerHTTPHandler = handlers.NewCustomerHandler(customerService, scopeResolver)
+	handlers.SetCustomerService(customerService)
+
+	// 업로드 파일 저장소 (filesystem 또는 s3, 여러 서버를 운영하면 s3 사용)
+	fileStorage, err := services.NewFileStorage(services.FileStorageConfigFromEnv())
+	if err != nil {
+		logger.Fatal("Invalid file storage configuration: %v", err)
+	}
+	handlers.SetFileStorage(fileStorage, strings.EqualFold(os.Getenv("FILE_DOWNLOAD_REDIRECT"), "true"))
+	logger.Info("File storage: %s", fileStorage.Name())
+
+	// 클라이언트 요청 서명 검증 (제품별 설정, nonce 저장소: memory 또는 mysql)
+	nonceStore := services.NewNonceStore(os.Getenv("REQUEST_NONCE_STORE"), sqlExecutor)
+	requestSkew := time.Duration(utils.GetEnvInt("REQUEST_SIGNATURE_MAX_SKEW_SECONDS", 300)) * time.Second
+	requestVerifier := services.NewRequestSignatureVerifier(sqlExecutor, nonceStore, requestSkew)
+	signedRequest := middleware.RequireRequestSignature(requestVerifier)
+	handlers.SetOfflineRequestVerifier(requestVerifier)
+
+	// 클라이언트 IP 판별 시 전달 헤더를 믿을 프록시 (기본값 loopback)
+	if err := middleware.SetTrustedProxies(os.Getenv("TRUSTED_PROXIES")); err != nil {
+		logger.Fatal("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// 공개 클라이언트 API 속도 제한 (토큰 버킷 저장소: memory 또는 mysql)
+	rateLimitStore := services.NewRateLimitStore(os.Getenv("RATE_LIMIT_STORE"), sqlExecutor)
+	activateRateLimit := clientRateLimit(rateLimitStore, "activate", middleware.RateLimitConfig{
+		PerIP:          middleware.RateLimit{Requests: 20, Per: time.Minute},
+		PerLicense:     middleware.RateLimit{Requests: 10, Per: time.Minute},
+		PerFingerprint: middleware.RateLimit{Requests: 10, Per: time.Minute},
+		FailClosed:     true,
+	})
+	validateRateLimit := clientRateLimit(rateLimitStore, "validate", middleware.RateLimitConfig{
+		PerIP:          middleware.RateLimit{Requests: 120, Per: time.Minute},
+		PerLicense:     middleware.RateLimit{Requests: 60, Per: time.Minute},
+		PerFingerprint: middleware.RateLimit{Requests: 30, Per: time.Minute},
+	})
+	deactivateRateLimit := clientRateLimit(rateLimitStore, "deactivate", middleware.RateLimitConfig{
+		PerIP:          middleware.RateLimit{Requests: 20, Per: time.Minute},
+		PerLicense:     middleware.RateLimit{Requests: 10, Per: time.Minute},
+		PerFingerprint: middleware.RateLimit{Requests: 10, Per: time.Minute},
+	})
+	usageRateLimit := clientRateLimit(rateLimitStore, "usage", middleware.RateLimitConfig{
+		PerIP:      middleware.RateLimit{Requests: 300, Per: time.Minute},
+		PerLicense: middleware.RateLimit{Requests: 300, Per: time.Minute},
+	})
+	trialRateLimit := clientRateLimit(rateLimitStore, "trial", middleware.RateLimitConfig{
+		PerIP:          middleware.RateLimit{Requests: 10, Per: time.Hour},
+		PerFingerprint: middleware.RateLimit{Requests: 3, Per: time.Hour},
+		FailClosed:     true,
+	})
+	publicKeysRateLimit := clientRateLimit(rateLimitStore, "public_keys", middleware.RateLimitConfig{
+		PerIP: middleware.RateLimit{Requests: 60, Per: time.Minute},
+	})
+	revocationsRateLimit := clientRateLimit(rateLimitStore, "revocations", middleware.RateLimitConfig{
+		PerIP: middleware.RateLimit{Requests: 60, Per: time.Minute},
+	})
+	filesRateLimit := clientRateLimit(rateLimitStore, "files", middleware.RateLimitConfig{
+		PerIP: middleware.RateLimit{Requests: 30, Per: time.Minute},
+	})
+	clientLogsRateLimit := clientRateLimit(rateLimitStore, "client_logs", middleware.RateLimitConfig{
+		PerIP:      middleware.RateLimit{Requests: 60, Per: time.Minute},
+		PerLicense: middleware.RateLimit{Requests: 60, Per: time.Minute},
+	})
+	leaseRateLimit := clientRateLimit(rateLimitStore, "lease", middleware.RateLimitConfig{
+		PerIP:      middleware.RateLimit{Requests: 120, Per: time.Minute},
+		PerLicense: middleware.RateLimit{Requests: 120, Per: time.Minute},
+	})
+	offlineRateLimit := clientRateLimit(rateLimitStore, "offline", middleware.RateLimitConfig{
+		PerIP: middleware.RateLimit{Requests: 10, Per: time.Minute},
+	})
+	updateCheckRateLimit := clientRateLimit(rateLimitStore, "update_check", middleware.RateLimitConfig{
+		PerIP:      middleware.RateLimit{Requests: 60, Per: time.Minute},
+		PerLicense: middleware.RateLimit{Requests: 30, Per: time.Minute},
+	})
+
+	paymentWebhookRateLimit := clientRateLimit(rateLimitStore, "payment_webhook", middleware.RateLimitConfig{
+		PerIP: middleware.RateLimit{Requests: 300, Per: time.Minute},
+	})
+
+	// 결제 웹훅 어댑터 (비밀값이 설정된 어댑터만 활성화)
+	paymentProviders := services.NewPaymentProviderRegistry()
+	webhookTolerance := time.Duration(utils.GetEnvInt("PAYMENT_WEBHOOK_TOLERANCE_SECONDS", 300)) * time.Second
+	if secret := os.Getenv("PAYMENT_WEBHOOK_SECRET"); secret != "" {
+		paymentProviders.Register(services.NewGenericPaymentProvider(secret, webhookTolerance))
+	}
+	handlers.SetPaymentProviders(paymentProviders)
+	if names := paymentProviders.Names(); len(names) > 0 {
+		logger.Info("Payment webhook providers: %s", strings.Join(names, ", "))
+	}
+
+	// 라이선스 인증서 서명 키 준비 (없으면 자동 생성)
+	if err := utils.EnsureLicenseSigningKey(); err != nil {
+		logger.Fatal("Failed to prepare license signing key: %v", err)
+	}
+
 	// 스케줄러 시작 (만료된 라이선스 자동 처리)
 	scheduler.StartScheduler()
 
@@ -146,6 +252,28 @@ func main() {
 			middleware.SetJSONHeader,
 		))
 
+	// 라이선스 일괄 발급 API (JSON 또는 CSV 본문, 결과는 CSV)
+	mux.HandleFunc("/api/admin/licenses/bulk",
+		middleware.ChainMiddleware(
+			handlers.BulkCreateLicenses,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.RequirePermissions(models.PermissionLicensesManage),
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
+	// 라이선스 내보내기 API (목록 필터/리소스 스코프 적용, CSV 또는 JSON 스트리밍)
+	mux.HandleFunc("/api/admin/licenses/export",
+		middleware.ChainMiddleware(
+			handlers.ExportLicenses,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.RequirePermissions(models.PermissionLicensesView),
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
 	// 라이선스 디바이스 조회 API (인증 필요)
 	mux.HandleFunc("/api/admin/licenses/devices",
 		middleware.ChainMiddleware(
@@ -214,6 +342,17 @@ func main() {
 			middleware.SetJSONHeader,
 		))
 
+	// 디바이스 릴리스 채널 지정 API (관리자, 인증 필요)
+	mux.HandleFunc("/api/admin/devices/release-channel",
+		middleware.ChainMiddleware(
+			handlers.SetDeviceReleaseChannel,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.RequirePermissions(models.PermissionDevicesManage),
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
 	// 제품 관리 API (인증 필요)
 	mux.HandleFunc("/api/admin/products",
 		middleware.ChainMiddleware(
@@ -224,6 +363,17 @@ func main() {
 			middleware.SetJSONHeader,
 		))
 
+	// 제품 클라이언트 시크릿 재발급 API (요청 서명용)
+	mux.HandleFunc("/api/admin/products/client-secret",
+		middleware.ChainMiddleware(
+			productHTTPHandler.RotateClientSecret,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.RequirePermissions(models.PermissionProductsManage),
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
 	mux.HandleFunc("/api/admin/products/",
 		middleware.ChainMiddleware(
 			productDetailHandler,
@@ -233,6 +383,35 @@ func main() {
 			middleware.SetJSONHeader,
 		))
 
+	// 고객 관리 API (인증 필요)
+	mux.HandleFunc("/api/admin/customers",
+		middleware.ChainMiddleware(
+			customerHandler,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
+	mux.HandleFunc("/api/admin/customers/",
+		middleware.ChainMiddleware(
+			customerDetailHandler,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
+	// 결제 플랜 매핑 관리 API
+	mux.HandleFunc("/api/admin/payment-plans",
+		middleware.ChainMiddleware(
+			paymentPlanHandler,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
 	// 제품 파일 매핑 관리 API
 	mux.HandleFunc("/api/admin/product-files",
 		middleware.ChainMiddleware(
@@ -243,6 +422,48 @@ func main() {
 			middleware.SetJSONHeader,
 		))
 
+	// 제품 파일 단계적 배포 제어 API
+	mux.HandleFunc("/api/admin/product-files/rollout",
+		middleware.ChainMiddleware(
+			handlers.UpdateProductFileRollout,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.RequirePermissions(models.PermissionFilesManage),
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
+	// 제품 기능 카탈로그 API
+	mux.HandleFunc("/api/admin/product-features",
+		middleware.ChainMiddleware(
+			productFeatureRouter,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
+	// 제품 릴리스 API
+	mux.HandleFunc("/api/admin/product-releases",
+		middleware.ChainMiddleware(
+			productReleaseRouter,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
+	// 기능 권한 보유 라이선스 조회 API
+	mux.HandleFunc("/api/admin/entitlements/licenses",
+		middleware.ChainMiddleware(
+			handlers.GetLicensesByEntitlement,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.RequirePermissions(models.PermissionLicensesView),
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
 	// 정책 관리 API (인증 필요)
 	mux.HandleFunc("/api/admin/policies",
 		middleware.ChainMiddleware(
@@ -314,6 +535,27 @@ func main() {
 			middleware.SetJSONHeader,
 		))
 
+	// 라이선스 인증서 서명 키 관리 API (슈퍼 관리자 전용)
+	mux.HandleFunc("/api/admin/signing-keys",
+		middleware.ChainMiddleware(
+			handlers.ListSigningKeys,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.RequireRoles("super_admin"),
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
+	mux.HandleFunc("/api/admin/signing-keys/rotate",
+		middleware.ChainMiddleware(
+			handlers.RotateSigningKey,
+			middleware.LoggingMiddleware,
+			middleware.AuthMiddleware,
+			middleware.RequireRoles("super_admin"),
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+		))
+
 	// 관리자 비밀번호 초기화 API (슈퍼 관리자 전용)
 	mux.HandleFunc("/api/admin/admins/",
 		middleware.ChainMiddleware(
@@ -332,6 +574,8 @@ func main() {
 			middleware.LoggingMiddleware,
 			middleware.CORSMiddleware,
 			middleware.SetJSONHeader,
+			activateRateLimit,
+			signedRequest,
 		))
 
 	mux.HandleFunc("/api/license/validate",
@@ -340,6 +584,123 @@ func main() {
 			middleware.LoggingMiddleware,
 			middleware.CORSMiddleware,
 			middleware.SetJSONHeader,
+			validateRateLimit,
+			signedRequest,
+		))
+
+	mux.HandleFunc("/api/license/deactivate",
+		middleware.ChainMiddleware(
+			handlers.DeactivateLicense,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			deactivateRateLimit,
+			signedRequest,
+		))
+
+	mux.HandleFunc("/api/license/lease/checkout",
+		middleware.ChainMiddleware(
+			handlers.CheckoutLease,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			leaseRateLimit,
+			signedRequest,
+		))
+
+	mux.HandleFunc("/api/license/lease/renew",
+		middleware.ChainMiddleware(
+			handlers.RenewLease,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			leaseRateLimit,
+			signedRequest,
+		))
+
+	mux.HandleFunc("/api/license/lease/release",
+		middleware.ChainMiddleware(
+			handlers.ReleaseLease,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			leaseRateLimit,
+			signedRequest,
+		))
+
+	mux.HandleFunc("/api/license/offline/activate",
+		middleware.ChainMiddleware(
+			handlers.OfflineActivateLicense,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			offlineRateLimit,
+		))
+
+	mux.HandleFunc("/api/license/offline/deactivate",
+		middleware.ChainMiddleware(
+			handlers.OfflineDeactivateLicense,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			offlineRateLimit,
+		))
+
+	mux.HandleFunc("/api/license/usage",
+		middleware.ChainMiddleware(
+			handlers.ReportUsage,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			usageRateLimit,
+			signedRequest,
+		))
+
+	mux.HandleFunc("/api/license/update-check",
+		middleware.ChainMiddleware(
+			handlers.CheckForUpdate,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			updateCheckRateLimit,
+			signedRequest,
+		))
+
+	mux.HandleFunc("/api/license/trial",
+		middleware.ChainMiddleware(
+			handlers.RequestTrialLicense,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			trialRateLimit,
+			signedRequest,
+		))
+
+	mux.HandleFunc("/api/license/public-keys",
+		middleware.ChainMiddleware(
+			handlers.GetLicensePublicKeys,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			publicKeysRateLimit,
+		))
+
+	mux.HandleFunc("/api/license/revocations",
+		middleware.ChainMiddleware(
+			handlers.GetRevocationList,
+			middleware.LoggingMiddleware,
+			middleware.CORSMiddleware,
+			middleware.SetJSONHeader,
+			revocationsRateLimit,
+		))
+
+	// 결제 서비스 웹훅 (서명으로 인증)
+	mux.HandleFunc("/api/webhooks/payments/",
+		middleware.ChainMiddleware(
+			handlers.HandlePaymentWebhook,
+			middleware.LoggingMiddleware,
+			middleware.SetJSONHeader,
+			paymentWebhookRateLimit,
 		))
 
 	mux.HandleFunc("/api/license/files/",
@@ -347,6 +708,7 @@ func main() {
 			handlers.DownloadProductFile,
 			middleware.LoggingMiddleware,
 			middleware.CORSMiddleware,
+			filesRateLimit,
 		))
 
 	// 클라이언트 로그 API (인증 불필요)
@@ -356,6 +718,7 @@ func main() {
 			middleware.LoggingMiddleware,
 			middleware.CORSMiddleware,
 			middleware.SetJSONHeader,
+			clientLogsRateLimit,
 		))
 
 	// 관리자 - 클라이언트 로그 조회 API (인증 필요)
@@ -473,15 +836,138 @@ func licenseHandler(w http.ResponseWriter, r *http.Request) {
 func licenseDetailHandler(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/admin/licenses/")
 	path = strings.Trim(path, "/")
+	action := ""
 	if path != "" {
-		if strings.Contains(path, "/") {
-			w.WriteHeader(http.StatusNotFound)
-			return
+		if parts := strings.SplitN(path, "/", 2); len(parts) == 2 {
+			path, action = parts[0], parts[1]
 		}
 		ctx := context.WithValue(r.Context(), "path_license_id", path)
 		r = r.WithContext(ctx)
 	}
 
+	// 하위 경로 액션: /api/admin/licenses/{id}/{action}
+	switch action {
+	case "":
+	case "convert":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+			return
+		}
+		handlers.ConvertTrialLicense(w, r)
+		return
+	case "renew", "extend":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+			return
+		}
+		if action == "renew" {
+			handlers.RenewLicense(w, r)
+		} else {
+			handlers.ExtendLicense(w, r)
+		}
+		return
+	case "change-product":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+			return
+		}
+		handlers.ChangeLicenseProduct(w, r)
+		return
+	case "release-channel":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+			return
+		}
+		handlers.SetLicenseReleaseChannel(w, r)
+		return
+	case "revoke":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+			return
+		}
+		handlers.RevokeLicense(w, r)
+		return
+	case "suspend", "resume":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+			return
+		}
+		if action == "suspend" {
+			handlers.SuspendLicense(w, r)
+		} else {
+			handlers.ResumeLicense(w, r)
+		}
+		return
+	case "history":
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesView) {
+			return
+		}
+		handlers.GetLicenseHistory(w, r)
+		return
+	case "entitlements":
+		switch r.Method {
+		case http.MethodGet:
+			if !middleware.EnsurePermission(w, r, models.PermissionLicensesView) {
+				return
+			}
+			handlers.ListLicenseEntitlements(w, r)
+		case http.MethodPost:
+			if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+				return
+			}
+			handlers.GrantLicenseEntitlement(w, r)
+		case http.MethodDelete:
+			if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+				return
+			}
+			handlers.RevokeLicenseEntitlement(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	case "usage":
+		switch r.Method {
+		case http.MethodGet:
+			if !middleware.EnsurePermission(w, r, models.PermissionLicensesView) {
+				return
+			}
+			handlers.GetLicenseUsage(w, r)
+		case http.MethodDelete:
+			if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+				return
+			}
+			handlers.ResetLicenseUsage(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		if !middleware.EnsurePermission(w, r, models.PermissionLicensesView) {
@@ -503,6 +989,18 @@ func licenseDetailHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// clientRateLimit 라우트별 속도 제한 미들웨어를 만듭니다.
+// RATE_LIMIT_<NAME> 환경변수("ip=60/1m,license=30/1m,fingerprint=30/1m")로 기본값을 재정의할 수 있습니다.
+func clientRateLimit(store services.RateLimitStore, name string, defaults middleware.RateLimitConfig) func(http.HandlerFunc) http.HandlerFunc {
+	defaults.Name = name
+	envName := "RATE_LIMIT_" + strings.ToUpper(name)
+	cfg, err := middleware.ParseRateLimitConfig(os.Getenv(envName), defaults)
+	if err != nil {
+		logger.Warn("Invalid %s, using defaults: %v", envName, err)
+	}
+	return middleware.RateLimitMiddleware(store, cfg)
+}
+
 // productHandler 제품 목록/생성 핸들러
 func productHandler(w http.ResponseWriter, r *http.Request) {
 	if productHTTPHandler == nil {
@@ -556,6 +1054,59 @@ func productDetailHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// customerHandler 고객 목록/생성 핸들러
+func customerHandler(w http.ResponseWriter, r *http.Request) {
+	if customerHTTPHandler == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("customer handler not initialized", nil))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !middleware.EnsurePermission(w, r, models.PermissionCustomersView) {
+			return
+		}
+		customerHTTPHandler.List(w, r)
+	case http.MethodPost:
+		if !middleware.EnsurePermission(w, r, models.PermissionCustomersManage) {
+			return
+		}
+		customerHTTPHandler.Create(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// customerDetailHandler 고객 상세/수정/삭제 핸들러
+func customerDetailHandler(w http.ResponseWriter, r *http.Request) {
+	if customerHTTPHandler == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse("customer handler not initialized", nil))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !middleware.EnsurePermission(w, r, models.PermissionCustomersView) {
+			return
+		}
+		customerHTTPHandler.Get(w, r)
+	case http.MethodPut:
+		if !middleware.EnsurePermission(w, r, models.PermissionCustomersManage) {
+			return
+		}
+		customerHTTPHandler.Update(w, r)
+	case http.MethodDelete:
+		if !middleware.EnsurePermission(w, r, models.PermissionCustomersManage) {
+			return
+		}
+		customerHTTPHandler.Delete(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
 // productFileRouter 제품-파일 매핑 핸들러
 func productFileRouter(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -584,6 +1135,75 @@ func productFileRouter(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// productFeatureRouter 제품 기능 카탈로그 핸들러
+func productFeatureRouter(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if !middleware.EnsurePermission(w, r, models.PermissionProductsView) {
+			return
+		}
+		handlers.ListProductFeatures(w, r)
+	case http.MethodPost:
+		if !middleware.EnsurePermission(w, r, models.PermissionProductsManage) {
+			return
+		}
+		handlers.CreateProductFeature(w, r)
+	case http.MethodDelete:
+		if !middleware.EnsurePermission(w, r, models.PermissionProductsManage) {
+			return
+		}
+		handlers.DeleteProductFeature(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// productReleaseRouter 제품 릴리스 핸들러
+func productReleaseRouter(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if !middleware.EnsurePermission(w, r, models.PermissionProductsView) {
+			return
+		}
+		handlers.ListProductReleases(w, r)
+	case http.MethodPost:
+		if !middleware.EnsurePermission(w, r, models.PermissionProductsManage) {
+			return
+		}
+		handlers.CreateProductRelease(w, r)
+	case http.MethodDelete:
+		if !middleware.EnsurePermission(w, r, models.PermissionProductsManage) {
+			return
+		}
+		handlers.DeleteProductRelease(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// paymentPlanHandler 결제 플랜 매핑 목록/저장/삭제 핸들러
+func paymentPlanHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesView) {
+			return
+		}
+		handlers.GetPaymentPlans(w, r)
+	case http.MethodPost:
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+			return
+		}
+		handlers.SavePaymentPlan(w, r)
+	case http.MethodDelete:
+		if !middleware.EnsurePermission(w, r, models.PermissionLicensesManage) {
+			return
+		}
+		handlers.DeletePaymentPlan(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
 // policyHandler 정책 목록/생성 핸들러
 func policyHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustedProxies X-Forwarded-For, X-Real-IP 헤더를 믿을 수 있는 프록시 대역
+// 기본값은 같은 서버의 리버스 프록시(loopback)만 신뢰합니다.
+var trustedProxies = mustParseTrustedProxies("127.0.0.0/8,::1/128")
+
+// SetTrustedProxies 신뢰할 프록시 목록("10.0.0.0/8,192.168.1.10")을 설정합니다.
+// 빈 문자열이면 기본값(loopback)을 유지하고, "none"이면 어떤 프록시 헤더도 믿지 않습니다.
+func SetTrustedProxies(spec string) error {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil
+	}
+	if strings.EqualFold(spec, "none") {
+		trustedProxies = nil
+		return nil
+	}
+	nets, err := parseTrustedProxies(spec)
+	if err != nil {
+		return err
+	}
+	trustedProxies = nets
+	return nil
+}
+
+func mustParseTrustedProxies(spec string) []*net.IPNet {
+	nets, err := parseTrustedProxies(spec)
+	if err != nil {
+		panic(err)
+	}
+	return nets
+}
+
+func parseTrustedProxies(spec string) ([]*net.IPNet, error) {
+	nets := []*net.IPNet{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(part, "/") {
+			ip := net.ParseIP(part)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", part)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", part)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+func isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// getClientIP 클라이언트 IP 추출
+// 연결한 주소(RemoteAddr)가 신뢰하는 프록시일 때만 전달 헤더를 읽으며,
+// X-Forwarded-For는 오른쪽부터 신뢰하지 않는 첫 주소를 사용합니다. 클라이언트가 임의로 넣은 왼쪽 값은 무시됩니다.
+func getClientIP(r *http.Request) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip) {
+		return ip
+	}
+
+	// X-Forwarded-For 헤더 확인 (프록시 환경)
+	if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		hops := strings.Split(strings.Join(values, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop == "" {
+				continue
+			}
+			if !isTrustedProxy(hop) {
+				return hop
+			}
+			ip = hop
+		}
+		return ip
+	}
+
+	// X-Real-IP 헤더 확인
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); xri != "" {
+		return xri
+	}
+	return ip
+}
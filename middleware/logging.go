@@ -29,6 +29,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap http.ResponseController가 스트리밍 응답을 flush할 수 있도록 원래 ResponseWriter를 반환합니다.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // LoggingMiddleware HTTP 요청/응답 로깅 미들웨어
 func LoggingMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -84,29 +89,6 @@ func getLogLevelForStatus(statusCode int) logger.LogLevel {
 	}
 }
 
-// getClientIP 클라이언트 IP 추출
-func getClientIP(r *http.Request) string {
-	// X-Forwarded-For 헤더 확인 (프록시 환경)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	// X-Real-IP 헤더 확인
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
-	}
-
-	// RemoteAddr 사용
-	ip := r.RemoteAddr
-	if idx := strings.LastIndex(ip, ":"); idx != -1 {
-		ip = ip[:idx]
-	}
-	return ip
-}
-
 // generateRequestID 요청 ID 생성
 func generateRequestID() string {
 	id, _ := utils.GenerateID("")
@@ -217,7 +199,7 @@ func CORSMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-License-Timestamp, X-License-Nonce, X-License-Signature")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
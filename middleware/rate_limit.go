@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"studiolicense/logger"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// RateLimiter 토큰 버킷 저장소 (services.RateLimitStore)
+type RateLimiter interface {
+	Take(ctx context.Context, key string, limit int, per time.Duration) (bool, time.Duration, error)
+}
+
+// RateLimit per 동안 허용하는 요청 수. Requests가 0 이하이면 제한하지 않습니다.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Enabled 제한이 설정되어 있는지 여부
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// RateLimitConfig 라우트별 속도 제한 설정
+type RateLimitConfig struct {
+	Name           string    // 버킷 키 구분용 라우트 이름
+	PerIP          RateLimit // 클라이언트 IP 기준
+	PerLicense     RateLimit // 요청 본문 license_key 기준
+	PerFingerprint RateLimit // 요청 본문 device_info 핑거프린트 기준
+	FailClosed     bool      // 저장소 오류 시 통과시키지 않고 503으로 거부 (키 대입 표적이 되는 라우트)
+}
+
+// ParseRateLimitConfig 환경변수 형식("ip=60/1m,license=30/1m,fingerprint=30/1m")을 읽어 base 설정을 덮어씁니다.
+// 값이 비어 있으면 base를 그대로 반환하고, 항목 값을 0으로 주면 해당 기준의 제한을 끕니다.
+func ParseRateLimitConfig(spec string, base RateLimitConfig) (RateLimitConfig, error) {
+	cfg := base
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return base, fmt.Errorf("invalid rate limit entry %q", part)
+		}
+		limit, err := parseRateLimit(strings.TrimSpace(value))
+		if err != nil {
+			return base, fmt.Errorf("invalid rate limit %q: %w", part, err)
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "ip":
+			cfg.PerIP = limit
+		case "license":
+			cfg.PerLicense = limit
+		case "fingerprint":
+			cfg.PerFingerprint = limit
+		default:
+			return base, fmt.Errorf("unknown rate limit key %q", name)
+		}
+	}
+	return cfg, nil
+}
+
+// parseRateLimit "30/1m" 형식을 읽습니다. 기간을 생략하면 1분으로 봅니다.
+func parseRateLimit(value string) (RateLimit, error) {
+	countRaw, perRaw, hasPer := strings.Cut(value, "/")
+	count, err := strconv.Atoi(strings.TrimSpace(countRaw))
+	if err != nil || count < 0 {
+		return RateLimit{}, fmt.Errorf("invalid request count")
+	}
+	per := time.Minute
+	if hasPer {
+		perRaw = strings.TrimSpace(perRaw)
+		if !strings.ContainsAny(perRaw, "0123456789") {
+			perRaw = "1" + perRaw
+		}
+		per, err = time.ParseDuration(perRaw)
+		if err != nil || per <= 0 {
+			return RateLimit{}, fmt.Errorf("invalid period")
+		}
+	}
+	return RateLimit{Requests: count, Per: per}, nil
+}
+
+// RateLimitMiddleware 클라이언트 IP, 라이선스 키, 디바이스 핑거프린트별 토큰 버킷으로 요청을 제한합니다.
+// 한도를 넘으면 429와 Retry-After 헤더를 반환합니다. 저장소 오류 시에는 해당 버킷만 건너뛰고,
+// FailClosed 라우트는 503으로 거부합니다.
+func RateLimitMiddleware(limiter RateLimiter, cfg RateLimitConfig) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		if limiter == nil || (!cfg.PerIP.Enabled() && !cfg.PerLicense.Enabled() && !cfg.PerFingerprint.Enabled()) {
+			return next
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			type bucket struct {
+				key   string
+				limit RateLimit
+			}
+			buckets := make([]bucket, 0, 3)
+			if cfg.PerIP.Enabled() {
+				buckets = append(buckets, bucket{"ip:" + getClientIP(r), cfg.PerIP})
+			}
+
+			if (cfg.PerLicense.Enabled() || cfg.PerFingerprint.Enabled()) && r.Body != nil && r.Method != http.MethodGet {
+				body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodyBytes))
+				r.Body.Close()
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					_ = json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+
+				var target struct {
+					LicenseKey string             `json:"license_key"`
+					DeviceInfo *models.DeviceInfo `json:"device_info"`
+				}
+				_ = json.Unmarshal(body, &target)
+
+				// 대소문자나 구분자만 바꾼 키가 새 버킷을 받지 않도록 표준 형식으로 바꿔 사용합니다.
+				licenseKey, _ := utils.NormalizeLicenseKey(target.LicenseKey)
+				if licenseKey = strings.ToUpper(licenseKey); licenseKey != "" && cfg.PerLicense.Enabled() {
+					buckets = append(buckets, bucket{"license:" + licenseKey, cfg.PerLicense})
+				}
+				if info := target.DeviceInfo; info != nil && cfg.PerFingerprint.Enabled() {
+					fingerprint := utils.GenerateDeviceFingerprint(
+						info.ClientID, info.CPUID, info.MotherboardSN, info.MACAddress, info.DiskSerial, info.MachineID,
+					)
+					buckets = append(buckets, bucket{"fp:" + fingerprint, cfg.PerFingerprint})
+				}
+			}
+
+			for _, b := range buckets {
+				allowed, retryAfter, err := limiter.Take(r.Context(), cfg.Name+":"+b.key, b.limit.Requests, b.limit.Per)
+				if err != nil {
+					logger.WithFields(map[string]interface{}{
+						"request_id":  r.Context().Value("request_id"),
+						"route":       cfg.Name,
+						"bucket":      strings.SplitN(b.key, ":", 2)[0],
+						"fail_closed": cfg.FailClosed,
+						"error":       err.Error(),
+					}).Warn("Rate limit store error")
+					if cfg.FailClosed {
+						w.Header().Set("Content-Type", "application/json")
+						w.WriteHeader(http.StatusServiceUnavailable)
+						_ = json.NewEncoder(w).Encode(models.ErrorResponse("Rate limiter unavailable", nil))
+						return
+					}
+					// 남은 버킷은 계속 확인해 저장소 오류 하나로 모든 제한이 풀리지 않게 합니다.
+					continue
+				}
+				if allowed {
+					continue
+				}
+
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				logger.WithFields(map[string]interface{}{
+					"request_id":  r.Context().Value("request_id"),
+					"route":       cfg.Name,
+					"bucket":      strings.SplitN(b.key, ":", 2)[0],
+					"ip":          getClientIP(r),
+					"retry_after": seconds,
+				}).Warn("Rate limit exceeded")
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				w.WriteHeader(http.StatusTooManyRequests)
+				resp := models.ErrorResponse("Too many requests", nil)
+				resp.Data = map[string]interface{}{"retry_after": seconds}
+				_ = json.NewEncoder(w).Encode(resp)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+	}
+}
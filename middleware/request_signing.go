@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"studiolicense/logger"
+	"studiolicense/models"
+)
+
+// maxSignedBodyBytes 서명 검증을 위해 읽는 요청 본문 최대 크기
+const maxSignedBodyBytes = 1 << 20
+
+// RequestVerifier 클라이언트 요청 서명 검증기
+type RequestVerifier interface {
+	VerifyRequest(ctx context.Context, method, path string, header http.Header, body []byte) error
+}
+
+// RequireRequestSignature 요청 본문을 읽어 서명을 검증한 뒤 핸들러가 다시 읽을 수 있도록 본문을 복원합니다.
+// 서명을 켜지 않은 제품의 요청은 verifier가 통과시킵니다.
+func RequireRequestSignature(verifier RequestVerifier) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if verifier == nil || r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodyBytes))
+			r.Body.Close()
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(models.ErrorResponse("Invalid request body", err))
+				return
+			}
+
+			if err := verifier.VerifyRequest(r.Context(), r.Method, r.URL.Path, r.Header, body); err != nil {
+				logger.WithFields(map[string]interface{}{
+					"request_id": r.Context().Value("request_id"),
+					"path":       r.URL.Path,
+					"ip":         getClientIP(r),
+					"error":      err.Error(),
+				}).Warn("Request signature rejected")
+
+				w.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(w).Encode(models.ErrorResponse("Request signature rejected", err))
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		}
+	}
+}
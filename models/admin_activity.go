@@ -20,6 +20,14 @@ const (
 	AdminActionCreateLicense     = "create_license"
 	AdminActionUpdateLicense     = "update_license"
 	AdminActionDeleteLicense     = "delete_license"
+	AdminActionBulkCreateLicense = "bulk_create_license"
+	AdminActionExportLicenses    = "export_licenses"
+	AdminActionRenewLicense      = "renew_license"
+	AdminActionExtendLicense     = "extend_license"
+	AdminActionSuspendLicense    = "suspend_license"
+	AdminActionResumeLicense     = "resume_license"
+	AdminActionRevokeLicense     = "revoke_license"
+	AdminActionChangeProduct     = "change_license_product"
 	AdminActionDeactivateDev     = "deactivate_device"
 	AdminActionDeactivateDevice  = "deactivate_device"
 	AdminActionReactivateDev     = "reactivate_device"
@@ -36,5 +44,23 @@ const (
 	AdminActionDownloadFile      = "download_file"
 	AdminActionAttachProductFile = "attach_product_file"
 	AdminActionUpdateProductFile = "update_product_file"
+	AdminActionUpdateRollout     = "update_product_file_rollout"
 	AdminActionDeleteProductFile = "delete_product_file"
+	AdminActionRotateSigningKey  = "rotate_signing_key"
+	AdminActionConvertTrial      = "convert_trial"
+	AdminActionCreateFeature     = "create_feature"
+	AdminActionDeleteFeature     = "delete_feature"
+	AdminActionCreateRelease     = "create_release"
+	AdminActionDeleteRelease     = "delete_release"
+	AdminActionSetReleaseChannel = "set_release_channel"
+	AdminActionGrantEntitlement  = "grant_entitlement"
+	AdminActionRevokeEntitlement = "revoke_entitlement"
+	AdminActionResetUsage        = "reset_usage"
+	AdminActionRotateSecret      = "rotate_client_secret"
+	AdminActionCreateCustomer    = "create_customer"
+	AdminActionUpdateCustomer    = "update_customer"
+	AdminActionDeleteCustomer    = "delete_customer"
+	AdminActionSavePaymentPlan   = "save_payment_plan"
+	AdminActionDeletePaymentPlan = "delete_payment_plan"
+	AdminActionPaymentWebhook    = "payment_webhook"
 )
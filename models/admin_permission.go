@@ -13,6 +13,9 @@ const (
 	PermissionProductsView   = "products.view"
 	PermissionProductsManage = "products.manage"
 
+	PermissionCustomersView   = "customers.view"
+	PermissionCustomersManage = "customers.manage"
+
 	PermissionPoliciesView   = "policies.view"
 	PermissionPoliciesManage = "policies.manage"
 
@@ -75,6 +78,18 @@ var AdminPermissionCatalog = []AdminPermissionDefinition{
 		Description: "제품을 생성하거나 수정할 수 있습니다.",
 		Category:    "제품",
 	},
+	{
+		Key:         PermissionCustomersView,
+		Label:       "고객 조회",
+		Description: "고객 목록과 연락처, 연결된 라이선스 수를 볼 수 있습니다.",
+		Category:    "고객",
+	},
+	{
+		Key:         PermissionCustomersManage,
+		Label:       "고객 관리",
+		Description: "고객을 생성, 수정, 삭제할 수 있습니다.",
+		Category:    "고객",
+	},
 	{
 		Key:         PermissionPoliciesView,
 		Label:       "정책 조회",
@@ -10,6 +10,8 @@ const (
 	ResourceTypeLicenses = "licenses"
 	ResourceTypePolicies = "policies"
 	ResourceTypeProducts = "products"
+
+	ResourceTypeCustomers = "customers"
 )
 
 // AdminResourceTypes lists all supported resource types.
@@ -17,6 +19,7 @@ var AdminResourceTypes = []string{
 	ResourceTypeLicenses,
 	ResourceTypePolicies,
 	ResourceTypeProducts,
+	ResourceTypeCustomers,
 }
 
 // Resource mode keys describing how access should be granted.
@@ -43,7 +46,7 @@ type AdminResourcePermissionConfig struct {
 // IsValidResourceType returns true when the resource type is supported.
 func IsValidResourceType(resourceType string) bool {
 	switch strings.ToLower(strings.TrimSpace(resourceType)) {
-	case ResourceTypeLicenses, ResourceTypePolicies, ResourceTypeProducts:
+	case ResourceTypeLicenses, ResourceTypePolicies, ResourceTypeProducts, ResourceTypeCustomers:
 		return true
 	default:
 		return false
@@ -0,0 +1,50 @@
+package models
+
+// Customer 고객(조직) 정보
+type Customer struct {
+	ID           string            `json:"id" db:"id"`
+	Name         string            `json:"name" db:"name"`                 // 고객(담당자) 이름
+	Organization string            `json:"organization" db:"organization"` // 회사/기관명
+	Email        string            `json:"email" db:"email"`               // 대표 이메일 (고객별 고유)
+	Phone        string            `json:"phone" db:"phone"`
+	ExternalID   *string           `json:"external_id" db:"external_id"` // CRM/결제 시스템 등 외부 시스템의 고객 ID (고유)
+	Contacts     []CustomerContact `json:"contacts" db:"contacts"`       // 추가 담당자 목록
+	Notes        string            `json:"notes" db:"notes"`
+	LicenseCount int               `json:"license_count" db:"license_count"` // 연결된 라이선스 수
+	CreatedBy    string            `json:"created_by" db:"created_by"`
+	CreatedAt    string            `json:"created_at" db:"created_at"`
+	UpdatedAt    string            `json:"updated_at" db:"updated_at"`
+}
+
+// CustomerContact 고객사 담당자 정보
+type CustomerContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone,omitempty"`
+	Role  string `json:"role,omitempty"` // 예: billing, technical
+}
+
+// CreateCustomerRequest 고객 생성 요청
+type CreateCustomerRequest struct {
+	Name         string            `json:"name" binding:"required"`
+	Organization string            `json:"organization"`
+	Email        string            `json:"email" binding:"required,email"`
+	Phone        string            `json:"phone"`
+	ExternalID   string            `json:"external_id"`
+	Contacts     []CustomerContact `json:"contacts"`
+	Notes        string            `json:"notes"`
+}
+
+// UpdateCustomerRequest 고객 수정 요청
+// 이름과 이메일을 바꾸면 연결된 라이선스의 customer_name, customer_email도 함께 바뀝니다.
+type UpdateCustomerRequest struct {
+	Name         string `json:"name"`
+	Organization string `json:"organization"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	// ExternalID 생략하면 기존 값 유지, 빈 문자열이면 삭제
+	ExternalID *string `json:"external_id"`
+	// Contacts 생략하면 기존 값 유지, 빈 배열이면 모두 삭제
+	Contacts []CustomerContact `json:"contacts"`
+	Notes    string            `json:"notes"`
+}
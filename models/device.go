@@ -11,6 +11,7 @@ type DeviceActivation struct {
 	ActivatedAt       string  `json:"activated_at" db:"activated_at"`
 	LastValidatedAt   string  `json:"last_validated_at" db:"last_validated_at"`
 	DeactivatedAt     *string `json:"deactivated_at,omitempty" db:"deactivated_at"`
+	ReleaseChannel    *string `json:"release_channel" db:"release_channel"` // nil이면 라이선스의 채널을 따름
 }
 
 // DeviceStatus 상태 상수
@@ -42,6 +43,8 @@ type ActivateRequest struct {
 type ValidateRequest struct {
 	LicenseKey string     `json:"license_key" binding:"required"`
 	DeviceInfo DeviceInfo `json:"device_info" binding:"required"`
+	// AppVersion 실행 중인 클라이언트 버전 (선택사항, 보내면 유지보수 기간 기준으로 사용 가능 여부를 확인)
+	AppVersion string `json:"app_version"`
 }
 
 // DeactivateRequest 라이선스 비활성화 요청
@@ -5,7 +5,7 @@ type DeviceActivityLog struct {
 	ID        int    `json:"id" db:"id"`
 	DeviceID  string `json:"device_id" db:"device_id"`
 	LicenseID string `json:"license_id" db:"license_id"`
-	Action    string `json:"action" db:"action"` // activated, validated, deactivated, reactivated
+	Action    string `json:"action" db:"action"` // activated, validated, deactivated, reactivated, self_deactivated, fingerprint_drift
 	Details   string `json:"details" db:"details"`
 	CreatedAt string `json:"created_at" db:"created_at"`
 }
@@ -16,4 +16,10 @@ const (
 	DeviceActionValidated   = "validated"
 	DeviceActionDeactivated = "deactivated"
 	DeviceActionReactivated = "reactivated"
+	// DeviceActionSelfDeactivated 최종 사용자가 직접 디바이스를 해제한 경우 (셀프 이전 횟수 집계에 사용)
+	DeviceActionSelfDeactivated = "self_deactivated"
+	// DeviceActionFingerprintDrift 일부 하드웨어 구성요소가 바뀌었지만 임계값 이상 일치해 기존 디바이스로 인정된 경우
+	DeviceActionFingerprintDrift = "fingerprint_drift"
+	// DeviceActionReleaseChannelChanged 관리자가 디바이스의 릴리스 채널을 지정하거나 해제한 경우
+	DeviceActionReleaseChannelChanged = "release_channel_changed"
 )
@@ -0,0 +1,47 @@
+package models
+
+// ProductFeature 제품별 기능 카탈로그 항목
+type ProductFeature struct {
+	ID          string `json:"id" db:"id"`
+	ProductID   string `json:"product_id" db:"product_id"`
+	FeatureKey  string `json:"feature_key" db:"feature_key"` // 클라이언트가 확인하는 기능 식별자 (예: export_4k)
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+	CreatedAt   string `json:"created_at" db:"created_at"`
+}
+
+// LicenseEntitlement 라이선스에 부여된 기능 권한
+type LicenseEntitlement struct {
+	ID          string  `json:"id" db:"id"`
+	LicenseID   string  `json:"license_id" db:"license_id"`
+	FeatureID   string  `json:"feature_id" db:"feature_id"`
+	FeatureKey  string  `json:"feature_key" db:"feature_key"`
+	FeatureName string  `json:"feature_name" db:"feature_name"`
+	Quantity    *int    `json:"quantity" db:"quantity"`     // nil이면 수량 제한 없음
+	ExpiresAt   *string `json:"expires_at" db:"expires_at"` // nil이면 라이선스 만료일을 따름
+	CreatedBy   string  `json:"created_by" db:"created_by"`
+	CreatedAt   string  `json:"created_at" db:"created_at"`
+}
+
+// EntitlementResponse 클라이언트 응답용 기능 권한
+type EntitlementResponse struct {
+	FeatureKey string  `json:"feature_key"`
+	Name       string  `json:"name"`
+	Quantity   *int    `json:"quantity,omitempty"`
+	ExpiresAt  *string `json:"expires_at,omitempty"`
+}
+
+// CreateProductFeatureRequest 기능 카탈로그 등록 요청
+type CreateProductFeatureRequest struct {
+	ProductID   string `json:"product_id" binding:"required"`
+	FeatureKey  string `json:"feature_key" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// GrantEntitlementRequest 라이선스 기능 부여 요청 (같은 기능을 다시 부여하면 수량/만료일을 갱신)
+type GrantEntitlementRequest struct {
+	FeatureID string `json:"feature_id" binding:"required"`
+	Quantity  *int   `json:"quantity"`
+	ExpiresAt string `json:"expires_at"` // YYYY-MM-DD 또는 RFC3339, 빈 값이면 만료 없음
+}
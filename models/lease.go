@@ -0,0 +1,45 @@
+package models
+
+// LicenseLease 플로팅(동시 사용) 라이선스의 좌석 임대 정보
+type LicenseLease struct {
+	ID                string  `json:"id" db:"id"`
+	LicenseID         string  `json:"license_id" db:"license_id"`
+	DeviceFingerprint string  `json:"device_fingerprint" db:"device_fingerprint"`
+	DeviceName        string  `json:"device_name" db:"device_name"`
+	Status            string  `json:"status" db:"status"` // active, released, expired
+	CheckedOutAt      string  `json:"checked_out_at" db:"checked_out_at"`
+	LastHeartbeatAt   string  `json:"last_heartbeat_at" db:"last_heartbeat_at"`
+	ExpiresAt         string  `json:"expires_at" db:"expires_at"`
+	ReleasedAt        *string `json:"released_at,omitempty" db:"released_at"`
+
+	// 유예 기간 중인 라이선스의 임대 응답에만 포함됩니다.
+	GraceEndsAt           *string `json:"grace_ends_at,omitempty" db:"-"`
+	GraceRemainingSeconds *int64  `json:"grace_remaining_seconds,omitempty" db:"-"`
+}
+
+// LeaseStatus 상태 상수
+const (
+	LeaseStatusActive   = "active"
+	LeaseStatusReleased = "released"
+	LeaseStatusExpired  = "expired"
+)
+
+// LeaseCheckoutRequest 좌석 임대 요청
+type LeaseCheckoutRequest struct {
+	LicenseKey string     `json:"license_key" binding:"required"`
+	DeviceInfo DeviceInfo `json:"device_info" binding:"required"`
+	TTLSeconds int        `json:"ttl_seconds"` // 선택사항, 서버 최대값으로 제한됨
+}
+
+// LeaseRenewRequest 임대 갱신(heartbeat) 요청
+type LeaseRenewRequest struct {
+	LicenseKey string `json:"license_key" binding:"required"`
+	LeaseID    string `json:"lease_id" binding:"required"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
+
+// LeaseReleaseRequest 임대 반납 요청
+type LeaseReleaseRequest struct {
+	LicenseKey string `json:"license_key" binding:"required"`
+	LeaseID    string `json:"lease_id" binding:"required"`
+}
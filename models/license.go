@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // License 라이선스 정보
 type License struct {
@@ -8,6 +11,9 @@ type License struct {
 	LicenseKey    string  `json:"license_key" db:"license_key"`
 	ProductID     *string `json:"product_id" db:"product_id"`
 	PolicyID      *string `json:"policy_id" db:"policy_id"`
+	LicenseType   string  `json:"license_type" db:"license_type"` // node_locked, floating
+	IsTrial       bool    `json:"is_trial" db:"is_trial"`
+	ConvertedAt   *string `json:"converted_at,omitempty" db:"converted_at"` // 체험판 → 정식 전환 시각
 	ProductName   string  `json:"product_name" db:"product_name"`
 	PolicyName    string  `json:"policy_name" db:"policy_name"`
 	CustomerName  string  `json:"customer_name" db:"customer_name"`
@@ -15,29 +21,81 @@ type License struct {
 	MaxDevices    int     `json:"max_devices" db:"max_devices"`
 	ActiveDevices int     `json:"active_devices" db:"active_devices"` // 활성 디바이스 수
 	ExpiresAt     string  `json:"expires_at" db:"expires_at"`
-	Status        string  `json:"status" db:"status"` // active, revoked, expired
-	CreatedBy     string  `json:"created_by" db:"created_by"`
-	Notes         string  `json:"notes" db:"notes"`
-	CreatedAt     string  `json:"created_at" db:"created_at"`
-	UpdatedAt     string  `json:"updated_at" db:"updated_at"`
+	Status        string  `json:"status" db:"status"` // scheduled, active, grace, suspended, revoked, expired
+	// GracePeriodDays, MaxOfflineDays nil이면 정책 데이터 또는 서버 기본값을 따릅니다.
+	GracePeriodDays *int   `json:"grace_period_days" db:"grace_period_days"`
+	MaxOfflineDays  *int   `json:"max_offline_days" db:"max_offline_days"`
+	CreatedBy       string `json:"created_by" db:"created_by"`
+	Notes           string `json:"notes" db:"notes"`
+	CreatedAt       string `json:"created_at" db:"created_at"`
+	UpdatedAt       string `json:"updated_at" db:"updated_at"`
+	// UsageQuotas 라이선스 단위 사용량 한도 (정책의 usage_quotas보다 우선, 상세 조회에만 포함)
+	UsageQuotas json.RawMessage `json:"usage_quotas,omitempty" db:"usage_quotas"`
+	// CustomerID 연결된 고객 ID (customer_name, customer_email은 고객 정보의 사본)
+	CustomerID *string `json:"customer_id" db:"customer_id"`
+	// 일시 정지 정보 (suspended 상태에서만 값이 있음, ResumeAt이 있으면 그날 스케줄러가 자동 재개)
+	SuspensionReason *string `json:"suspension_reason,omitempty" db:"suspension_reason"`
+	SuspendedAt      *string `json:"suspended_at,omitempty" db:"suspended_at"`
+	ResumeAt         *string `json:"resume_at,omitempty" db:"resume_at"`
+	// 폐기 정보 (revoked 상태에서만 값이 있음)
+	RevocationReason *string `json:"revocation_reason,omitempty" db:"revocation_reason"`
+	RevokedAt        *string `json:"revoked_at,omitempty" db:"revoked_at"`
+	// StartsAt 사용 시작 시각 (nil이면 발급 즉시 사용 가능, 이전에는 scheduled 상태)
+	StartsAt *string `json:"starts_at" db:"starts_at"`
+	// MaintenanceUntil 유지보수 종료일 (이날까지 출시된 제품 버전만 사용 가능, nil이면 버전 제한 없음)
+	MaintenanceUntil *string `json:"maintenance_until" db:"maintenance_until"`
+	// ReleaseChannel 업데이트를 받을 릴리스 채널 (nil이면 클라이언트가 요청한 stable/beta)
+	ReleaseChannel *string `json:"release_channel" db:"release_channel"`
+	// 결제 웹훅으로 발급된 라이선스의 결제 서비스, 주문, 구독 ID
+	PaymentProvider       *string `json:"payment_provider,omitempty" db:"payment_provider"`
+	PaymentOrderID        *string `json:"payment_order_id,omitempty" db:"payment_order_id"`
+	PaymentSubscriptionID *string `json:"payment_subscription_id,omitempty" db:"payment_subscription_id"`
 }
 
 // LicenseStatus 상태 상수
 const (
 	LicenseStatusActive  = "active"
+	LicenseStatusGrace   = "grace" // 만료일이 지났지만 유예 기간 내
 	LicenseStatusRevoked = "revoked"
 	LicenseStatusExpired = "expired"
+	// LicenseStatusSuspended 미납 등으로 일시 정지 (revoked와 달리 재개 가능)
+	LicenseStatusSuspended = "suspended"
+	// LicenseStatusScheduled 시작일(starts_at) 전의 선발급 라이선스
+	LicenseStatusScheduled = "scheduled"
+)
+
+// LicenseType 라이선스 유형 상수
+const (
+	LicenseTypeNodeLocked = "node_locked" // 디바이스 고정 (max_devices = 활성화 가능한 디바이스 수)
+	LicenseTypeFloating   = "floating"    // 동시 사용 (max_devices = 동시 임대 가능한 좌석 수)
 )
 
+// IsValidLicenseType 지원하는 라이선스 유형인지 확인
+func IsValidLicenseType(licenseType string) bool {
+	return licenseType == LicenseTypeNodeLocked || licenseType == LicenseTypeFloating
+}
+
 // CreateLicenseRequest 라이선스 생성 요청
 type CreateLicenseRequest struct {
 	ProductID     string `json:"product_id" binding:"required"` // 제품 ID
 	PolicyID      string `json:"policy_id"`                     // 정책 ID (선택사항)
+	LicenseType   string `json:"license_type"`                  // node_locked(기본값), floating
 	CustomerName  string `json:"customer_name" binding:"required"`
 	CustomerEmail string `json:"customer_email" binding:"required,email"`
 	MaxDevices    int    `json:"max_devices" binding:"required,min=1"`
 	ExpiresAt     string `json:"expires_at" binding:"required"`
 	Notes         string `json:"notes"`
+	// 유예 기간/오프라인 허용 일수 (선택사항, 생략하면 정책 또는 서버 기본값)
+	GracePeriodDays *int `json:"grace_period_days"`
+	MaxOfflineDays  *int `json:"max_offline_days"`
+	// 측정 항목별 사용량 한도 (선택사항, 생략하면 정책의 usage_quotas를 따름)
+	UsageQuotas map[string]UsageQuota `json:"usage_quotas"`
+	// CustomerID 기존 고객에 연결 (생략하면 customer_email로 고객을 찾고, 없으면 새로 만듭니다)
+	CustomerID string `json:"customer_id"`
+	// StartsAt 사용 시작 시각 (RFC3339 또는 YYYY-MM-DD, 생략하면 즉시 사용 가능)
+	StartsAt string `json:"starts_at"`
+	// MaintenanceUntil 유지보수 종료일 (YYYY-MM-DD, 생략하면 모든 버전 사용 가능)
+	MaintenanceUntil string `json:"maintenance_until"`
 }
 
 // UpdateLicenseRequest 라이선스 수정 요청
@@ -48,6 +106,81 @@ type UpdateLicenseRequest struct {
 	MaxDevices    int    `json:"max_devices"`
 	ExpiresAt     string `json:"expires_at"`
 	Notes         string `json:"notes"`
+	// 생략하면 기존 값 유지, 음수이면 설정을 지워 정책 또는 서버 기본값을 따름
+	GracePeriodDays *int `json:"grace_period_days"`
+	MaxOfflineDays  *int `json:"max_offline_days"`
+	// 생략하면 기존 값 유지, 빈 객체이면 라이선스 한도를 지워 정책을 따름
+	UsageQuotas map[string]UsageQuota `json:"usage_quotas"`
+	// CustomerID 생략하면 기존 연결 유지, 빈 문자열이면 연결 해제, 지정하면 해당 고객으로 연결하고 고객명/이메일을 고객 정보로 바꿉니다
+	CustomerID *string `json:"customer_id"`
+	// Reason, OrderRef 변경 이력에 함께 기록됩니다
+	Reason   string `json:"reason"`
+	OrderRef string `json:"order_ref"`
+	// StartsAt 생략하면 기존 값 유지, 빈 문자열이면 시작일 제거 (즉시 사용 가능)
+	StartsAt *string `json:"starts_at"`
+	// MaintenanceUntil 생략하면 기존 값 유지, 빈 문자열이면 유지보수 종료일 제거 (모든 버전 사용 가능)
+	MaintenanceUntil *string `json:"maintenance_until"`
+}
+
+// 제품 변경 시 초과 디바이스를 자동으로 고르는 기준
+const (
+	DeviceTrimLeastRecent = "least_recent" // 마지막 검증이 가장 오래된 디바이스부터 해제
+	DeviceTrimNewest      = "newest"       // 가장 최근에 활성화된 디바이스부터 해제
+)
+
+// ChangeLicenseProductRequest 라이선스 제품 변경(업그레이드/다운그레이드) 요청
+// 라이선스 키와 활성 디바이스는 유지되며, 새 max_devices를 넘는 디바이스만 해제합니다.
+type ChangeLicenseProductRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	// PolicyID 생략하면 기존 정책 유지, 빈 문자열이면 정책 해제
+	PolicyID *string `json:"policy_id"`
+	// MaxDevices 생략하면 기존 값 유지
+	MaxDevices *int `json:"max_devices"`
+	// DeactivateDeviceIDs 해제할 활성 디바이스 (초과분 이상이어야 함)
+	DeactivateDeviceIDs []string `json:"deactivate_device_ids"`
+	// TrimStrategy 해제할 디바이스를 지정하지 않았을 때 초과분을 고르는 기준 (least_recent, newest). 둘 다 없으면 초과 시 409
+	TrimStrategy string `json:"trim_strategy"`
+	Reason       string `json:"reason"`
+	OrderRef     string `json:"order_ref"`
+}
+
+// BulkCreateLicenseRequest 라이선스 일괄 발급 요청
+// customers를 지정하면 행마다 한 개씩, 생략하면 상위 고객 정보로 count개를 발급합니다.
+type BulkCreateLicenseRequest struct {
+	ProductID        string                `json:"product_id"`
+	PolicyID         string                `json:"policy_id"`
+	LicenseType      string                `json:"license_type"`
+	MaxDevices       int                   `json:"max_devices"` // 행에서 생략한 경우의 기본값
+	ExpiresAt        string                `json:"expires_at"`  // 행에서 생략한 경우의 기본값
+	Notes            string                `json:"notes"`
+	GracePeriodDays  *int                  `json:"grace_period_days"`
+	MaxOfflineDays   *int                  `json:"max_offline_days"`
+	UsageQuotas      map[string]UsageQuota `json:"usage_quotas"`
+	StartsAt         string                `json:"starts_at"`         // 모든 행에 공통으로 적용되는 사용 시작 시각
+	MaintenanceUntil string                `json:"maintenance_until"` // 모든 행에 공통으로 적용되는 유지보수 종료일
+	// Count customers 없이 같은 고객(또는 고객 미지정)으로 발급할 개수
+	Count         int              `json:"count"`
+	CustomerID    string           `json:"customer_id"`
+	CustomerName  string           `json:"customer_name"`
+	CustomerEmail string           `json:"customer_email"`
+	Customers     []BulkLicenseRow `json:"customers"`
+}
+
+// BulkLicenseRow 일괄 발급의 행 (CSV 헤더도 같은 이름을 사용)
+type BulkLicenseRow struct {
+	CustomerID    string `json:"customer_id"`
+	CustomerName  string `json:"customer_name"`
+	CustomerEmail string `json:"customer_email"`
+	MaxDevices    int    `json:"max_devices"`
+	ExpiresAt     string `json:"expires_at"`
+	Notes         string `json:"notes"`
+}
+
+// BulkLicenseRowError 일괄 발급 행별 검증 오류 (row는 1부터 시작, CSV는 헤더 다음 행이 1)
+type BulkLicenseRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
 }
 
 // DeactivateDeviceRequest 디바이스 비활성화 요청
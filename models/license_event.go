@@ -0,0 +1,59 @@
+package models
+
+// LicenseEvent 라이선스 변경 이력 (갱신, 연장, 수정, 자동 상태 전환 등)
+type LicenseEvent struct {
+	ID        int64                         `json:"id" db:"id"`
+	LicenseID string                        `json:"license_id" db:"license_id"`
+	EventType string                        `json:"event_type" db:"event_type"`
+	Changes   map[string]LicenseFieldChange `json:"changes" db:"changes"` // 필드별 이전/이후 값
+	Reason    string                        `json:"reason" db:"reason"`
+	OrderRef  *string                       `json:"order_ref" db:"order_ref"` // 외부 주문/결제 참조 번호
+	ActorID   string                        `json:"actor_id" db:"actor_id"`   // 변경한 관리자 ID (자동 처리는 system)
+	ActorName string                        `json:"actor_name" db:"actor_name"`
+	CreatedAt string                        `json:"created_at" db:"created_at"`
+}
+
+// LicenseFieldChange 필드 하나의 이전/이후 값
+type LicenseFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// LicenseEventType 상수
+const (
+	LicenseEventCreated        = "created"
+	LicenseEventUpdated        = "updated"
+	LicenseEventRenewed        = "renewed"  // 새 이용 기간 시작 (오늘 또는 기존 만료일 중 늦은 날 기준)
+	LicenseEventExtended       = "extended" // 기존 만료일 기준 기간 연장
+	LicenseEventConverted      = "converted"
+	LicenseEventStatusGrace    = "grace"   // 스케줄러가 유예 기간으로 전환
+	LicenseEventExpired        = "expired" // 스케줄러가 만료 처리
+	LicenseEventSuspended      = "suspended"
+	LicenseEventResumed        = "resumed" // 관리자 재개 또는 자동 재개일 도래
+	LicenseEventRevoked        = "revoked"
+	LicenseEventStarted        = "started"         // 시작일 도래로 scheduled → active
+	LicenseEventProductChanged = "product_changed" // 제품 업그레이드/다운그레이드
+)
+
+// RenewLicenseRequest 라이선스 갱신 요청
+// months, days, expires_at 중 하나를 지정합니다. months/days는 오늘과 기존 만료일 중 늦은 날부터 계산합니다.
+type RenewLicenseRequest struct {
+	Months    int    `json:"months"`
+	Days      int    `json:"days"`
+	ExpiresAt string `json:"expires_at"`
+	// MaxDevices 생략하면 기존 값 유지 (갱신 시 플랜 변경)
+	MaxDevices *int `json:"max_devices"`
+	// PolicyID 생략하면 기존 값 유지, 빈 문자열이면 정책 제거
+	PolicyID *string `json:"policy_id"`
+	Reason   string  `json:"reason"`
+	OrderRef string  `json:"order_ref"`
+}
+
+// ExtendLicenseRequest 라이선스 기간 연장 요청
+// days를 지정하면 기존 만료일에 더하고, expires_at을 지정하면 기존 만료일보다 뒤인 날짜로 바꿉니다.
+type ExtendLicenseRequest struct {
+	Days      int    `json:"days"`
+	ExpiresAt string `json:"expires_at"`
+	Reason    string `json:"reason"`
+	OrderRef  string `json:"order_ref"`
+}
@@ -0,0 +1,85 @@
+package models
+
+// 일시 정지 사유 코드 (검증 응답의 suspension_reason으로 그대로 전달됩니다)
+const (
+	SuspensionReasonNonPayment      = "non_payment"
+	SuspensionReasonChargeback      = "chargeback"
+	SuspensionReasonPolicyViolation = "policy_violation"
+	SuspensionReasonCustomerRequest = "customer_request"
+	SuspensionReasonOther           = "other"
+)
+
+// IsValidSuspensionReason 지원하는 일시 정지 사유 코드인지 확인
+func IsValidSuspensionReason(reason string) bool {
+	switch reason {
+	case SuspensionReasonNonPayment, SuspensionReasonChargeback, SuspensionReasonPolicyViolation,
+		SuspensionReasonCustomerRequest, SuspensionReasonOther:
+		return true
+	}
+	return false
+}
+
+// 폐기 사유 코드 (폐기 목록과 검증 응답의 revocation_reason으로 전달됩니다)
+const (
+	RevocationReasonKeyCompromise   = "key_compromise"
+	RevocationReasonRefund          = "refund"
+	RevocationReasonChargeback      = "chargeback"
+	RevocationReasonFraud           = "fraud"
+	RevocationReasonSuperseded      = "superseded"
+	RevocationReasonCustomerRequest = "customer_request"
+	RevocationReasonOther           = "other"
+)
+
+// IsValidRevocationReason 지원하는 폐기 사유 코드인지 확인
+func IsValidRevocationReason(reason string) bool {
+	switch reason {
+	case RevocationReasonKeyCompromise, RevocationReasonRefund, RevocationReasonChargeback, RevocationReasonFraud,
+		RevocationReasonSuperseded, RevocationReasonCustomerRequest, RevocationReasonOther:
+		return true
+	}
+	return false
+}
+
+// licenseStatusTransitions 라이선스 상태별로 전환 가능한 다음 상태
+// scheduled는 시작일이 되면 active가 되고, active는 시작일을 미래로 고치면 scheduled로 돌아갑니다.
+// revoked는 종료 상태이며, suspended는 active로만 되돌릴 수 있습니다
+// (재개 후 만료일이 지났으면 스케줄러가 유예/만료로 전환합니다).
+var licenseStatusTransitions = map[string][]string{
+	LicenseStatusScheduled: {LicenseStatusActive, LicenseStatusRevoked},
+	LicenseStatusActive:    {LicenseStatusGrace, LicenseStatusExpired, LicenseStatusSuspended, LicenseStatusRevoked, LicenseStatusScheduled},
+	LicenseStatusGrace:     {LicenseStatusActive, LicenseStatusExpired, LicenseStatusSuspended, LicenseStatusRevoked},
+	LicenseStatusExpired:   {LicenseStatusActive, LicenseStatusRevoked},
+	LicenseStatusSuspended: {LicenseStatusActive, LicenseStatusRevoked},
+	LicenseStatusRevoked:   {},
+}
+
+// CanTransitionLicenseStatus from 상태에서 to 상태로 전환할 수 있는지 확인
+func CanTransitionLicenseStatus(from, to string) bool {
+	for _, next := range licenseStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// SuspendLicenseRequest 라이선스 일시 정지 요청
+type SuspendLicenseRequest struct {
+	// Reason 사유 코드 (non_payment, chargeback, policy_violation, customer_request, other)
+	Reason string `json:"reason" binding:"required"`
+	// ResumeAt 자동 재개일 (YYYY-MM-DD, 생략하면 관리자가 재개할 때까지 유지)
+	ResumeAt string `json:"resume_at"`
+	Note     string `json:"note"`
+}
+
+// RevokeLicenseRequest 라이선스 폐기 요청 (되돌릴 수 없음)
+type RevokeLicenseRequest struct {
+	// Reason 사유 코드 (key_compromise, refund, chargeback, fraud, superseded, customer_request, other)
+	Reason  string `json:"reason" binding:"required"`
+	Comment string `json:"comment" binding:"required"`
+}
+
+// ResumeLicenseRequest 라이선스 재개 요청
+type ResumeLicenseRequest struct {
+	Note string `json:"note"`
+}
@@ -0,0 +1,56 @@
+package models
+
+// 결제 이벤트 유형 (결제 서비스별 이벤트는 어댑터가 이 유형으로 변환합니다)
+const (
+	PaymentEventOrderCreated         = "order.created"         // 신규 주문: 플랜 매핑에 따라 라이선스 발급
+	PaymentEventSubscriptionRenewed  = "subscription.renewed"  // 구독 갱신: 연결된 라이선스의 만료일 연장
+	PaymentEventOrderRefunded        = "order.refunded"        // 환불: 연결된 라이선스 폐기
+	PaymentEventSubscriptionCanceled = "subscription.canceled" // 구독 해지: 연결된 라이선스 일시 정지
+)
+
+// 결제 웹훅 처리 결과
+const (
+	PaymentEventResultProcessing = "processing"
+	PaymentEventResultProcessed  = "processed"
+	PaymentEventResultIgnored    = "ignored" // 지원하지 않는 이벤트 유형
+)
+
+// PaymentEvent 결제 서비스 웹훅을 공통 형식으로 변환한 이벤트
+type PaymentEvent struct {
+	ID             string `json:"id"`   // 결제 서비스의 이벤트 ID (중복 처리 방지 기준)
+	Type           string `json:"type"` // order.created, subscription.renewed, order.refunded, subscription.canceled
+	OrderID        string `json:"order_id"`
+	SubscriptionID string `json:"subscription_id"`
+	PlanCode       string `json:"plan"`
+	Quantity       int    `json:"quantity"`
+	CustomerName   string `json:"customer_name"`
+	CustomerEmail  string `json:"customer_email"`
+	// PeriodEnd 결제된 이용 기간의 마지막 날 (YYYY-MM-DD, 없으면 플랜의 duration_days로 계산)
+	PeriodEnd string `json:"period_end"`
+}
+
+// PaymentPlan 결제 서비스의 플랜 코드와 발급할 라이선스 조건의 매핑
+type PaymentPlan struct {
+	ID           string  `json:"id" db:"id"`
+	Provider     string  `json:"provider" db:"provider"`
+	PlanCode     string  `json:"plan_code" db:"plan_code"`
+	ProductID    string  `json:"product_id" db:"product_id"`
+	ProductName  string  `json:"product_name,omitempty" db:"product_name"`
+	PolicyID     *string `json:"policy_id" db:"policy_id"`
+	LicenseType  string  `json:"license_type" db:"license_type"`
+	MaxDevices   int     `json:"max_devices" db:"max_devices"`
+	DurationDays int     `json:"duration_days" db:"duration_days"` // 이벤트에 period_end가 없을 때 사용할 이용 기간
+	CreatedAt    string  `json:"created_at" db:"created_at"`
+	UpdatedAt    string  `json:"updated_at" db:"updated_at"`
+}
+
+// SavePaymentPlanRequest 플랜 매핑 생성/수정 요청 (provider, plan_code가 같으면 덮어씁니다)
+type SavePaymentPlanRequest struct {
+	Provider     string `json:"provider" binding:"required"`
+	PlanCode     string `json:"plan_code" binding:"required"`
+	ProductID    string `json:"product_id" binding:"required"`
+	PolicyID     string `json:"policy_id"`
+	LicenseType  string `json:"license_type"` // node_locked(기본값), floating
+	MaxDevices   int    `json:"max_devices"`
+	DurationDays int    `json:"duration_days" binding:"required"`
+}
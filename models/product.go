@@ -1,16 +1,34 @@
 package models
 
+import "studiolicense/licensekey"
+
 // Product 제품 정보
 type Product struct {
-	ID          string `json:"id" db:"id"`
-	Name        string `json:"name" db:"name"`
-	Description string `json:"description" db:"description"`
-	Status      string `json:"status" db:"status"` // active, inactive
-	CreatedBy   string `json:"created_by" db:"created_by"`
-	CreatedAt   string `json:"created_at" db:"created_at"`
-	UpdatedAt   string `json:"updated_at" db:"updated_at"`
+	ID                   string `json:"id" db:"id"`
+	Name                 string `json:"name" db:"name"`
+	Description          string `json:"description" db:"description"`
+	Status               string `json:"status" db:"status"`                               // active, inactive
+	FingerprintThreshold *int   `json:"fingerprint_threshold" db:"fingerprint_threshold"` // 디바이스 부분 일치 허용 점수 (nil이면 서버 기본값)
+	TrialDays            *int   `json:"trial_days" db:"trial_days"`                       // 셀프 체험판 기간 (nil이면 서버 기본값, 0이면 체험판 발급 안 함)
+	CreatedBy            string `json:"created_by" db:"created_by"`
+	CreatedAt            string `json:"created_at" db:"created_at"`
+	UpdatedAt            string `json:"updated_at" db:"updated_at"`
+
+	// RequestSigning 클라이언트 요청 서명 방식 (빈 값이면 서명 검사 안 함, hmac, ed25519)
+	RequestSigning  string  `json:"request_signing" db:"request_signing"`
+	ClientPublicKey *string `json:"client_public_key" db:"client_public_key"` // ed25519 방식의 클라이언트 공개키 (base64)
+	HasClientSecret bool    `json:"has_client_secret"`                        // hmac 비밀키 발급 여부 (비밀키 자체는 발급 시 한 번만 반환)
+
+	// KeyTemplate 라이선스 키 생성 규칙 (nil이면 기존 XXXX-XXXX-XXXX-XXXX 형식)
+	KeyTemplate *licensekey.Template `json:"key_template"`
 }
 
+// 클라이언트 요청 서명 방식 상수
+const (
+	RequestSigningHMAC    = "hmac"
+	RequestSigningEd25519 = "ed25519"
+)
+
 // ProductStatus 상태 상수
 const (
 	ProductStatusActive   = "active"
@@ -19,8 +37,15 @@ const (
 
 // CreateProductRequest 제품 생성 요청
 type CreateProductRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name                 string `json:"name" binding:"required"`
+	Description          string `json:"description"`
+	FingerprintThreshold *int   `json:"fingerprint_threshold"`
+	TrialDays            *int   `json:"trial_days"`
+	// RequestSigning off(기본값), hmac, ed25519
+	RequestSigning  *string `json:"request_signing"`
+	ClientPublicKey *string `json:"client_public_key"`
+	// KeyTemplate 생략하면 기존 키 형식 사용
+	KeyTemplate *licensekey.Template `json:"key_template"`
 }
 
 // UpdateProductRequest 제품 수정 요청
@@ -28,4 +53,14 @@ type UpdateProductRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Status      string `json:"status"`
+	// FingerprintThreshold 생략하면 기존 값 유지, 0이면 서버 기본값으로 초기화
+	FingerprintThreshold *int `json:"fingerprint_threshold"`
+	// TrialDays 생략하면 기존 값 유지, 음수이면 서버 기본값으로 초기화
+	TrialDays *int `json:"trial_days"`
+	// RequestSigning 생략하면 기존 값 유지, off이면 서명 검사 해제
+	RequestSigning *string `json:"request_signing"`
+	// ClientPublicKey 생략하면 기존 값 유지, 빈 문자열이면 삭제
+	ClientPublicKey *string `json:"client_public_key"`
+	// KeyTemplate 생략하면 기존 값 유지, length가 0인 템플릿({})이면 기존 키 형식으로 초기화. 이미 발급된 키에는 영향 없음
+	KeyTemplate *licensekey.Template `json:"key_template"`
 }
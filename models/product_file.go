@@ -1,29 +1,57 @@
 package models
 
+// Rollout states for a product file mapping.
+const (
+	RolloutStatusActive     = "active"      // delivered to rollout_percentage of devices
+	RolloutStatusPaused     = "paused"      // withheld from every device until resumed
+	RolloutStatusRolledBack = "rolled_back" // withheld, and devices already on this version are offered the previous release
+)
+
+// Rollout control actions.
+const (
+	RolloutActionSet      = "set"
+	RolloutActionPause    = "pause"
+	RolloutActionResume   = "resume"
+	RolloutActionRollback = "rollback"
+)
+
 // ProductFile describes the relationship between a product and a stored file asset.
 type ProductFile struct {
-	ID          string     `json:"id"`
-	ProductID   string     `json:"product_id"`
-	FileID      string     `json:"file_id"`
-	Label       string     `json:"label"`
-	Description string     `json:"description,omitempty"`
-	SortOrder   int        `json:"sort_order"`
-	IsActive    bool       `json:"is_active"`
-	DeliveryURL string     `json:"delivery_url,omitempty"`
-	File        *FileAsset `json:"file,omitempty"`
-	CreatedAt   string     `json:"created_at"`
-	UpdatedAt   string     `json:"updated_at"`
-}
-
-// AttachProductFileRequest represents the payload to link a file to a product.
-type AttachProductFileRequest struct {
+	ID          string `json:"id"`
 	ProductID   string `json:"product_id"`
 	FileID      string `json:"file_id"`
 	Label       string `json:"label"`
 	Description string `json:"description,omitempty"`
 	SortOrder   int    `json:"sort_order"`
+	IsActive    bool   `json:"is_active"`
 	DeliveryURL string `json:"delivery_url,omitempty"`
-	IsActive    *bool  `json:"is_active,omitempty"`
+	Version     string `json:"version,omitempty"` // release version this file belongs to (used by the update feed)
+	OS          string `json:"os,omitempty"`      // windows, macos, linux (empty = any)
+	Arch        string `json:"arch,omitempty"`    // x64, x86, arm64 (empty = any)
+	Channel     string `json:"channel"`           // lowest release channel that receives this file
+	// Staged rollout state; devices are bucketed by a stable hash of their device ID.
+	RolloutPercentage int        `json:"rollout_percentage"`
+	RolloutStatus     string     `json:"rollout_status"`
+	RolloutUpdatedAt  *string    `json:"rollout_updated_at,omitempty"`
+	File              *FileAsset `json:"file,omitempty"`
+	CreatedAt         string     `json:"created_at"`
+	UpdatedAt         string     `json:"updated_at"`
+}
+
+// AttachProductFileRequest represents the payload to link a file to a product.
+type AttachProductFileRequest struct {
+	ProductID         string `json:"product_id"`
+	FileID            string `json:"file_id"`
+	Label             string `json:"label"`
+	Description       string `json:"description,omitempty"`
+	SortOrder         int    `json:"sort_order"`
+	DeliveryURL       string `json:"delivery_url,omitempty"`
+	IsActive          *bool  `json:"is_active,omitempty"`
+	Version           string `json:"version,omitempty"`
+	OS                string `json:"os,omitempty"`
+	Arch              string `json:"arch,omitempty"`
+	Channel           string `json:"channel,omitempty"`            // stable (default), beta, internal
+	RolloutPercentage *int   `json:"rollout_percentage,omitempty"` // 0-100, default 100
 }
 
 // UpdateProductFileRequest represents the payload to update a product file mapping.
@@ -34,6 +62,18 @@ type UpdateProductFileRequest struct {
 	SortOrder   *int    `json:"sort_order,omitempty"`
 	DeliveryURL *string `json:"delivery_url,omitempty"`
 	IsActive    *bool   `json:"is_active,omitempty"`
+	// Version, OS, Arch: empty string clears the value
+	Version *string `json:"version,omitempty"`
+	OS      *string `json:"os,omitempty"`
+	Arch    *string `json:"arch,omitempty"`
+	Channel *string `json:"channel,omitempty"`
+}
+
+// UpdateProductFileRolloutRequest changes the staged rollout of a product file mapping.
+type UpdateProductFileRolloutRequest struct {
+	ID         string `json:"id"`
+	Action     string `json:"action"`               // set, pause, resume, rollback
+	Percentage *int   `json:"percentage,omitempty"` // required for set (0-100)
 }
 
 // ProductFileResponse is returned to clients during license validation.
@@ -50,5 +90,9 @@ type ProductFileResponse struct {
 	FileSize    int64  `json:"file_size"`
 	Checksum    string `json:"checksum,omitempty"`
 	StoragePath string `json:"storage_path"`
+	Version     string `json:"version,omitempty"`
+	OS          string `json:"os,omitempty"`
+	Arch        string `json:"arch,omitempty"`
+	Channel     string `json:"channel,omitempty"`
 	UpdatedAt   string `json:"updated_at"`
 }
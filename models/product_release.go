@@ -0,0 +1,112 @@
+package models
+
+// 릴리스 채널 상수
+const (
+	ReleaseChannelStable   = "stable"
+	ReleaseChannelBeta     = "beta"
+	ReleaseChannelInternal = "internal" // 사내 테스트용, 라이선스/디바이스에 지정된 경우에만 받음
+)
+
+// releaseChannelRank 채널이 포함하는 범위 (높을수록 더 많은 릴리스를 받음)
+var releaseChannelRank = map[string]int{
+	ReleaseChannelStable:   0,
+	ReleaseChannelBeta:     1,
+	ReleaseChannelInternal: 2,
+}
+
+// IsValidReleaseChannel 지원하는 릴리스 채널인지 확인
+func IsValidReleaseChannel(channel string) bool {
+	_, ok := releaseChannelRank[channel]
+	return ok
+}
+
+// ReleaseChannelIncludes 요청한 채널에서 받을 수 있는 릴리스인지 확인
+// internal 채널은 beta, stable 릴리스를, beta 채널은 stable 릴리스도 함께 받습니다.
+func ReleaseChannelIncludes(requested, releaseChannel string) bool {
+	requestedRank, ok := releaseChannelRank[requested]
+	if !ok {
+		requestedRank = releaseChannelRank[ReleaseChannelStable]
+	}
+	releaseRank, ok := releaseChannelRank[releaseChannel]
+	return ok && releaseRank <= requestedRank
+}
+
+// ProductRelease 제품의 출시 버전
+type ProductRelease struct {
+	ID          string `json:"id" db:"id"`
+	ProductID   string `json:"product_id" db:"product_id"`
+	Version     string `json:"version" db:"version"`           // 시맨틱 버전 (예: 2.3.0, 3.0.0-beta.1)
+	ReleaseDate string `json:"release_date" db:"release_date"` // YYYY-MM-DD, 라이선스의 maintenance_until과 비교
+	Channel     string `json:"channel" db:"channel"`           // stable, beta, internal
+	Mandatory   bool   `json:"mandatory" db:"mandatory"`       // 이전 버전 사용자는 반드시 업데이트해야 함
+	Notes       string `json:"notes" db:"notes"`
+	CreatedBy   string `json:"created_by" db:"created_by"`
+	CreatedAt   string `json:"created_at" db:"created_at"`
+}
+
+// CreateProductReleaseRequest 제품 릴리스 등록 요청
+type CreateProductReleaseRequest struct {
+	ProductID   string `json:"product_id" binding:"required"`
+	Version     string `json:"version" binding:"required"`
+	ReleaseDate string `json:"release_date" binding:"required"` // YYYY-MM-DD 또는 RFC3339
+	Channel     string `json:"channel"`                         // stable(기본값), beta, internal
+	Mandatory   bool   `json:"mandatory"`
+	Notes       string `json:"notes"`
+}
+
+// ReleaseEntitlement 라이선스가 사용할 수 있는 제품 버전 확인 결과
+// 유지보수 기간(maintenance_until)이 없으면 모든 릴리스를 사용할 수 있습니다.
+type ReleaseEntitlement struct {
+	AppVersion            string  `json:"app_version,omitempty"`
+	Entitled              bool    `json:"entitled"`
+	MaintenanceUntil      *string `json:"maintenance_until"`
+	LatestEntitledVersion *string `json:"latest_entitled_version"` // 사용할 수 있는 가장 최신 stable 버전
+	LatestVersion         *string `json:"latest_version"`          // 제품의 가장 최신 stable 버전
+}
+
+// UpdateCheckRequest 클라이언트 업데이트 확인 요청
+type UpdateCheckRequest struct {
+	LicenseKey     string     `json:"license_key" binding:"required"`
+	DeviceInfo     DeviceInfo `json:"device_info" binding:"required"`
+	ProductID      string     `json:"product_id"` // 선택사항, 보내면 라이선스의 제품과 일치해야 함
+	CurrentVersion string     `json:"current_version" binding:"required"`
+	OS             string     `json:"os" binding:"required"` // windows, macos, linux
+	Arch           string     `json:"arch"`                  // x64, x86, arm64 (생략하면 아키텍처 구분 없는 파일만)
+	Channel        string     `json:"channel"`               // stable(기본값), beta (라이선스/디바이스에 지정된 채널이 있으면 그 채널을 사용)
+}
+
+// UpdateRelease 업데이트 확인 응답의 설치할 릴리스
+type UpdateRelease struct {
+	Version     string                `json:"version"`
+	Channel     string                `json:"channel"`
+	ReleaseDate string                `json:"release_date"`
+	Notes       string                `json:"notes"`
+	Mandatory   bool                  `json:"mandatory"` // 현재 버전과 이 버전 사이에 필수 업데이트가 있으면 true
+	Files       []ProductFileResponse `json:"files"`
+}
+
+// UpdateCheckResult 업데이트 확인 결과
+type UpdateCheckResult struct {
+	UpdateAvailable  bool           `json:"update_available"`
+	CurrentVersion   string         `json:"current_version"`
+	Channel          string         `json:"channel"`
+	OS               string         `json:"os"`
+	Arch             string         `json:"arch,omitempty"`
+	Release          *UpdateRelease `json:"release"`
+	MaintenanceUntil *string        `json:"maintenance_until"`
+	// LatestVersion 유지보수 기간과 관계없이 이 채널/플랫폼의 가장 최신 버전 (Release보다 높으면 유지보수 갱신 필요)
+	LatestVersion *string `json:"latest_version"`
+	// Rollback 현재 버전의 배포가 롤백되어 이전 버전(Release)으로 되돌려야 하면 true
+	Rollback bool `json:"rollback"`
+}
+
+// SetReleaseChannelRequest 라이선스 릴리스 채널 지정 요청 (빈 문자열이면 지정 해제)
+type SetReleaseChannelRequest struct {
+	Channel string `json:"channel"`
+}
+
+// SetDeviceReleaseChannelRequest 디바이스 릴리스 채널 지정 요청 (빈 문자열이면 라이선스 채널을 따름)
+type SetDeviceReleaseChannelRequest struct {
+	DeviceID string `json:"device_id" binding:"required"`
+	Channel  string `json:"channel"`
+}
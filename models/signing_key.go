@@ -0,0 +1,17 @@
+package models
+
+// LicenseSigningKey 라이선스 인증서 서명 키 (공개 정보만 포함)
+type LicenseSigningKey struct {
+	ID        string  `json:"kid" db:"id"`
+	Algorithm string  `json:"alg" db:"algorithm"`
+	PublicKey string  `json:"public_key" db:"public_key"` // base64 인코딩된 Ed25519 공개키
+	Status    string  `json:"status" db:"status"`         // active, retired
+	CreatedAt string  `json:"created_at" db:"created_at"`
+	RetiredAt *string `json:"retired_at,omitempty" db:"retired_at"`
+}
+
+// SigningKeyStatus 상태 상수
+const (
+	SigningKeyStatusActive  = "active"
+	SigningKeyStatusRetired = "retired"
+)
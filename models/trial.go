@@ -0,0 +1,19 @@
+package models
+
+// TrialRequest 셀프 체험판 발급 요청
+type TrialRequest struct {
+	ProductID     string     `json:"product_id" binding:"required"`
+	CustomerName  string     `json:"customer_name"`
+	CustomerEmail string     `json:"customer_email" binding:"required,email"`
+	DeviceInfo    DeviceInfo `json:"device_info" binding:"required"`
+}
+
+// ConvertTrialRequest 체험판 → 정식 라이선스 전환 요청
+type ConvertTrialRequest struct {
+	ExpiresAt  string `json:"expires_at" binding:"required"`
+	MaxDevices int    `json:"max_devices"` // 0이면 기존 값 유지
+	PolicyID   string `json:"policy_id"`   // 빈 문자열이면 기존 정책 유지
+	Notes      string `json:"notes"`
+	Reason     string `json:"reason"`
+	OrderRef   string `json:"order_ref"` // 구매 주문 번호 (변경 이력에 기록)
+}
@@ -0,0 +1,45 @@
+package models
+
+// 사용량 집계 주기 상수
+const (
+	UsagePeriodDaily    = "daily"
+	UsagePeriodMonthly  = "monthly"
+	UsagePeriodLifetime = "lifetime"
+)
+
+// IsValidUsagePeriod 지원하는 집계 주기인지 확인
+func IsValidUsagePeriod(period string) bool {
+	return period == UsagePeriodDaily || period == UsagePeriodMonthly || period == UsagePeriodLifetime
+}
+
+// UsageQuota 측정 항목(metric)별 사용량 한도
+// 라이선스의 usage_quotas 또는 정책 데이터의 usage_quotas 키에 {"renders": {"limit": 100, "period": "monthly"}} 형태로 정의합니다.
+type UsageQuota struct {
+	Limit  int64  `json:"limit"`  // 라이선스에서 음수로 지정하면 정책 한도를 해제
+	Period string `json:"period"` // daily, monthly(기본값), lifetime
+}
+
+// UsageStatus 현재 기간의 측정 항목별 사용량
+type UsageStatus struct {
+	Metric    string  `json:"metric"`
+	Period    string  `json:"period"`
+	PeriodKey string  `json:"period_key"`
+	Used      int64   `json:"used"`
+	Limit     *int64  `json:"limit"`     // nil이면 한도 없음
+	Remaining *int64  `json:"remaining"` // nil이면 한도 없음
+	ResetsAt  *string `json:"resets_at"` // 다음 기간 시작 시각 (lifetime이면 nil)
+}
+
+// Exhausted 한도를 모두 사용했는지 확인
+func (s UsageStatus) Exhausted() bool {
+	return s.Remaining != nil && *s.Remaining <= 0
+}
+
+// UsageReportRequest 클라이언트 사용량 보고 요청
+type UsageReportRequest struct {
+	LicenseKey string     `json:"license_key" binding:"required"`
+	DeviceInfo DeviceInfo `json:"device_info" binding:"required"`
+	EventID    string     `json:"event_id" binding:"required"` // 클라이언트가 생성한 고유 ID (재전송 시 중복 집계 방지)
+	Metric     string     `json:"metric" binding:"required"`
+	Quantity   int64      `json:"quantity"` // 0 이하이면 1로 간주
+}
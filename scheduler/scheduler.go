@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"database/sql"
 	"fmt"
 	"studiolicense/database"
 	"studiolicense/logger"
@@ -17,6 +18,8 @@ func StartScheduler() {
 	ticker := time.NewTicker(1 * time.Hour)
 
 	// 서버 시작 시 즉시 한 번 실행
+	StartScheduledLicenses()
+	ResumeSuspendedLicenses()
 	UpdateExpiredLicenses()
 
 	// 고루틴으로 주기적 실행
@@ -24,104 +27,285 @@ func StartScheduler() {
 		for {
 			<-ticker.C
 			logger.Info("Scheduler tick: Running UpdateExpiredLicenses")
+			StartScheduledLicenses()
+			ResumeSuspendedLicenses()
 			UpdateExpiredLicenses()
 		}
 	}()
+
+	// 플로팅 라이선스 임대는 TTL이 짧으므로 1분마다 정리
+	leaseTicker := time.NewTicker(1 * time.Minute)
+	go func() {
+		for {
+			<-leaseTicker.C
+			ExpireFloatingLeases()
+		}
+	}()
+}
+
+// ExpireFloatingLeases heartbeat가 끊겨 만료 시각이 지난 임대를 expired로 전환
+func ExpireFloatingLeases() {
+	nowStr := utils.FormatDateTimeForDB(utils.NowSeoul())
+
+	result, err := database.DB.Exec(
+		"UPDATE license_leases SET status = ? WHERE status = ? AND expires_at < ?",
+		models.LeaseStatusExpired, models.LeaseStatusActive, nowStr,
+	)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"error": err.Error(),
+		}).Error("Failed to expire floating leases")
+		return
+	}
+
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		logger.Info("Expired %d floating leases", affected)
+	}
 }
 
 // UpdateExpiredLicenses 만료된 라이선스 상태 업데이트
+// 유예 기간이 설정된 라이선스는 active → grace → expired 순으로 전환합니다.
 func UpdateExpiredLicenses() {
 	logger.Info("Running scheduled task: UpdateExpiredLicenses")
 
-	now := time.Now()
-	nowStr := now.Format("2006-01-02 15:04:05")
+	now := utils.NowSeoul()
+	nowStr := utils.FormatDateTimeForDB(now)
+	today := utils.FormatDateOnly(now)
 
-	// 먼저 만료 대상 확인
+	// 만료일 당일까지는 사용 가능하므로 만료일이 오늘 이전인 라이선스만 대상입니다.
 	checkQuery := `
-		SELECT id, license_key, expires_at 
+		SELECT id, license_key, policy_id, expires_at, status, grace_period_days, max_offline_days
 		FROM licenses 
-		WHERE status = ? 
+		WHERE status IN (?, ?) 
 		AND expires_at < ? 
 		AND expires_at IS NOT NULL
-		LIMIT 10
 	`
 
-	rows, err := database.DB.Query(checkQuery, models.LicenseStatusActive, nowStr)
+	rows, err := database.DB.Query(checkQuery, models.LicenseStatusActive, models.LicenseStatusGrace, today)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"error": err.Error(),
 		}).Error("Failed to check expired licenses")
 		return
 	}
-	defer rows.Close()
 
-	count := 0
+	transitions := map[string]string{}
+	previous := map[string]string{}
+	lifecycles := utils.NewLicenseLifecycleCache()
 	for rows.Next() {
-		var (
-			id        string
-			key       string
-			expiresAt string
-		)
-		if scanErr := rows.Scan(&id, &key, &expiresAt); scanErr != nil {
+		var license models.License
+		if scanErr := rows.Scan(&license.ID, &license.LicenseKey, &license.PolicyID, &license.ExpiresAt,
+			&license.Status, &license.GracePeriodDays, &license.MaxOfflineDays); scanErr != nil {
 			logger.WithFields(map[string]interface{}{
 				"error": scanErr.Error(),
 			}).Warn("Failed to scan expired license row")
 			continue
 		}
+
+		next := models.LicenseStatusExpired
+		lifecycle := lifecycles.Resolve(license.GracePeriodDays, license.MaxOfflineDays, license.PolicyID)
+		if graceEndsAt, err := lifecycle.GraceEndsAt(license.ExpiresAt); err == nil &&
+			lifecycle.GracePeriodDays > 0 && now.Before(graceEndsAt) {
+			next = models.LicenseStatusGrace
+		}
+		if next == license.Status {
+			continue
+		}
+
 		logger.WithFields(map[string]interface{}{
-			"id":         id,
-			"key":        key,
-			"expires_at": expiresAt,
-			"now":        nowStr,
+			"id":         license.ID,
+			"key":        license.LicenseKey,
+			"expires_at": license.ExpiresAt,
+			"from":       license.Status,
+			"to":         next,
 		}).Info("Found expired license")
-		count++
+		transitions[license.ID] = next
+		previous[license.ID] = license.Status
 	}
 	if err = rows.Err(); err != nil {
 		logger.WithFields(map[string]interface{}{
 			"error": err.Error(),
 		}).Warn("Row iteration error while scanning expired licenses")
 	}
+	rows.Close()
+
+	counts := map[string]int{}
+	for id, next := range transitions {
+		// 조회 이후 관리자가 상태나 만료일을 바꿨을 수 있으므로 조회한 상태와 만료일을 조건으로 전환합니다.
+		err := utils.TransitionLicenseStatus(database.DB, id, utils.LicenseTransition{
+			From:          previous[id],
+			To:            next,
+			ExpiredBefore: today,
+		})
+		if err == utils.ErrLicenseStatusChanged {
+			continue
+		}
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"license_id": id,
+				"error":      err.Error(),
+			}).Error("Failed to update expired license")
+			continue
+		}
+		counts[next]++
+		recordStatusTransition(id, previous[id], next, "")
+	}
+
 	logger.WithFields(map[string]interface{}{
-		"count": count,
-	}).Info("Expired licenses to update")
-
-	// 업데이트 실행
-	query := `
-		UPDATE licenses 
-		SET status = ?, updated_at = ? 
-		WHERE status = ? 
-		AND expires_at < ? 
-		AND expires_at IS NOT NULL
-	`
+		"grace":   counts[models.LicenseStatusGrace],
+		"expired": counts[models.LicenseStatusExpired],
+		"now":     nowStr,
+	}).Info("Expired licenses updated")
+
+	// 상태가 바뀐 라이선스가 있으면 활동 로그 기록
+	if counts[models.LicenseStatusGrace] > 0 {
+		details := fmt.Sprintf("자동으로 %d개의 라이선스가 유예 기간으로 전환되었습니다.", counts[models.LicenseStatusGrace])
+		utils.LogAdminActivity("system", "System", "라이선스 유예 전환", details)
+	}
+	if counts[models.LicenseStatusExpired] > 0 {
+		details := fmt.Sprintf("자동으로 %d개의 라이선스가 만료 처리되었습니다.", counts[models.LicenseStatusExpired])
+		utils.LogAdminActivity("system", "System", "라이선스 만료 처리", details)
+	}
+}
+
+// StartScheduledLicenses 시작 시각(starts_at)이 된 scheduled 라이선스를 active로 전환합니다.
+// 클라이언트 요청 시에도 바로 전환되므로, 이미 전환된 라이선스는 건너뜁니다.
+func StartScheduledLicenses() {
+	now := utils.NowSeoul()
+	nowStr := utils.FormatDateTimeForDB(now)
 
-	result, err := database.DB.Exec(query,
-		models.LicenseStatusExpired,
-		nowStr,
-		models.LicenseStatusActive,
-		nowStr,
+	rows, err := database.DB.Query(
+		"SELECT id, starts_at FROM licenses WHERE status = ? AND starts_at IS NOT NULL AND starts_at <= ?",
+		models.LicenseStatusScheduled, nowStr,
 	)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"error": err.Error(),
+		}).Error("Failed to check scheduled licenses")
+		return
+	}
+
+	type scheduledLicense struct {
+		id, startsAt string
+	}
+	due := []scheduledLicense{}
+	for rows.Next() {
+		var license scheduledLicense
+		if err := rows.Scan(&license.id, &license.startsAt); err != nil {
+			continue
+		}
+		due = append(due, license)
+	}
+	rows.Close()
+
+	started := 0
+	for _, license := range due {
+		ok, err := utils.StartScheduledLicense(database.DB, license.id)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"license_id": license.id,
+				"error":      err.Error(),
+			}).Error("Failed to start scheduled license")
+			continue
+		}
+		if !ok {
+			continue
+		}
+		started++
+		logger.WithFields(map[string]interface{}{
+			"id":        license.id,
+			"starts_at": license.startsAt,
+		}).Info("Scheduled license started")
+	}
+
+	if started > 0 {
+		logger.Info("Started %d scheduled licenses", started)
+		details := fmt.Sprintf("시작일이 되어 %d개의 라이선스가 활성화되었습니다.", started)
+		utils.LogAdminActivity("system", "System", "라이선스 사용 시작", details)
+	}
+}
+
+// ResumeSuspendedLicenses 자동 재개일이 된 일시 정지 라이선스를 다시 활성화합니다.
+// 재개 후 만료일이 지난 라이선스는 이어서 실행되는 UpdateExpiredLicenses가 유예/만료로 전환합니다.
+func ResumeSuspendedLicenses() {
+	today := utils.FormatDateOnly(utils.NowSeoul())
 
+	rows, err := database.DB.Query(
+		"SELECT id, suspension_reason FROM licenses WHERE status = ? AND resume_at IS NOT NULL AND resume_at <= ?",
+		models.LicenseStatusSuspended, today,
+	)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"error": err.Error(),
-		}).Error("Failed to update expired licenses")
+		}).Error("Failed to check suspended licenses")
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
+	reasons := map[string]string{}
+	for rows.Next() {
+		var (
+			id     string
+			reason sql.NullString
+		)
+		if err := rows.Scan(&id, &reason); err != nil {
+			continue
+		}
+		reasons[id] = reason.String
+	}
+	rows.Close()
 
-	logger.WithFields(map[string]interface{}{
-		"count": rowsAffected,
-		"now":   nowStr,
-	}).Info("Expired licenses updated")
+	resumed := 0
+	for id, reason := range reasons {
+		err := utils.TransitionLicenseStatus(database.DB, id, utils.LicenseTransition{
+			From: models.LicenseStatusSuspended,
+			To:   models.LicenseStatusActive,
+		})
+		if err == utils.ErrLicenseStatusChanged {
+			continue
+		}
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"license_id": id,
+				"error":      err.Error(),
+			}).Error("Failed to resume suspended license")
+			continue
+		}
+		resumed++
+		recordStatusTransition(id, models.LicenseStatusSuspended, models.LicenseStatusActive, reason)
+	}
 
-	// 만료된 라이선스가 있으면 활동 로그 기록
-	if rowsAffected > 0 {
-		details := fmt.Sprintf("자동으로 %d개의 라이선스가 만료 처리되었습니다.", rowsAffected)
-		utils.LogAdminActivity("system", "System", "라이선스 만료 처리", details)
+	if resumed > 0 {
+		logger.Info("Resumed %d suspended licenses", resumed)
+		details := fmt.Sprintf("자동 재개일이 되어 %d개의 라이선스가 재개되었습니다.", resumed)
+		utils.LogAdminActivity("system", "System", models.AdminActionResumeLicense, details)
+	}
+}
 
+// recordStatusTransition 스케줄러의 유예/만료 전환과 자동 재개를 라이선스 변경 이력에 기록합니다.
+// suspensionReason은 자동 재개 시 해제된 일시 정지 사유입니다.
+func recordStatusTransition(licenseID, from, to, suspensionReason string) {
+	eventType := models.LicenseEventExpired
+	changes := map[string]models.LicenseFieldChange{
+		"status": {Old: from, New: to},
+	}
+	switch {
+	case from == models.LicenseStatusSuspended:
+		eventType = models.LicenseEventResumed
+		changes["suspension_reason"] = models.LicenseFieldChange{Old: suspensionReason, New: nil}
+	case to == models.LicenseStatusGrace:
+		eventType = models.LicenseEventStatusGrace
+	}
+	err := utils.RecordLicenseEvent(database.DB, models.LicenseEvent{
+		LicenseID: licenseID,
+		EventType: eventType,
+		Changes:   changes,
+		ActorID:   "system",
+		ActorName: "System",
+	})
+	if err != nil {
 		logger.WithFields(map[string]interface{}{
-			"count": rowsAffected,
-		}).Info("Admin activity logged for expired licenses")
+			"license_id": licenseID,
+			"error":      err.Error(),
+		}).Warn("Failed to record license status transition")
 	}
 }
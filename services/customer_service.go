@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+var (
+	// ErrCustomerNotFound는 고객이 존재하지 않을 때 반환됩니다.
+	ErrCustomerNotFound = errors.New("customer not found")
+	// ErrCustomerConflict는 같은 이메일 또는 외부 ID의 고객이 이미 존재할 때 반환됩니다.
+	ErrCustomerConflict = errors.New("customer email or external id already exists")
+	// ErrCustomerLinkedLicenses는 연결된 라이선스로 인해 삭제가 제한될 때 반환됩니다.
+	ErrCustomerLinkedLicenses = errors.New("customer has linked licenses")
+	// ErrInvalidCustomer는 고객 이름이나 이메일이 비어 있을 때 반환됩니다.
+	ErrInvalidCustomer = errors.New("customer name and a valid email are required")
+)
+
+// CustomerFilter는 고객 조회 시 필요한 필터 정보를 담습니다.
+type CustomerFilter struct {
+	Search  string
+	Scope   models.AdminResourcePermissionConfig
+	IsSuper bool
+	AdminID string
+}
+
+// CustomerService는 고객 도메인에 대한 비즈니스 로직을 정의합니다.
+type CustomerService interface {
+	Create(ctx context.Context, req models.CreateCustomerRequest, creatorID string) (models.Customer, error)
+	List(ctx context.Context, filter CustomerFilter) ([]models.Customer, error)
+	Get(ctx context.Context, id string) (models.Customer, error)
+	Update(ctx context.Context, id string, req models.UpdateCustomerRequest) error
+	Delete(ctx context.Context, id string) error
+	// ResolveForLicense는 라이선스에 연결할 고객을 찾습니다. customerID가 비어 있으면 이메일로 찾고, 없으면 새로 만듭니다.
+	// customerID와 이메일이 모두 비어 있으면 빈 고객(연결 안 함)을 반환합니다.
+	ResolveForLicense(ctx context.Context, customerID, name, email, creatorID string) (models.Customer, error)
+}
+
+type customerService struct {
+	db SQLExecutor
+}
+
+// NewCustomerService는 CustomerService 구현체를 생성합니다.
+func NewCustomerService(db SQLExecutor) CustomerService {
+	return &customerService{db: db}
+}
+
+const customerSelectColumns = `SELECT c.id, c.name, COALESCE(c.organization, ''), c.email, COALESCE(c.phone, ''),
+	c.external_id, c.contacts, COALESCE(c.notes, ''),
+	(SELECT COUNT(*) FROM licenses l WHERE l.customer_id = c.id) AS license_count,
+	c.created_by, c.created_at, c.updated_at
+	FROM customers c`
+
+func (s *customerService) Create(ctx context.Context, req models.CreateCustomerRequest, creatorID string) (models.Customer, error) {
+	name := strings.TrimSpace(req.Name)
+	email := normalizeCustomerEmail(req.Email)
+	if name == "" || !strings.Contains(email, "@") {
+		return models.Customer{}, ErrInvalidCustomer
+	}
+
+	contacts, err := encodeCustomerContacts(req.Contacts)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	id, err := utils.GenerateID("cust")
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	externalID := strings.TrimSpace(req.ExternalID)
+	now := time.Now().Format("2006-01-02 15:04:05")
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO customers (id, name, organization, email, phone, external_id, contacts, notes, created_by, created_at, updated_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?, ?, ?)`,
+		id, name, strings.TrimSpace(req.Organization), email, strings.TrimSpace(req.Phone), externalID,
+		contacts, req.Notes, creatorID, now, now,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return models.Customer{}, ErrCustomerConflict
+		}
+		return models.Customer{}, err
+	}
+
+	customer := models.Customer{
+		ID:           id,
+		Name:         name,
+		Organization: strings.TrimSpace(req.Organization),
+		Email:        email,
+		Phone:        strings.TrimSpace(req.Phone),
+		Contacts:     decodeCustomerContacts(sql.NullString{String: contacts, Valid: true}),
+		Notes:        req.Notes,
+		CreatedBy:    creatorID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if externalID != "" {
+		customer.ExternalID = &externalID
+	}
+	return customer, nil
+}
+
+func (s *customerService) List(ctx context.Context, filter CustomerFilter) ([]models.Customer, error) {
+	query := customerSelectColumns + " WHERE 1=1"
+	args := make([]any, 0)
+
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		query += " AND (c.name LIKE ? OR c.organization LIKE ? OR c.email LIKE ? OR c.external_id = ?)"
+		pattern := "%" + search + "%"
+		args = append(args, pattern, pattern, pattern, search)
+	}
+
+	if !filter.IsSuper {
+		sqlFragment, fragmentArgs := utils.BuildResourceFilter(filter.Scope, "c.id", "c.created_by", filter.AdminID)
+		query += sqlFragment
+		args = append(args, fragmentArgs...)
+	}
+
+	query += " ORDER BY c.organization IS NULL, c.organization, c.name"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := make([]models.Customer, 0)
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+
+	return customers, rows.Err()
+}
+
+func (s *customerService) Get(ctx context.Context, id string) (models.Customer, error) {
+	customer, err := scanCustomer(s.db.QueryRowContext(ctx, customerSelectColumns+" WHERE c.id = ?", id))
+	if err == sql.ErrNoRows {
+		return models.Customer{}, ErrCustomerNotFound
+	}
+	return customer, err
+}
+
+func (s *customerService) Update(ctx context.Context, id string, req models.UpdateCustomerRequest) error {
+	name := strings.TrimSpace(req.Name)
+	email := normalizeCustomerEmail(req.Email)
+	if name == "" || !strings.Contains(email, "@") {
+		return ErrInvalidCustomer
+	}
+
+	var contacts *string
+	if req.Contacts != nil {
+		encoded, err := encodeCustomerContacts(req.Contacts)
+		if err != nil {
+			return err
+		}
+		contacts = &encoded
+	}
+	var externalID *string
+	if req.ExternalID != nil {
+		value := strings.TrimSpace(*req.ExternalID)
+		externalID = &value
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	result, err := tx.ExecContext(ctx, `
+		UPDATE customers
+		SET name = ?, organization = NULLIF(?, ''), email = ?, phone = NULLIF(?, ''),
+			external_id = CASE WHEN ? IS NULL THEN external_id ELSE NULLIF(?, '') END,
+			contacts = CASE WHEN ? IS NULL THEN contacts ELSE ? END,
+			notes = ?, updated_at = ?
+		WHERE id = ?`,
+		name, strings.TrimSpace(req.Organization), email, strings.TrimSpace(req.Phone),
+		externalID, externalID, contacts, contacts, req.Notes, now, id,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrCustomerConflict
+		}
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		// MySQL은 값이 바뀌지 않은 행을 0으로 세므로 존재 여부를 다시 확인합니다.
+		var exists int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM customers WHERE id = ?", id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrCustomerNotFound
+		}
+	}
+
+	// 라이선스의 고객명/이메일은 목록 검색과 클라이언트 응답에 쓰이는 사본이므로 함께 맞춥니다.
+	if _, err := tx.ExecContext(ctx, "UPDATE licenses SET customer_name = ?, customer_email = ?, updated_at = ? WHERE customer_id = ?",
+		name, email, now, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *customerService) Delete(ctx context.Context, id string) error {
+	var count int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM licenses WHERE customer_id = ?", id).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCustomerLinkedLicenses
+	}
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM customers WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrCustomerNotFound
+	}
+	return err
+}
+
+func (s *customerService) ResolveForLicense(ctx context.Context, customerID, name, email, creatorID string) (models.Customer, error) {
+	if customerID = strings.TrimSpace(customerID); customerID != "" {
+		return s.Get(ctx, customerID)
+	}
+
+	email = normalizeCustomerEmail(email)
+	if email == "" {
+		return models.Customer{}, nil
+	}
+
+	customer, err := s.getByEmail(ctx, email)
+	if err != ErrCustomerNotFound {
+		return customer, err
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = email
+	}
+	customer, err = s.Create(ctx, models.CreateCustomerRequest{Name: name, Email: email}, creatorID)
+	if errors.Is(err, ErrCustomerConflict) {
+		// 동시에 같은 이메일로 생성된 경우 먼저 만들어진 고객을 사용합니다.
+		return s.getByEmail(ctx, email)
+	}
+	return customer, err
+}
+
+func (s *customerService) getByEmail(ctx context.Context, email string) (models.Customer, error) {
+	customer, err := scanCustomer(s.db.QueryRowContext(ctx, customerSelectColumns+" WHERE c.email = ?", email))
+	if err == sql.ErrNoRows {
+		return models.Customer{}, ErrCustomerNotFound
+	}
+	return customer, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCustomer(row rowScanner) (models.Customer, error) {
+	var (
+		customer  models.Customer
+		contacts  sql.NullString
+		createdBy sql.NullString
+	)
+	if err := row.Scan(&customer.ID, &customer.Name, &customer.Organization, &customer.Email, &customer.Phone,
+		&customer.ExternalID, &contacts, &customer.Notes, &customer.LicenseCount,
+		&createdBy, &customer.CreatedAt, &customer.UpdatedAt); err != nil {
+		return models.Customer{}, err
+	}
+	customer.Contacts = decodeCustomerContacts(contacts)
+	if createdBy.Valid {
+		customer.CreatedBy = createdBy.String
+	}
+	return customer, nil
+}
+
+// normalizeCustomerEmail은 고객 이메일을 비교할 수 있도록 소문자로 바꿉니다. 기존 라이선스 이전도 같은 규칙을 사용합니다.
+func normalizeCustomerEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func encodeCustomerContacts(contacts []models.CustomerContact) (string, error) {
+	if contacts == nil {
+		contacts = []models.CustomerContact{}
+	}
+	encoded, err := json.Marshal(contacts)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func decodeCustomerContacts(raw sql.NullString) []models.CustomerContact {
+	contacts := make([]models.CustomerContact, 0)
+	if raw.Valid && strings.TrimSpace(raw.String) != "" {
+		json.Unmarshal([]byte(raw.String), &contacts)
+	}
+	return contacts
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrFileObjectNotFound는 저장소에 해당 키의 객체가 없을 때 반환됩니다.
+	ErrFileObjectNotFound = errors.New("file object not found")
+	// ErrPresignNotSupported는 서명된 직접 다운로드 URL을 만들 수 없는 저장소에서 반환됩니다.
+	ErrPresignNotSupported = errors.New("presigned URLs are not supported by this storage")
+)
+
+// FileStorage는 업로드한 파일 객체를 보관하는 저장소입니다.
+// key는 files.storage_path 값(예: 2026/10/file_xxx.zip)이며 저장소가 바뀌어도 그대로 유지됩니다.
+// 단일 서버는 로컬 디스크 구현을, 여러 서버가 같은 파일을 제공해야 하면 S3 호환 구현을 사용합니다.
+type FileStorage interface {
+	// Name은 설정 값과 로그에 쓰는 저장소 이름(filesystem, s3)입니다.
+	Name() string
+	// Put은 body를 key에 저장합니다. size를 모르면 -1을 전달합니다.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get은 객체 내용을 엽니다. 호출한 쪽에서 반드시 닫아야 하며, 로컬 디스크 구현은 io.ReadSeeker도 구현합니다.
+	Get(ctx context.Context, key string) (io.ReadCloser, FileObjectInfo, error)
+	// Stat은 객체의 크기와 수정 시각을 조회합니다.
+	Stat(ctx context.Context, key string) (FileObjectInfo, error)
+	// Delete는 객체를 삭제합니다. 이미 없으면 오류로 보지 않습니다.
+	Delete(ctx context.Context, key string) error
+	// PresignGet은 expires 동안 인증 없이 내려받을 수 있는 URL을 만듭니다. filename이 있으면 그 이름으로 저장되도록 지정합니다.
+	PresignGet(ctx context.Context, key string, expires time.Duration, filename string) (string, error)
+}
+
+// FileObjectInfo는 저장된 객체의 메타데이터입니다.
+type FileObjectInfo struct {
+	Size        int64
+	ModTime     time.Time
+	ContentType string
+}
+
+// FileStorageConfig는 파일 저장소 설정입니다.
+type FileStorageConfig struct {
+	Backend string // filesystem(기본값), s3
+	BaseDir string // filesystem 저장 경로
+	S3      S3Config
+}
+
+// FileStorageConfigFromEnv는 환경 변수에서 파일 저장소 설정을 읽습니다.
+//   - FILE_STORAGE: filesystem(기본값) 또는 s3
+//   - FILE_STORAGE_DIR: filesystem 저장 경로 (기본값 data/files)
+//   - S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY, S3_PREFIX
+//   - S3_FORCE_PATH_STYLE: 기본값 true (MinIO 등 자체 호스팅 저장소 호환)
+func FileStorageConfigFromEnv() FileStorageConfig {
+	pathStyle := true
+	if v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("S3_FORCE_PATH_STYLE"))); err == nil {
+		pathStyle = v
+	}
+	return FileStorageConfig{
+		Backend: os.Getenv("FILE_STORAGE"),
+		BaseDir: strings.TrimSpace(os.Getenv("FILE_STORAGE_DIR")),
+		S3: S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			Prefix:          os.Getenv("S3_PREFIX"),
+			PathStyle:       pathStyle,
+		},
+	}
+}
+
+// NewFileStorage는 설정 값(filesystem, s3)에 맞는 FileStorage를 생성합니다. 알 수 없는 값이면 오류를 반환합니다.
+func NewFileStorage(cfg FileStorageConfig) (FileStorage, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Backend)) {
+	case "", "filesystem", "fs", "local":
+		return NewFileSystemStorage(cfg.BaseDir), nil
+	case "s3", "minio":
+		return NewS3FileStorage(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown file storage backend %q", cfg.Backend)
+	}
+}
+
+// cleanFileStorageKey는 key를 저장소 루트 아래의 상대 경로로 정규화합니다. 루트를 벗어나는 경로는 거부합니다.
+func cleanFileStorageKey(key string) (string, error) {
+	raw := strings.ReplaceAll(strings.TrimSpace(key), "\\", "/")
+	for _, segment := range strings.Split(raw, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	cleaned := strings.TrimPrefix(path.Clean("/"+raw), "/")
+	if cleaned == "" {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultFileStorageDir 로컬 디스크 저장소의 기본 경로
+var DefaultFileStorageDir = filepath.Join("data", "files")
+
+type fileSystemStorage struct {
+	baseDir string
+}
+
+// NewFileSystemStorage는 baseDir 아래에 파일을 보관하는 FileStorage를 생성합니다. baseDir이 비어 있으면 data/files를 사용합니다.
+func NewFileSystemStorage(baseDir string) FileStorage {
+	if baseDir == "" {
+		baseDir = DefaultFileStorageDir
+	}
+	return &fileSystemStorage{baseDir: baseDir}
+}
+
+func (s *fileSystemStorage) Name() string {
+	return "filesystem"
+}
+
+func (s *fileSystemStorage) fullPath(key string) (string, error) {
+	cleaned, err := cleanFileStorageKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.baseDir, filepath.FromSlash(cleaned)), nil
+}
+
+// Put은 같은 디렉터리의 임시 파일에 먼저 쓴 뒤 이름을 바꿔, 중간에 실패해도 기존 파일이 깨지지 않게 합니다.
+func (s *fileSystemStorage) Put(_ context.Context, key string, body io.Reader, size int64, _ string) error {
+	fullPath, err := s.fullPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size >= 0 && written != size {
+		err = fmt.Errorf("short write for %s: wrote %d of %d bytes", key, written, size)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+func (s *fileSystemStorage) Get(_ context.Context, key string) (io.ReadCloser, FileObjectInfo, error) {
+	fullPath, err := s.fullPath(key)
+	if err != nil {
+		return nil, FileObjectInfo{}, err
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, FileObjectInfo{}, ErrFileObjectNotFound
+		}
+		return nil, FileObjectInfo{}, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, FileObjectInfo{}, err
+	}
+	return f, FileObjectInfo{Size: stat.Size(), ModTime: stat.ModTime()}, nil
+}
+
+func (s *fileSystemStorage) Stat(_ context.Context, key string) (FileObjectInfo, error) {
+	fullPath, err := s.fullPath(key)
+	if err != nil {
+		return FileObjectInfo{}, err
+	}
+	stat, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return FileObjectInfo{}, ErrFileObjectNotFound
+		}
+		return FileObjectInfo{}, err
+	}
+	return FileObjectInfo{Size: stat.Size(), ModTime: stat.ModTime()}, nil
+}
+
+func (s *fileSystemStorage) Delete(_ context.Context, key string) error {
+	fullPath, err := s.fullPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// PresignGet 로컬 디스크 파일은 서버가 직접 전송하므로 직접 다운로드 URL을 지원하지 않습니다.
+func (s *fileSystemStorage) PresignGet(context.Context, string, time.Duration, string) (string, error) {
+	return "", ErrPresignNotSupported
+}
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config는 S3 호환 저장소(AWS S3, MinIO, Cloudflare R2 등) 연결 설정입니다.
+type S3Config struct {
+	Endpoint        string // 예: https://s3.ap-northeast-2.amazonaws.com, http://localhost:9000 (스킴이 없으면 https)
+	Region          string // 서명에 쓰는 리전 (기본값 us-east-1, MinIO는 보통 기본값 그대로)
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string // 모든 객체 키 앞에 붙일 경로 (예: studiolicense/files)
+	PathStyle       bool   // true이면 endpoint/bucket/key, false이면 bucket.endpoint/key 형식으로 요청
+}
+
+// s3UnsignedPayload 본문 해시 대신 쓰는 값. 업로드 본문을 두 번 읽지 않도록 본문은 서명하지 않고 TLS와 체크섬 검증에 맡깁니다.
+const s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3MaxPresignExpiry 서명 URL 최대 유효 기간 (SigV4 제한)
+const s3MaxPresignExpiry = 7 * 24 * time.Hour
+
+type s3FileStorage struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3FileStorage는 S3 호환 API(AWS Signature V4)로 객체를 보관하는 FileStorage를 생성합니다.
+func NewS3FileStorage(cfg S3Config) (FileStorage, error) {
+	cfg.Endpoint = strings.TrimSpace(cfg.Endpoint)
+	cfg.Bucket = strings.TrimSpace(cfg.Bucket)
+	cfg.Prefix = strings.Trim(strings.TrimSpace(cfg.Prefix), "/")
+	if cfg.Region = strings.TrimSpace(cfg.Region); cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 storage requires endpoint and bucket")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("s3 storage requires access key and secret key")
+	}
+	if !strings.Contains(cfg.Endpoint, "://") {
+		cfg.Endpoint = "https://" + cfg.Endpoint
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	endpoint.Path = strings.TrimRight(endpoint.Path, "/")
+
+	return &s3FileStorage{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Transport: newS3Transport()},
+		now:      time.Now,
+	}, nil
+}
+
+// newS3Transport 연결과 응답 헤더 대기에만 시간 제한을 둡니다.
+// http.Client.Timeout은 본문을 읽는 시간까지 포함해 큰 설치 파일 다운로드나 이전 도중 전송을 끊으므로 쓰지 않고,
+// 본문 전송은 호출한 쪽의 context(요청 취소 등)로 중단합니다.
+func newS3Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.ResponseHeaderTimeout = time.Minute
+	transport.ExpectContinueTimeout = time.Second
+	return transport
+}
+
+func (s *s3FileStorage) Name() string {
+	return "s3"
+}
+
+// objectURL key에 해당하는 객체 URL. Path는 디코딩된 값, RawPath는 서명에 쓰는 인코딩된 값입니다.
+func (s *s3FileStorage) objectURL(key string) (*url.URL, error) {
+	cleaned, err := cleanFileStorageKey(key)
+	if err != nil {
+		return nil, err
+	}
+	objectKey := cleaned
+	if s.cfg.Prefix != "" {
+		objectKey = s.cfg.Prefix + "/" + cleaned
+	}
+
+	u := *s.endpoint
+	objectPath := "/" + objectKey
+	if s.cfg.PathStyle {
+		objectPath = "/" + s.cfg.Bucket + objectPath
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.Path = s.endpoint.Path + objectPath
+	u.RawPath = s3URIEncode(s.endpoint.Path, false) + s3URIEncode(objectPath, false)
+	u.RawQuery = ""
+	return &u, nil
+}
+
+func (s *s3FileStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+	// S3는 길이를 알 수 없는 단일 PUT을 받지 않으므로 크기를 모르면 메모리에 모아서 보냅니다.
+	if size < 0 {
+		buf, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		body, size = bytes.NewReader(buf), int64(len(buf))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), io.NopCloser(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3ResponseError(req, resp)
+	}
+	return nil
+}
+
+func (s *s3FileStorage) Get(ctx context.Context, key string) (io.ReadCloser, FileObjectInfo, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, FileObjectInfo{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, FileObjectInfo{}, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, FileObjectInfo{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, FileObjectInfo{}, ErrFileObjectNotFound
+		}
+		return nil, FileObjectInfo{}, s3ResponseError(req, resp)
+	}
+	return resp.Body, s3ObjectInfo(resp), nil
+}
+
+func (s *s3FileStorage) Stat(ctx context.Context, key string) (FileObjectInfo, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return FileObjectInfo{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return FileObjectInfo{}, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return FileObjectInfo{}, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return s3ObjectInfo(resp), nil
+	case http.StatusNotFound:
+		return FileObjectInfo{}, ErrFileObjectNotFound
+	}
+	return FileObjectInfo{}, s3ResponseError(req, resp)
+}
+
+func (s *s3FileStorage) Delete(ctx context.Context, key string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+	return s3ResponseError(req, resp)
+}
+
+// PresignGet은 쿼리 문자열 서명(SigV4) 방식의 GET URL을 만듭니다. 서버 키는 URL에 포함되지 않습니다.
+func (s *s3FileStorage) PresignGet(_ context.Context, key string, expires time.Duration, filename string) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	if expires <= 0 || expires > s3MaxPresignExpiry {
+		return "", fmt.Errorf("presign expiry must be between 1s and %s", s3MaxPresignExpiry)
+	}
+
+	amzDate, scope := s.credentialScope(s.now())
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.cfg.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if filename != "" {
+		query.Set("response-content-disposition",
+			fmt.Sprintf("attachment; filename=\"%s\"; filename*=UTF-8''%s", strings.ReplaceAll(filename, "\"", ""), url.PathEscape(filename)))
+	}
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		s3CanonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+	query.Set("X-Amz-Signature", s.signature(amzDate, scope, canonicalRequest))
+	u.RawQuery = s3CanonicalQuery(query)
+	return u.String(), nil
+}
+
+// do는 요청에 AWS Signature V4 헤더 서명을 붙여 전송합니다.
+func (s *s3FileStorage) do(req *http.Request) (*http.Response, error) {
+	amzDate, scope := s.credentialScope(s.now())
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3CanonicalQuery(req.URL.Query()),
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + s3UnsignedPayload + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, s.signature(amzDate, scope, canonicalRequest)))
+
+	return s.client.Do(req)
+}
+
+func (s *s3FileStorage) credentialScope(now time.Time) (string, string) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	return amzDate, amzDate[:8] + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+func (s *s3FileStorage) signature(amzDate, scope, canonicalRequest string) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := s3HMAC([]byte("AWS4"+s.cfg.SecretAccessKey), amzDate[:8])
+	key = s3HMAC(key, s.cfg.Region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3URIEncode는 SigV4 규칙대로 비예약 문자(A-Z a-z 0-9 - _ . ~)를 제외하고 퍼센트 인코딩합니다.
+func s3URIEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery는 키 순서로 정렬하고 SigV4 규칙으로 인코딩한 쿼리 문자열을 만듭니다.
+func s3CanonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		vals := append([]string(nil), values[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, s3URIEncode(k, true)+"="+s3URIEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func s3ObjectInfo(resp *http.Response) FileObjectInfo {
+	info := FileObjectInfo{
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+	return info
+}
+
+// s3ResponseError는 S3 오류 응답(XML)의 Code, Message를 포함한 오류를 만듭니다.
+func s3ResponseError(req *http.Request, resp *http.Response) error {
+	var payload struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err := xml.Unmarshal(body, &payload); err == nil && payload.Code != "" {
+		return fmt.Errorf("s3 %s %s: %s (%s: %s)", req.Method, req.URL.Path, resp.Status, payload.Code, payload.Message)
+	}
+	return fmt.Errorf("s3 %s %s: %s", req.Method, req.URL.Path, resp.Status)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NonceStore는 요청 서명의 nonce를 기록해 같은 요청이 다시 전송되는 것을 막습니다.
+// 단일 서버는 메모리 구현을, 여러 서버가 같은 DB를 쓰는 클러스터는 MySQL 구현을 사용합니다.
+type NonceStore interface {
+	// Remember는 nonce를 만료 시각까지 기록합니다. 이미 기록된 nonce이면 false를 반환합니다.
+	Remember(ctx context.Context, key string, expiresAt time.Time) (bool, error)
+}
+
+// nonceSweepInterval 만료된 nonce를 정리하는 최소 간격
+const nonceSweepInterval = time.Minute
+
+type memoryNonceStore struct {
+	mu        sync.Mutex
+	entries   map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryNonceStore는 프로세스 메모리에 nonce를 보관하는 NonceStore를 생성합니다.
+func NewMemoryNonceStore() NonceStore {
+	return &memoryNonceStore{entries: make(map[string]time.Time)}
+}
+
+func (s *memoryNonceStore) Remember(_ context.Context, key string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= nonceSweepInterval {
+		for k, exp := range s.entries {
+			if !exp.After(now) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if exp, ok := s.entries[key]; ok && exp.After(now) {
+		return false, nil
+	}
+	s.entries[key] = expiresAt
+	return true, nil
+}
+
+type sqlNonceStore struct {
+	db        SQLExecutor
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewSQLNonceStore는 request_nonces 테이블에 nonce를 보관하는 NonceStore를 생성합니다.
+func NewSQLNonceStore(db SQLExecutor) NonceStore {
+	return &sqlNonceStore{db: db}
+}
+
+func (s *sqlNonceStore) Remember(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	now := time.Now()
+	s.sweep(ctx, now)
+
+	// 만료된 같은 키가 남아 있으면 덮어쓰고, 유효한 키가 있으면 영향받은 행이 없습니다.
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO request_nonces (nonce_key, expires_at) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE expires_at = IF(expires_at <= ?, VALUES(expires_at), expires_at)`,
+		key, expiresAt.Format("2006-01-02 15:04:05"), now.Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (s *sqlNonceStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < nonceSweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	_, _ = s.db.ExecContext(ctx, "DELETE FROM request_nonces WHERE expires_at <= ?", now.Format("2006-01-02 15:04:05"))
+}
+
+// NewNonceStore는 설정 값(memory, mysql)에 맞는 NonceStore를 생성합니다. 알 수 없는 값이면 메모리 구현을 사용합니다.
+func NewNonceStore(kind string, db SQLExecutor) NonceStore {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "mysql", "sql", "db":
+		return NewSQLNonceStore(db)
+	default:
+		return NewMemoryNonceStore()
+	}
+}
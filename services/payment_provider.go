@@ -0,0 +1,171 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"studiolicense/models"
+)
+
+// generic 결제 웹훅 서명 헤더
+const (
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp" // 유닉스 시간(초)
+	HeaderWebhookSignature = "X-Webhook-Signature" // hex(HMAC-SHA256(secret, timestamp + "." + body))
+)
+
+var (
+	// ErrWebhookSignatureInvalid는 웹훅 서명이 없거나 일치하지 않을 때 반환됩니다.
+	ErrWebhookSignatureInvalid = errors.New("invalid webhook signature")
+	// ErrWebhookExpired는 웹훅 시각이 허용 범위를 벗어났을 때 반환됩니다.
+	ErrWebhookExpired = errors.New("webhook timestamp outside allowed window")
+	// ErrWebhookPayloadInvalid는 웹훅 본문을 결제 이벤트로 해석할 수 없을 때 반환됩니다.
+	ErrWebhookPayloadInvalid = errors.New("invalid webhook payload")
+)
+
+// PaymentProvider는 결제 서비스별 웹훅 서명 방식과 본문 형식을 공통 결제 이벤트로 바꾸는 어댑터입니다.
+type PaymentProvider interface {
+	// Name은 웹훅 경로(/api/webhooks/payments/{name})와 플랜 매핑에 쓰는 이름입니다.
+	Name() string
+	// VerifyWebhook은 요청 헤더의 서명이 본문과 일치하는지 확인합니다.
+	VerifyWebhook(header http.Header, body []byte) error
+	// ParseEvent는 본문을 공통 결제 이벤트로 변환합니다. 지원하지 않는 유형은 그대로 두면 처리 단계에서 무시됩니다.
+	ParseEvent(body []byte) (models.PaymentEvent, error)
+}
+
+// PaymentProviderRegistry는 설정된 결제 어댑터를 이름으로 찾습니다.
+type PaymentProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]PaymentProvider
+}
+
+// NewPaymentProviderRegistry는 결제 어댑터 목록을 생성합니다.
+func NewPaymentProviderRegistry(providers ...PaymentProvider) *PaymentProviderRegistry {
+	registry := &PaymentProviderRegistry{providers: make(map[string]PaymentProvider)}
+	for _, provider := range providers {
+		registry.Register(provider)
+	}
+	return registry
+}
+
+// Register는 결제 어댑터를 추가합니다. 같은 이름이 있으면 교체합니다.
+func (r *PaymentProviderRegistry) Register(provider PaymentProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[strings.ToLower(provider.Name())] = provider
+}
+
+// Get은 이름으로 결제 어댑터를 찾습니다.
+func (r *PaymentProviderRegistry) Get(name string) (PaymentProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[strings.ToLower(strings.TrimSpace(name))]
+	return provider, ok
+}
+
+// Names는 등록된 결제 어댑터 이름을 정렬해 반환합니다.
+func (r *PaymentProviderRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PaymentProviderGeneric 범용 JSON 결제 어댑터 이름
+const PaymentProviderGeneric = "generic"
+
+// genericPaymentProvider는 이 서버가 정의한 JSON 형식을 그대로 받는 어댑터입니다.
+// 자체 결제 시스템이나 로컬 테스트용 결제 서버를 연동할 때 사용합니다.
+type genericPaymentProvider struct {
+	secret    []byte
+	tolerance time.Duration
+}
+
+// genericPaymentPayload generic 어댑터의 웹훅 본문
+type genericPaymentPayload struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		OrderID        string `json:"order_id"`
+		SubscriptionID string `json:"subscription_id"`
+		Plan           string `json:"plan"`
+		Quantity       int    `json:"quantity"`
+		PeriodEnd      string `json:"period_end"`
+		Customer       struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"customer"`
+	} `json:"data"`
+}
+
+// NewGenericPaymentProvider는 공유 비밀값으로 HMAC 서명을 검증하는 범용 JSON 어댑터를 생성합니다.
+// tolerance는 허용하는 웹훅 시각 오차입니다.
+func NewGenericPaymentProvider(secret string, tolerance time.Duration) PaymentProvider {
+	if tolerance <= 0 {
+		tolerance = 5 * time.Minute
+	}
+	return &genericPaymentProvider{secret: []byte(secret), tolerance: tolerance}
+}
+
+func (p *genericPaymentProvider) Name() string {
+	return PaymentProviderGeneric
+}
+
+func (p *genericPaymentProvider) VerifyWebhook(header http.Header, body []byte) error {
+	timestamp := strings.TrimSpace(header.Get(HeaderWebhookTimestamp))
+	signature := strings.TrimPrefix(strings.TrimSpace(header.Get(HeaderWebhookSignature)), "sha256=")
+	if len(p.secret) == 0 || timestamp == "" || signature == "" {
+		return ErrWebhookSignatureInvalid
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookExpired
+	}
+	if skew := time.Since(time.Unix(seconds, 0)); skew > p.tolerance || skew < -p.tolerance {
+		return ErrWebhookExpired
+	}
+
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	provided, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(provided, mac.Sum(nil)) {
+		return ErrWebhookSignatureInvalid
+	}
+	return nil
+}
+
+func (p *genericPaymentProvider) ParseEvent(body []byte) (models.PaymentEvent, error) {
+	var payload genericPaymentPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return models.PaymentEvent{}, ErrWebhookPayloadInvalid
+	}
+	if strings.TrimSpace(payload.ID) == "" || strings.TrimSpace(payload.Type) == "" {
+		return models.PaymentEvent{}, ErrWebhookPayloadInvalid
+	}
+
+	return models.PaymentEvent{
+		ID:             strings.TrimSpace(payload.ID),
+		Type:           strings.TrimSpace(payload.Type),
+		OrderID:        strings.TrimSpace(payload.Data.OrderID),
+		SubscriptionID: strings.TrimSpace(payload.Data.SubscriptionID),
+		PlanCode:       strings.TrimSpace(payload.Data.Plan),
+		Quantity:       payload.Data.Quantity,
+		CustomerName:   strings.TrimSpace(payload.Data.Customer.Name),
+		CustomerEmail:  strings.TrimSpace(payload.Data.Customer.Email),
+		PeriodEnd:      strings.TrimSpace(payload.Data.PeriodEnd),
+	}, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"studiolicense/models"
+)
+
+const testWebhookSecret = "whsec_test"
+
+func signGenericWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func genericWebhookHeader(timestamp, signature string) http.Header {
+	header := http.Header{}
+	if timestamp != "" {
+		header.Set(HeaderWebhookTimestamp, timestamp)
+	}
+	if signature != "" {
+		header.Set(HeaderWebhookSignature, signature)
+	}
+	return header
+}
+
+func TestGenericPaymentProviderVerifyWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1","type":"order.created","data":{"order_id":"ord_1","plan":"pro"}}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name   string
+		secret string
+		header http.Header
+		body   []byte
+		want   error
+	}{
+		{"valid", testWebhookSecret, genericWebhookHeader(now, signGenericWebhook(testWebhookSecret, now, body)), body, nil},
+		{"sha256 prefix", testWebhookSecret, genericWebhookHeader(now, "sha256="+signGenericWebhook(testWebhookSecret, now, body)), body, nil},
+		{"wrong secret", testWebhookSecret, genericWebhookHeader(now, signGenericWebhook("other", now, body)), body, ErrWebhookSignatureInvalid},
+		{"tampered body", testWebhookSecret, genericWebhookHeader(now, signGenericWebhook(testWebhookSecret, now, body)),
+			[]byte(`{"id":"evt_1","type":"order.created","data":{"order_id":"ord_1","plan":"enterprise"}}`), ErrWebhookSignatureInvalid},
+		{"signature for another timestamp", testWebhookSecret, genericWebhookHeader(now, signGenericWebhook(testWebhookSecret, stale, body)), body, ErrWebhookSignatureInvalid},
+		{"non-hex signature", testWebhookSecret, genericWebhookHeader(now, "not-hex"), body, ErrWebhookSignatureInvalid},
+		{"missing signature", testWebhookSecret, genericWebhookHeader(now, ""), body, ErrWebhookSignatureInvalid},
+		{"missing timestamp", testWebhookSecret, genericWebhookHeader("", signGenericWebhook(testWebhookSecret, now, body)), body, ErrWebhookSignatureInvalid},
+		{"no secret configured", "", genericWebhookHeader(now, signGenericWebhook("", now, body)), body, ErrWebhookSignatureInvalid},
+		{"stale timestamp", testWebhookSecret, genericWebhookHeader(stale, signGenericWebhook(testWebhookSecret, stale, body)), body, ErrWebhookExpired},
+		{"future timestamp", testWebhookSecret, genericWebhookHeader(future, signGenericWebhook(testWebhookSecret, future, body)), body, ErrWebhookExpired},
+		{"non-numeric timestamp", testWebhookSecret, genericWebhookHeader("yesterday", signGenericWebhook(testWebhookSecret, "yesterday", body)), body, ErrWebhookExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewGenericPaymentProvider(tt.secret, 5*time.Minute)
+			if err := provider.VerifyWebhook(tt.header, tt.body); !errors.Is(err, tt.want) {
+				t.Fatalf("VerifyWebhook error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenericPaymentProviderParseEventLifecycle(t *testing.T) {
+	provider := NewGenericPaymentProvider(testWebhookSecret, 0)
+
+	// 주문 → 구독 갱신 → 환불 순서로 같은 주문/구독에 연결된 이벤트를 받습니다.
+	tests := []struct {
+		body string
+		want models.PaymentEvent
+	}{
+		{
+			`{"id":"evt_1","type":"order.created","data":{"order_id":"ord_1","subscription_id":"sub_1","plan":"pro",
+				"quantity":2,"customer":{"name":" Kim ","email":"kim@example.com"}}}`,
+			models.PaymentEvent{ID: "evt_1", Type: models.PaymentEventOrderCreated, OrderID: "ord_1", SubscriptionID: "sub_1",
+				PlanCode: "pro", Quantity: 2, CustomerName: "Kim", CustomerEmail: "kim@example.com"},
+		},
+		{
+			`{"id":"evt_2","type":"subscription.renewed","data":{"subscription_id":"sub_1","period_end":"2027-01-31"}}`,
+			models.PaymentEvent{ID: "evt_2", Type: models.PaymentEventSubscriptionRenewed, SubscriptionID: "sub_1", PeriodEnd: "2027-01-31"},
+		},
+		{
+			`{"id":"evt_3","type":"order.refunded","data":{"order_id":"ord_1"}}`,
+			models.PaymentEvent{ID: "evt_3", Type: models.PaymentEventOrderRefunded, OrderID: "ord_1"},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := provider.ParseEvent([]byte(tt.body))
+		if err != nil {
+			t.Fatalf("ParseEvent(%s): %v", tt.want.Type, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseEvent(%s) = %+v, want %+v", tt.want.Type, got, tt.want)
+		}
+	}
+}
+
+func TestGenericPaymentProviderParseEventDuplicateDelivery(t *testing.T) {
+	provider := NewGenericPaymentProvider(testWebhookSecret, 0)
+
+	// 재전송된 이벤트는 같은 ID로 해석되어야 웹훅 처리에서 한 번만 적용됩니다.
+	first, err := provider.ParseEvent([]byte(`{"id":"evt_9","type":"order.refunded","data":{"order_id":"ord_1"}}`))
+	if err != nil {
+		t.Fatalf("ParseEvent: %v", err)
+	}
+	again, err := provider.ParseEvent([]byte(`{"id":" evt_9 ","type":"order.refunded","data":{"order_id":"ord_1"}}`))
+	if err != nil {
+		t.Fatalf("ParseEvent: %v", err)
+	}
+	if first.ID != again.ID {
+		t.Fatalf("redelivered event ID = %q, want %q", again.ID, first.ID)
+	}
+}
+
+func TestGenericPaymentProviderParseEventInvalid(t *testing.T) {
+	provider := NewGenericPaymentProvider(testWebhookSecret, 0)
+
+	for _, body := range []string{
+		`not json`,
+		`{"type":"order.created"}`,
+		`{"id":"evt_1"}`,
+		`{"id":"  ","type":"order.created"}`,
+	} {
+		if _, err := provider.ParseEvent([]byte(body)); !errors.Is(err, ErrWebhookPayloadInvalid) {
+			t.Errorf("ParseEvent(%s) error = %v, want ErrWebhookPayloadInvalid", body, err)
+		}
+	}
+}
+
+func TestPaymentProviderRegistry(t *testing.T) {
+	registry := NewPaymentProviderRegistry(NewGenericPaymentProvider(testWebhookSecret, 0))
+
+	if _, ok := registry.Get(" Generic "); !ok {
+		t.Fatal("Get should match provider names case-insensitively")
+	}
+	if _, ok := registry.Get("stripe"); ok {
+		t.Fatal("Get returned an unregistered provider")
+	}
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{PaymentProviderGeneric}) {
+		t.Fatalf("Names = %v", names)
+	}
+}
@@ -2,11 +2,16 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"strings"
 	"time"
 
+	"studiolicense/licensecert"
+	"studiolicense/licensekey"
 	"studiolicense/models"
 	"studiolicense/utils"
 )
@@ -18,6 +23,10 @@ var (
 	ErrProductNotFound = errors.New("product not found")
 	// ErrProductLinkedLicenses는 연결된 라이선스로 인해 삭제가 제한될 때 반환됩니다.
 	ErrProductLinkedLicenses = errors.New("product has linked licenses")
+	// ErrInvalidRequestSigning는 요청 서명 방식이나 클라이언트 공개키가 올바르지 않을 때 반환됩니다.
+	ErrInvalidRequestSigning = errors.New("invalid request signing configuration")
+	// ErrInvalidKeyTemplate는 라이선스 키 템플릿 설정이 올바르지 않을 때 반환됩니다.
+	ErrInvalidKeyTemplate = licensekey.ErrInvalidTemplate
 )
 
 // ProductFilter는 제품 조회 시 필요한 필터 정보를 담습니다.
@@ -35,6 +44,7 @@ type ProductService interface {
 	Get(ctx context.Context, id string) (models.Product, error)
 	Update(ctx context.Context, id string, req models.UpdateProductRequest) error
 	Delete(ctx context.Context, id string) error
+	RotateClientSecret(ctx context.Context, id string) (string, error)
 }
 
 type productService struct {
@@ -47,6 +57,16 @@ func NewProductService(db SQLExecutor) ProductService {
 }
 
 func (s *productService) Create(ctx context.Context, req models.CreateProductRequest, creatorID string) (models.Product, error) {
+	signing, publicKey, err := normalizeRequestSigning(req.RequestSigning, req.ClientPublicKey)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	keyTemplate, err := encodeKeyTemplate(req.KeyTemplate)
+	if err != nil {
+		return models.Product{}, err
+	}
+
 	id, err := utils.GenerateID("prod")
 	if err != nil {
 		return models.Product{}, err
@@ -54,9 +74,11 @@ func (s *productService) Create(ctx context.Context, req models.CreateProductReq
 
 	now := time.Now().Format("2006-01-02 15:04:05")
 	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO products (id, name, description, status, created_by, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		id, req.Name, req.Description, models.ProductStatusActive, creatorID, now, now,
+		INSERT INTO products (id, name, description, status, fingerprint_threshold, trial_days,
+			request_signing, client_public_key, key_template, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)`,
+		id, req.Name, req.Description, models.ProductStatusActive, req.FingerprintThreshold, req.TrialDays,
+		stringOrEmpty(signing), stringOrEmpty(publicKey), stringOrEmpty(keyTemplate), creatorID, now, now,
 	)
 	if err != nil {
 		if isDuplicateKeyError(err) {
@@ -66,18 +88,25 @@ func (s *productService) Create(ctx context.Context, req models.CreateProductReq
 	}
 
 	return models.Product{
-		ID:          id,
-		Name:        req.Name,
-		Description: req.Description,
-		Status:      models.ProductStatusActive,
-		CreatedBy:   creatorID,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:                   id,
+		Name:                 req.Name,
+		Description:          req.Description,
+		Status:               models.ProductStatusActive,
+		FingerprintThreshold: req.FingerprintThreshold,
+		TrialDays:            req.TrialDays,
+		CreatedBy:            creatorID,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+		RequestSigning:       stringOrEmpty(signing),
+		ClientPublicKey:      emptyToNil(publicKey),
+		KeyTemplate:          decodeKeyTemplate(keyTemplate),
 	}, nil
 }
 
 func (s *productService) List(ctx context.Context, filter ProductFilter) ([]models.Product, error) {
-	query := `SELECT id, name, description, status, created_by, created_at, updated_at FROM products WHERE 1=1`
+	query := `SELECT id, name, description, status, fingerprint_threshold, trial_days,
+		COALESCE(request_signing, ''), client_public_key, client_secret IS NOT NULL, key_template,
+		created_by, created_at, updated_at FROM products WHERE 1=1`
 	args := make([]any, 0)
 
 	if strings.TrimSpace(filter.Status) != "" {
@@ -102,12 +131,20 @@ func (s *productService) List(ctx context.Context, filter ProductFilter) ([]mode
 	products := make([]models.Product, 0)
 	for rows.Next() {
 		var (
-			product   models.Product
-			createdBy sql.NullString
+			product     models.Product
+			createdBy   sql.NullString
+			threshold   sql.NullInt64
+			trialDays   sql.NullInt64
+			keyTemplate sql.NullString
 		)
-		if err := rows.Scan(&product.ID, &product.Name, &product.Description, &product.Status, &createdBy, &product.CreatedAt, &product.UpdatedAt); err != nil {
+		if err := rows.Scan(&product.ID, &product.Name, &product.Description, &product.Status, &threshold, &trialDays,
+			&product.RequestSigning, &product.ClientPublicKey, &product.HasClientSecret, &keyTemplate,
+			&createdBy, &product.CreatedAt, &product.UpdatedAt); err != nil {
 			return nil, err
 		}
+		product.FingerprintThreshold = nullIntPtr(threshold)
+		product.TrialDays = nullIntPtr(trialDays)
+		product.KeyTemplate = decodeKeyTemplate(&keyTemplate.String)
 		if createdBy.Valid {
 			product.CreatedBy = createdBy.String
 		}
@@ -119,15 +156,22 @@ func (s *productService) List(ctx context.Context, filter ProductFilter) ([]mode
 
 func (s *productService) Get(ctx context.Context, id string) (models.Product, error) {
 	var (
-		product   models.Product
-		createdBy sql.NullString
+		product     models.Product
+		createdBy   sql.NullString
+		threshold   sql.NullInt64
+		trialDays   sql.NullInt64
+		keyTemplate sql.NullString
 	)
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, name, description, status, created_by, created_at, updated_at
+		SELECT id, name, description, status, fingerprint_threshold, trial_days,
+			COALESCE(request_signing, ''), client_public_key, client_secret IS NOT NULL, key_template,
+			created_by, created_at, updated_at
 		FROM products WHERE id = ?`,
 		id,
-	).Scan(&product.ID, &product.Name, &product.Description, &product.Status, &createdBy, &product.CreatedAt, &product.UpdatedAt)
+	).Scan(&product.ID, &product.Name, &product.Description, &product.Status, &threshold, &trialDays,
+		&product.RequestSigning, &product.ClientPublicKey, &product.HasClientSecret, &keyTemplate,
+		&createdBy, &product.CreatedAt, &product.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return models.Product{}, ErrProductNotFound
@@ -138,15 +182,36 @@ func (s *productService) Get(ctx context.Context, id string) (models.Product, er
 	if createdBy.Valid {
 		product.CreatedBy = createdBy.String
 	}
+	product.FingerprintThreshold = nullIntPtr(threshold)
+	product.TrialDays = nullIntPtr(trialDays)
+	product.KeyTemplate = decodeKeyTemplate(&keyTemplate.String)
 	return product, nil
 }
 
 func (s *productService) Update(ctx context.Context, id string, req models.UpdateProductRequest) error {
+	signing, publicKey, err := normalizeRequestSigning(req.RequestSigning, req.ClientPublicKey)
+	if err != nil {
+		return err
+	}
+	keyTemplate, err := encodeKeyTemplate(req.KeyTemplate)
+	if err != nil {
+		return err
+	}
+
 	result, err := s.db.ExecContext(ctx, `
 		UPDATE products
-		SET name = ?, description = ?, status = ?, updated_at = ?
+		SET name = ?, description = ?, status = ?,
+			fingerprint_threshold = CASE WHEN ? IS NULL THEN fingerprint_threshold ELSE NULLIF(?, 0) END,
+			trial_days = CASE WHEN ? IS NULL THEN trial_days WHEN ? < 0 THEN NULL ELSE ? END,
+			request_signing = CASE WHEN ? IS NULL THEN request_signing ELSE NULLIF(?, '') END,
+			client_public_key = CASE WHEN ? IS NULL THEN client_public_key ELSE NULLIF(?, '') END,
+			key_template = CASE WHEN ? IS NULL THEN key_template ELSE NULLIF(?, '') END,
+			updated_at = ?
 		WHERE id = ?`,
-		req.Name, req.Description, req.Status, time.Now().Format("2006-01-02 15:04:05"), id,
+		req.Name, req.Description, req.Status, req.FingerprintThreshold, req.FingerprintThreshold,
+		req.TrialDays, req.TrialDays, req.TrialDays,
+		signing, signing, publicKey, publicKey, keyTemplate, keyTemplate,
+		time.Now().Format("2006-01-02 15:04:05"), id,
 	)
 	if err != nil {
 		if isDuplicateKeyError(err) {
@@ -181,6 +246,104 @@ func (s *productService) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+// RotateClientSecret는 hmac 요청 서명에 사용할 새 클라이언트 비밀키를 발급합니다. 기존 비밀키는 즉시 무효화됩니다.
+func (s *productService) RotateClientSecret(ctx context.Context, id string) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(raw)
+
+	result, err := s.db.ExecContext(ctx, "UPDATE products SET client_secret = ?, updated_at = ? WHERE id = ?",
+		secret, time.Now().Format("2006-01-02 15:04:05"), id)
+	if err != nil {
+		return "", err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return "", ErrProductNotFound
+	}
+	return secret, nil
+}
+
+// normalizeRequestSigning은 요청 서명 방식과 공개키를 검증합니다. off는 빈 문자열(해제)로 바꿉니다.
+func normalizeRequestSigning(signing, publicKey *string) (*string, *string, error) {
+	if signing != nil {
+		value := strings.ToLower(strings.TrimSpace(*signing))
+		switch value {
+		case "", "off", "none":
+			value = ""
+		case models.RequestSigningHMAC, models.RequestSigningEd25519:
+		default:
+			return nil, nil, ErrInvalidRequestSigning
+		}
+		signing = &value
+	}
+	if publicKey != nil {
+		value := strings.TrimSpace(*publicKey)
+		if value != "" {
+			if _, err := licensecert.ParsePublicKey(value); err != nil {
+				return nil, nil, ErrInvalidRequestSigning
+			}
+		}
+		publicKey = &value
+	}
+	return signing, publicKey, nil
+}
+
+// encodeKeyTemplate는 키 템플릿을 검증해 JSON으로 저장할 값을 만듭니다.
+// nil이면 nil(변경 없음), length가 0이면 빈 문자열(기존 키 형식으로 초기화)을 반환합니다.
+func encodeKeyTemplate(tmpl *licensekey.Template) (*string, error) {
+	if tmpl == nil {
+		return nil, nil
+	}
+	value := ""
+	if tmpl.Length != 0 {
+		if err := tmpl.Validate(); err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		value = string(encoded)
+	}
+	return &value, nil
+}
+
+// decodeKeyTemplate는 저장된 키 템플릿 JSON을 읽습니다. 비어 있거나 읽을 수 없으면 nil을 반환합니다.
+func decodeKeyTemplate(raw *string) *licensekey.Template {
+	if raw == nil || strings.TrimSpace(*raw) == "" {
+		return nil
+	}
+	var tmpl licensekey.Template
+	if err := json.Unmarshal([]byte(*raw), &tmpl); err != nil {
+		return nil
+	}
+	return &tmpl
+}
+
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func emptyToNil(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
+
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
+
 func isDuplicateKeyError(err error) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitStore는 키별 토큰 버킷을 보관합니다.
+// 단일 서버는 메모리 구현을, 여러 서버가 같은 한도를 공유해야 하면 MySQL 구현을 사용합니다.
+type RateLimitStore interface {
+	// Take는 key 버킷에서 토큰 하나를 사용합니다. 버킷 용량은 limit이며 per 동안 limit개가 다시 채워집니다.
+	// 토큰이 없으면 false와 다음 토큰이 채워질 때까지의 대기 시간을 반환합니다.
+	Take(ctx context.Context, key string, limit int, per time.Duration) (bool, time.Duration, error)
+}
+
+// rateLimitSweepInterval 가득 찬(더 이상 의미 없는) 버킷을 정리하는 최소 간격
+const rateLimitSweepInterval = time.Minute
+
+// takeToken은 토큰 버킷 계산을 수행합니다. 남은 토큰과 허용 여부, 대기 시간, 버킷이 가득 차는 시각을 반환합니다.
+func takeToken(tokens float64, updatedAt, now time.Time, limit int, per time.Duration) (float64, bool, time.Duration, time.Time) {
+	capacity := float64(limit)
+	rate := capacity / per.Seconds() // 초당 채워지는 토큰 수
+
+	if elapsed := now.Sub(updatedAt).Seconds(); elapsed > 0 {
+		tokens = math.Min(capacity, tokens+elapsed*rate)
+	}
+
+	allowed := tokens >= 1
+	var retryAfter time.Duration
+	if allowed {
+		tokens--
+	} else {
+		retryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+
+	fullAt := now.Add(time.Duration((capacity - tokens) / rate * float64(time.Second)))
+	return tokens, allowed, retryAfter, fullAt
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time
+}
+
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore는 프로세스 메모리에 토큰 버킷을 보관하는 RateLimitStore를 생성합니다.
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{buckets: make(map[string]*memoryBucket)}
+}
+
+func (s *memoryRateLimitStore) Take(_ context.Context, key string, limit int, per time.Duration) (bool, time.Duration, error) {
+	if limit <= 0 || per <= 0 {
+		return true, 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= rateLimitSweepInterval {
+		for k, b := range s.buckets {
+			if !b.fullAt.After(now) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(limit), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	tokens, allowed, retryAfter, fullAt := takeToken(b.tokens, b.updatedAt, now, limit, per)
+	b.tokens, b.updatedAt, b.fullAt = tokens, now, fullAt
+	return allowed, retryAfter, nil
+}
+
+type sqlRateLimitStore struct {
+	db        SQLExecutor
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewSQLRateLimitStore는 rate_limit_buckets 테이블에 토큰 버킷을 보관하는 RateLimitStore를 생성합니다.
+func NewSQLRateLimitStore(db SQLExecutor) RateLimitStore {
+	return &sqlRateLimitStore{db: db}
+}
+
+func (s *sqlRateLimitStore) Take(ctx context.Context, key string, limit int, per time.Duration) (bool, time.Duration, error) {
+	if limit <= 0 || per <= 0 {
+		return true, 0, nil
+	}
+
+	now := time.Now()
+	s.sweep(ctx, now)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, 0, err
+	}
+	defer tx.Rollback()
+
+	nowMs := now.UnixMilli()
+	if _, err := tx.ExecContext(ctx, `
+		INSERT IGNORE INTO rate_limit_buckets (bucket_key, tokens, updated_at_ms, full_at_ms)
+		VALUES (?, ?, ?, ?)`, key, float64(limit), nowMs, nowMs); err != nil {
+		return false, 0, err
+	}
+
+	var (
+		tokens      float64
+		updatedAtMs int64
+	)
+	if err := tx.QueryRowContext(ctx,
+		"SELECT tokens, updated_at_ms FROM rate_limit_buckets WHERE bucket_key = ? FOR UPDATE", key,
+	).Scan(&tokens, &updatedAtMs); err != nil {
+		if err == sql.ErrNoRows {
+			return true, 0, nil
+		}
+		return false, 0, err
+	}
+
+	tokens, allowed, retryAfter, fullAt := takeToken(tokens, time.UnixMilli(updatedAtMs), now, limit, per)
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE rate_limit_buckets SET tokens = ?, updated_at_ms = ?, full_at_ms = ? WHERE bucket_key = ?",
+		tokens, nowMs, fullAt.UnixMilli(), key,
+	); err != nil {
+		return false, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, 0, err
+	}
+	return allowed, retryAfter, nil
+}
+
+func (s *sqlRateLimitStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	_, _ = s.db.ExecContext(ctx, "DELETE FROM rate_limit_buckets WHERE full_at_ms <= ?", now.UnixMilli())
+}
+
+// NewRateLimitStore는 설정 값(memory, mysql)에 맞는 RateLimitStore를 생성합니다. 알 수 없는 값이면 메모리 구현을 사용합니다.
+func NewRateLimitStore(kind string, db SQLExecutor) RateLimitStore {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "mysql", "sql", "db":
+		return NewSQLRateLimitStore(db)
+	default:
+		return NewMemoryRateLimitStore()
+	}
+}
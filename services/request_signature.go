@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"studiolicense/licensecert"
+	"studiolicense/models"
+	"studiolicense/utils"
+)
+
+// 클라이언트 요청 서명 헤더
+const (
+	HeaderRequestTimestamp = "X-License-Timestamp" // 유닉스 시간(초)
+	HeaderRequestNonce     = "X-License-Nonce"     // 요청마다 새로 만든 임의 문자열
+	HeaderRequestSignature = "X-License-Signature" // hmac: hex, ed25519: base64
+)
+
+var (
+	// ErrRequestSignatureRequired는 서명이 필요한 제품의 요청에 서명 헤더가 없을 때 반환됩니다.
+	ErrRequestSignatureRequired = errors.New("request signature required")
+	// ErrRequestSignatureInvalid는 서명이 일치하지 않을 때 반환됩니다.
+	ErrRequestSignatureInvalid = errors.New("invalid request signature")
+	// ErrRequestExpired는 요청 시각이 허용 범위를 벗어났을 때 반환됩니다.
+	ErrRequestExpired = errors.New("request timestamp outside allowed window")
+	// ErrRequestReplayed는 이미 사용한 nonce로 요청했을 때 반환됩니다.
+	ErrRequestReplayed = errors.New("request nonce already used")
+	// ErrRequestProductUnknown은 서명이 필요한 경로의 요청에서 제품을 찾을 수 없을 때 반환됩니다.
+	ErrRequestProductUnknown = errors.New("request product not found")
+)
+
+// RequestSignatureVerifier는 제품별 설정에 따라 클라이언트 요청의 서명, 시각, nonce를 검증합니다.
+// 요청 서명을 켜지 않은 제품의 요청은 검사하지 않고 통과시킵니다.
+type RequestSignatureVerifier struct {
+	db      SQLExecutor
+	nonces  NonceStore
+	maxSkew time.Duration
+}
+
+// NewRequestSignatureVerifier는 요청 서명 검증기를 생성합니다. maxSkew는 허용하는 요청 시각 오차입니다.
+func NewRequestSignatureVerifier(db SQLExecutor, nonces NonceStore, maxSkew time.Duration) *RequestSignatureVerifier {
+	if maxSkew <= 0 {
+		maxSkew = 5 * time.Minute
+	}
+	return &RequestSignatureVerifier{db: db, nonces: nonces, maxSkew: maxSkew}
+}
+
+// BuildRequestSigningPayload는 서명 대상 문자열을 만듭니다.
+// 형식: METHOD\nPATH\nTIMESTAMP\nNONCE\nhex(sha256(body))
+func BuildRequestSigningPayload(method, path, timestamp, nonce string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{strings.ToUpper(method), path, timestamp, nonce, hex.EncodeToString(sum[:])}, "\n")
+}
+
+// VerifyRequest는 요청 본문에서 제품을 찾아 서명이 필요한 제품이면 서명 헤더를 검증합니다.
+func (v *RequestSignatureVerifier) VerifyRequest(ctx context.Context, method, path string, header http.Header, body []byte) error {
+	productID, signing, secret, publicKey, err := v.lookupProduct(ctx, body)
+	if err != nil {
+		return err
+	}
+	if signing == "" {
+		return nil
+	}
+
+	timestamp := strings.TrimSpace(header.Get(HeaderRequestTimestamp))
+	nonce := strings.TrimSpace(header.Get(HeaderRequestNonce))
+	signature := strings.TrimSpace(header.Get(HeaderRequestSignature))
+	if timestamp == "" || nonce == "" || signature == "" || len(nonce) > 128 {
+		return ErrRequestSignatureRequired
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrRequestExpired
+	}
+	requestTime := time.Unix(seconds, 0)
+	if skew := time.Since(requestTime); skew > v.maxSkew || skew < -v.maxSkew {
+		return ErrRequestExpired
+	}
+
+	payload := []byte(BuildRequestSigningPayload(method, path, timestamp, nonce, body))
+	if err := verifyProductSignature(signing, secret, publicKey, payload, signature); err != nil {
+		return err
+	}
+
+	// 서명이 유효한 요청만 nonce를 기록해, 위조 요청으로 nonce 저장소가 채워지지 않도록 합니다.
+	// 허용 시각 범위 밖의 요청은 이미 거부되므로 범위 두 배 동안만 보관하면 충분합니다.
+	fresh, err := v.nonces.Remember(ctx, productID+":"+nonce, requestTime.Add(2*v.maxSkew))
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrRequestReplayed
+	}
+	return nil
+}
+
+// VerifyOfflineRequest는 오프라인 요청 파일의 제품 서명(product_signature)을 검증합니다.
+// 요청 파일은 서명 헤더 없이 업로드되므로, 서명이 필요한 제품은 파일 안의 제품 서명으로 같은 보호를 받습니다.
+// 재전송은 요청 파일의 nonce와 생성 시각으로 핸들러가 막습니다.
+func (v *RequestSignatureVerifier) VerifyOfflineRequest(ctx context.Context, licenseKey string, signed licensecert.SignedOfflineRequest) error {
+	_, signing, secret, publicKey, err := v.lookupLicenseProduct(ctx, licenseKey)
+	if err != nil {
+		return err
+	}
+	if signing == "" {
+		return nil
+	}
+	signature := strings.TrimSpace(signed.ProductSignature)
+	if signature == "" {
+		return ErrRequestSignatureRequired
+	}
+	return verifyProductSignature(signing, secret, publicKey, signed.ProductSigningInput(), signature)
+}
+
+// verifyProductSignature는 제품의 서명 방식과 키로 payload 서명을 확인합니다. hmac은 hex, ed25519는 base64입니다.
+func verifyProductSignature(signing string, secret, publicKey sql.NullString, payload []byte, signature string) error {
+	switch signing {
+	case models.RequestSigningHMAC:
+		if !secret.Valid || secret.String == "" {
+			return ErrRequestSignatureInvalid
+		}
+		mac := hmac.New(sha256.New, []byte(secret.String))
+		mac.Write(payload)
+		provided, err := hex.DecodeString(signature)
+		if err != nil || !hmac.Equal(provided, mac.Sum(nil)) {
+			return ErrRequestSignatureInvalid
+		}
+	case models.RequestSigningEd25519:
+		if !publicKey.Valid {
+			return ErrRequestSignatureInvalid
+		}
+		key, err := licensecert.ParsePublicKey(publicKey.String)
+		if err != nil {
+			return ErrRequestSignatureInvalid
+		}
+		provided, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil || !ed25519.Verify(key, payload, provided) {
+			return ErrRequestSignatureInvalid
+		}
+	default:
+		return ErrRequestSignatureInvalid
+	}
+	return nil
+}
+
+// lookupProduct는 요청 본문의 license_key 또는 product_id로 제품의 서명 설정을 조회합니다.
+// 서명이 필요한 경로이므로 제품을 특정할 수 없는 요청은 서명 검사를 건너뛰지 않고 거부합니다.
+func (v *RequestSignatureVerifier) lookupProduct(ctx context.Context, body []byte) (string, string, sql.NullString, sql.NullString, error) {
+	var target struct {
+		LicenseKey string `json:"license_key"`
+		ProductID  string `json:"product_id"`
+	}
+	_ = json.Unmarshal(body, &target)
+
+	switch {
+	case strings.TrimSpace(target.LicenseKey) != "":
+		return v.lookupLicenseProduct(ctx, target.LicenseKey)
+	case strings.TrimSpace(target.ProductID) != "":
+		var (
+			productID string
+			signing   string
+			secret    sql.NullString
+			publicKey sql.NullString
+		)
+		err := v.db.QueryRowContext(ctx, `SELECT id, COALESCE(request_signing, ''), client_secret, client_public_key
+			FROM products WHERE id = ?`, target.ProductID).Scan(&productID, &signing, &secret, &publicKey)
+		if err == sql.ErrNoRows {
+			err = ErrRequestProductUnknown
+		}
+		return productID, signing, secret, publicKey, err
+	default:
+		return "", "", sql.NullString{}, sql.NullString{}, ErrRequestProductUnknown
+	}
+}
+
+// lookupLicenseProduct는 라이선스 키가 속한 제품의 서명 설정을 조회합니다.
+func (v *RequestSignatureVerifier) lookupLicenseProduct(ctx context.Context, licenseKey string) (string, string, sql.NullString, sql.NullString, error) {
+	// 핸들러와 같은 표준 형식으로 조회해야 키 표기를 바꿔 서명 검사를 피할 수 없습니다.
+	licenseKey, _ = utils.NormalizeLicenseKey(licenseKey)
+
+	var (
+		productID string
+		signing   string
+		secret    sql.NullString
+		publicKey sql.NullString
+	)
+	err := v.db.QueryRowContext(ctx, `SELECT p.id, COALESCE(p.request_signing, ''), p.client_secret, p.client_public_key
+		FROM licenses l JOIN products p ON l.product_id = p.id
+		WHERE l.license_key = ?`, utils.LicenseKeyLookup(licenseKey)).Scan(&productID, &signing, &secret, &publicKey)
+	if err == sql.ErrNoRows {
+		err = ErrRequestProductUnknown
+	}
+	return productID, signing, secret, publicKey, err
+}
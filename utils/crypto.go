@@ -5,30 +5,15 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"strings"
+	"studiolicense/licensekey"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 // GenerateLicenseKey 라이선스 키 생성 (형식: XXXX-XXXX-XXXX-XXXX)
+// 키 템플릿이 없는 제품의 기존 형식이며, 제품별 템플릿은 GenerateProductLicenseKey를 사용합니다.
 func GenerateLicenseKey() (string, error) {
-	bytes := make([]byte, 8)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", err
-	}
-
-	key := hex.EncodeToString(bytes)
-	key = strings.ToUpper(key)
-
-	// 4자리씩 끊어서 대시로 연결
-	formatted := fmt.Sprintf("%s-%s-%s-%s",
-		key[0:4],
-		key[4:8],
-		key[8:12],
-		key[12:16],
-	)
-
-	return formatted, nil
+	return licensekey.Legacy.Generate()
 }
 
 // GenerateID UUID 스타일 ID 생성
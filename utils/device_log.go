@@ -7,10 +7,16 @@ import (
 
 // LogDeviceActivity 디바이스 활동 로그 기록 헬퍼
 func LogDeviceActivity(deviceID, licenseID, action, details string) {
-	query := `INSERT INTO device_activity_logs (device_id, license_id, action, details, created_at) 
-		VALUES (?, ?, ?, ?, ?)`
-	_, err := database.DB.Exec(query, deviceID, licenseID, action, details, NowSeoul())
-	if err != nil {
+	if err := RecordDeviceActivity(database.DB, deviceID, licenseID, action, details); err != nil {
 		logger.Error("Failed to log device activity: %v", err)
 	}
 }
+
+// RecordDeviceActivity 주어진 트랜잭션(또는 DB)으로 디바이스 활동 로그를 기록합니다.
+// 셀프 해제 횟수처럼 로그 자체가 판단 기준이 되는 경우 변경과 같은 트랜잭션에서 기록합니다.
+func RecordDeviceActivity(db QueryExecer, deviceID, licenseID, action, details string) error {
+	query := `INSERT INTO device_activity_logs (device_id, license_id, action, details, created_at) 
+		VALUES (?, ?, ?, ?, ?)`
+	_, err := db.Exec(query, deviceID, licenseID, action, details, NowSeoul())
+	return err
+}
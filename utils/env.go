@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GetEnvInt 정수형 환경변수를 읽고, 없거나 잘못된 값이면 fallback을 반환합니다.
+func GetEnvInt(name string, fallback int) int {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"studiolicense/models"
+)
+
+// FingerprintComponents 하드웨어 구성요소별 해시 (구성요소 이름 → SHA-256 hex)
+type FingerprintComponents map[string]string
+
+// fingerprintWeights 구성요소별 가중치. 임계값은 일치한 구성요소 가중치의 합과 비교합니다.
+var fingerprintWeights = map[string]int{
+	"client_id":      1,
+	"cpu_id":         1,
+	"motherboard_sn": 1,
+	"mac_address":    1,
+	"disk_serial":    1,
+	"machine_id":     1,
+}
+
+// FingerprintMaxScore 모든 구성요소가 일치할 때의 점수
+func FingerprintMaxScore() int {
+	total := 0
+	for _, weight := range fingerprintWeights {
+		total += weight
+	}
+	return total
+}
+
+// GenerateFingerprintComponents 디바이스 정보로 구성요소별 해시를 생성합니다.
+// 값이 비어 있는 구성요소는 비교 대상에서 제외하기 위해 포함하지 않습니다.
+func GenerateFingerprintComponents(info models.DeviceInfo) FingerprintComponents {
+	values := map[string]string{
+		"client_id":      info.ClientID,
+		"cpu_id":         info.CPUID,
+		"motherboard_sn": info.MotherboardSN,
+		"mac_address":    info.MACAddress,
+		"disk_serial":    info.DiskSerial,
+		"machine_id":     info.MachineID,
+	}
+
+	components := FingerprintComponents{}
+	for name, value := range values {
+		if value == "" {
+			continue
+		}
+		hash := sha256.Sum256([]byte(name + "|" + value))
+		components[name] = hex.EncodeToString(hash[:])
+	}
+	return components
+}
+
+// ParseFingerprintComponents DB에 저장된 JSON 문자열을 구성요소 해시로 변환합니다.
+func ParseFingerprintComponents(raw string) (FingerprintComponents, error) {
+	components := FingerprintComponents{}
+	if raw == "" {
+		return components, nil
+	}
+	err := json.Unmarshal([]byte(raw), &components)
+	return components, err
+}
+
+// String DB 저장용 JSON 문자열
+func (c FingerprintComponents) String() string {
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+// MatchScore 두 구성요소 집합의 일치 점수와 변경된 구성요소 목록을 반환합니다.
+func (c FingerprintComponents) MatchScore(other FingerprintComponents) (score int, changed []string) {
+	for name, weight := range fingerprintWeights {
+		stored, ok := c[name]
+		if !ok {
+			continue
+		}
+		if other[name] == stored {
+			score += weight
+		} else {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return score, changed
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+
+	"studiolicense/models"
+)
+
+// QueryExecer *sql.DB와 *sql.Tx를 모두 받을 수 있는 최소 인터페이스
+type QueryExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// LicenseSnapshot 이력에 기록하는 라이선스 필드 값 (nil은 값 없음)
+type LicenseSnapshot map[string]interface{}
+
+// LoadLicenseSnapshot 변경 전후 비교를 위해 이력 대상 필드를 읽습니다.
+func LoadLicenseSnapshot(db QueryExecer, licenseID string) (LicenseSnapshot, error) {
+	var (
+		status, licenseType             string
+		customerName, customerEmail     string
+		expiresAt                       sql.NullString
+		maxDevices                      int64
+		isTrial                         bool
+		productID, policyID, customerID sql.NullString
+		suspensionReason, resumeAt      sql.NullString
+		revocationReason, startsAt      sql.NullString
+		maintenanceUntil                sql.NullString
+		releaseChannel                  sql.NullString
+		gracePeriodDays, maxOfflineDays sql.NullInt64
+	)
+	err := db.QueryRow(`SELECT DATE_FORMAT(expires_at, '%Y-%m-%d'), status, license_type, customer_name, customer_email,
+		max_devices, is_trial, product_id, policy_id, customer_id, grace_period_days, max_offline_days,
+		suspension_reason, DATE_FORMAT(resume_at, '%Y-%m-%d'), revocation_reason,
+		DATE_FORMAT(starts_at, '%Y-%m-%d %H:%i:%s'), DATE_FORMAT(maintenance_until, '%Y-%m-%d'),
+		release_channel
+		FROM licenses WHERE id = ?`, licenseID,
+	).Scan(&expiresAt, &status, &licenseType, &customerName, &customerEmail,
+		&maxDevices, &isTrial, &productID, &policyID, &customerID, &gracePeriodDays, &maxOfflineDays,
+		&suspensionReason, &resumeAt, &revocationReason,
+		&startsAt, &maintenanceUntil, &releaseChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	return LicenseSnapshot{
+		"expires_at":        nullStringValue(expiresAt),
+		"status":            status,
+		"license_type":      licenseType,
+		"customer_name":     customerName,
+		"customer_email":    customerEmail,
+		"max_devices":       maxDevices,
+		"is_trial":          isTrial,
+		"product_id":        nullStringValue(productID),
+		"policy_id":         nullStringValue(policyID),
+		"customer_id":       nullStringValue(customerID),
+		"grace_period_days": nullInt64Value(gracePeriodDays),
+		"max_offline_days":  nullInt64Value(maxOfflineDays),
+		"suspension_reason": nullStringValue(suspensionReason),
+		"resume_at":         nullStringValue(resumeAt),
+		"revocation_reason": nullStringValue(revocationReason),
+		"starts_at":         nullStringValue(startsAt),
+		"maintenance_until": nullStringValue(maintenanceUntil),
+		"release_channel":   nullStringValue(releaseChannel),
+	}, nil
+}
+
+// DiffLicenseSnapshots 바뀐 필드만 이전/이후 값으로 반환합니다. before가 nil이면(생성) 값이 있는 필드를 모두 반환합니다.
+func DiffLicenseSnapshots(before, after LicenseSnapshot) map[string]models.LicenseFieldChange {
+	changes := make(map[string]models.LicenseFieldChange)
+	for field, newValue := range after {
+		oldValue := before[field]
+		if before == nil && newValue == nil {
+			continue
+		}
+		if before != nil && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes[field] = models.LicenseFieldChange{Old: oldValue, New: newValue}
+	}
+	return changes
+}
+
+// RecordLicenseEvent 라이선스 변경 이력을 기록합니다. 바뀐 필드가 없는 수정 이벤트는 기록하지 않습니다.
+// 라이선스 변경과 같은 트랜잭션에서 기록하려면 db에 *sql.Tx를 넘깁니다.
+func RecordLicenseEvent(db QueryExecer, event models.LicenseEvent) error {
+	if event.EventType == models.LicenseEventUpdated && len(event.Changes) == 0 {
+		return nil
+	}
+
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return err
+	}
+	var orderRef interface{}
+	if event.OrderRef != nil && *event.OrderRef != "" {
+		orderRef = *event.OrderRef
+	}
+
+	_, err = db.Exec(`INSERT INTO license_events (license_id, event_type, changes, reason, order_ref, actor_id, actor_name, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.LicenseID, event.EventType, string(changes), event.Reason, orderRef, event.ActorID, event.ActorName,
+		FormatDateTimeForDB(NowSeoul()),
+	)
+	return err
+}
+
+func nullStringValue(value sql.NullString) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.String
+}
+
+func nullInt64Value(value sql.NullInt64) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.Int64
+}
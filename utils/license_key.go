@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/licensekey"
+	"studiolicense/logger"
+)
+
+// keyTemplateCacheTTL 제품 키 템플릿 캐시 유지 시간
+const keyTemplateCacheTTL = time.Minute
+
+var keyTemplateCache struct {
+	mu        sync.Mutex
+	templates []licensekey.Template
+	loadedAt  time.Time
+}
+
+// LoadKeyTemplate 제품에 설정된 라이선스 키 템플릿을 조회합니다. 설정이 없으면 기존 형식(licensekey.Legacy)을 반환합니다.
+func LoadKeyTemplate(productID string) (licensekey.Template, error) {
+	var raw sql.NullString
+	err := database.DB.QueryRow("SELECT key_template FROM products WHERE id = ?", productID).Scan(&raw)
+	if err != nil && err != sql.ErrNoRows {
+		return licensekey.Template{}, err
+	}
+	if !raw.Valid || strings.TrimSpace(raw.String) == "" {
+		return licensekey.Legacy, nil
+	}
+
+	var tmpl licensekey.Template
+	if err := json.Unmarshal([]byte(raw.String), &tmpl); err != nil {
+		return licensekey.Template{}, err
+	}
+	return tmpl, nil
+}
+
+// GenerateProductLicenseKey 제품의 키 템플릿에 맞는 라이선스 키를 생성합니다.
+func GenerateProductLicenseKey(productID string) (string, error) {
+	tmpl, err := LoadKeyTemplate(productID)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Generate()
+}
+
+// NormalizeLicenseKey 클라이언트가 보낸 키를 DB 조회 전에 검사합니다.
+// 체크섬이 있는 제품 템플릿과 형식이 맞지만 체크섬이 틀리면 licensekey.ErrChecksumMismatch를 반환해
+// 오타를 DB 조회 없이 걸러냅니다. 템플릿과 맞는 키는 표준 형식(대문자, Crockford 혼동 문자 보정)으로 바꾸고,
+// 어떤 템플릿과도 맞지 않는 키는 기존 형식(licensekey.Legacy)으로 보고, 그마저 아니면 그대로 반환합니다.
+// 해시 저장 모드에서는 이 표준 형식으로 해시를 계산합니다.
+// 기존 형식은 체크섬이 없어 같은 모양의 템플릿 키도 통과시키므로, 체크섬 검사를 건너뛰지 않도록 마지막에 확인합니다.
+func NormalizeLicenseKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return key, nil
+	}
+
+	mismatch := false
+	for _, tmpl := range cachedKeyTemplates() {
+		normalized, err := tmpl.Verify(key)
+		if err == nil {
+			return normalized, nil
+		}
+		if errors.Is(err, licensekey.ErrChecksumMismatch) {
+			mismatch = true
+		}
+	}
+	if mismatch {
+		return key, licensekey.ErrChecksumMismatch
+	}
+	if normalized, err := licensekey.Legacy.Normalize(key); err == nil {
+		return normalized, nil
+	}
+	return key, nil
+}
+
+// InvalidateKeyTemplateCache 제품 키 템플릿이 바뀌었을 때 캐시를 비웁니다.
+func InvalidateKeyTemplateCache() {
+	keyTemplateCache.mu.Lock()
+	keyTemplateCache.loadedAt = time.Time{}
+	keyTemplateCache.mu.Unlock()
+}
+
+// cachedKeyTemplates 제품에 설정된 키 템플릿 목록을 캐시에서 반환합니다. 체크섬이 있는 템플릿이 먼저 옵니다.
+func cachedKeyTemplates() []licensekey.Template {
+	keyTemplateCache.mu.Lock()
+	defer keyTemplateCache.mu.Unlock()
+
+	if time.Since(keyTemplateCache.loadedAt) < keyTemplateCacheTTL {
+		return keyTemplateCache.templates
+	}
+
+	rows, err := database.DB.Query("SELECT DISTINCT key_template FROM products WHERE key_template IS NOT NULL AND key_template <> ''")
+	if err != nil {
+		logger.Warn("Failed to load license key templates: %v", err)
+		return keyTemplateCache.templates
+	}
+	defer rows.Close()
+
+	var withChecksum, withoutChecksum []licensekey.Template
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			continue
+		}
+		var tmpl licensekey.Template
+		if err := json.Unmarshal([]byte(raw), &tmpl); err != nil || tmpl.Validate() != nil {
+			continue
+		}
+		if tmpl.ChecksumLength > 0 {
+			withChecksum = append(withChecksum, tmpl)
+		} else {
+			withoutChecksum = append(withoutChecksum, tmpl)
+		}
+	}
+
+	keyTemplateCache.templates = append(withChecksum, withoutChecksum...)
+	keyTemplateCache.loadedAt = time.Now()
+	return keyTemplateCache.templates
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"studiolicense/database"
+	"studiolicense/logger"
+)
+
+// 라이선스 키 저장 방식 (LICENSE_KEY_STORAGE)
+const (
+	LicenseKeyStoragePlaintext = "plaintext"
+	LicenseKeyStorageHashed    = "hashed"
+)
+
+// hashedLicenseKeyPrefix 해시로 저장된 키 값의 접두사. 키 템플릿 문자에는 ':'가 없으므로 평문 키와 구분됩니다.
+const hashedLicenseKeyPrefix = "hmac:"
+
+// licenseKeyHashSecret 해시 저장 모드의 키 (비어 있으면 평문 저장 모드)
+var licenseKeyHashSecret []byte
+
+// ConfigureLicenseKeyStorage 라이선스 키 저장 방식을 설정합니다.
+// hashed 모드에서는 DB에 키의 HMAC-SHA256 값과 표시용 접두사만 저장하며 secret이 반드시 필요합니다.
+// secret을 잃어버리거나 바꾸면 기존 키를 모두 조회할 수 없으므로 안전하게 보관해야 합니다.
+func ConfigureLicenseKeyStorage(mode, secret string) error {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", LicenseKeyStoragePlaintext:
+		licenseKeyHashSecret = nil
+		return nil
+	case LicenseKeyStorageHashed:
+		if len(secret) < 32 {
+			return fmt.Errorf("LICENSE_KEY_HASH_SECRET must be at least 32 characters in hashed mode")
+		}
+		licenseKeyHashSecret = []byte(secret)
+		return nil
+	default:
+		return fmt.Errorf("unknown license key storage mode %q", mode)
+	}
+}
+
+// LicenseKeysHashed 해시 저장 모드 여부
+func LicenseKeysHashed() bool {
+	return len(licenseKeyHashSecret) > 0
+}
+
+// LicenseKeyLookup licenses.license_key, client_logs.license_key에 저장/조회할 값을 반환합니다.
+// 평문 모드이면 키 그대로, 해시 모드이면 "hmac:" + hex(HMAC-SHA256(secret, key))입니다.
+func LicenseKeyLookup(key string) string {
+	if !LicenseKeysHashed() || key == "" || strings.HasPrefix(key, hashedLicenseKeyPrefix) {
+		return key
+	}
+	mac := hmac.New(sha256.New, licenseKeyHashSecret)
+	mac.Write([]byte(key))
+	return hashedLicenseKeyPrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// LicenseKeyPrefix 관리 화면에서 키를 구분할 수 있도록 저장하는 표시용 접두사입니다.
+// 평문 모드이면 nil, 해시 모드이면 템플릿 접두사와 첫 4자리(예: SL-954E)를 반환합니다.
+func LicenseKeyPrefix(key, productID string) *string {
+	if !LicenseKeysHashed() {
+		return nil
+	}
+	visible := 4
+	if tmpl, err := LoadKeyTemplate(productID); err == nil && tmpl.Prefix != "" {
+		visible += len(tmpl.Prefix) + 1
+	}
+	if visible > len(key) {
+		visible = len(key)
+	}
+	prefix := key[:visible]
+	return &prefix
+}
+
+// LicenseKeyDisplaySQL 조회 쿼리에서 표시용 키를 만드는 SQL 식을 반환합니다. column은 "l.license_key" 같은 키 컬럼입니다.
+// 해시로 저장된 키는 "접두사..." 형태로, 평문 키는 그대로 표시합니다.
+func LicenseKeyDisplaySQL(column string) string {
+	return fmt.Sprintf("COALESCE(CONCAT(%s_prefix, '...'), %s)", column, column)
+}
+
+// MigrateLicenseKeysToHashed 평문으로 저장된 라이선스 키와 클라이언트 로그의 키를 해시 값으로 바꿉니다.
+// 이미 해시로 저장된 행은 건너뛰므로 서버를 시작할 때마다 실행해도 안전합니다.
+func MigrateLicenseKeysToHashed() (int, error) {
+	if !LicenseKeysHashed() {
+		return 0, nil
+	}
+
+	rows, err := database.DB.Query("SELECT id, license_key, product_id FROM licenses WHERE license_key NOT LIKE ?", hashedLicenseKeyPrefix+"%")
+	if err != nil {
+		return 0, err
+	}
+	type plainKey struct {
+		id        string
+		key       string
+		productID sql.NullString
+	}
+	var pending []plainKey
+	for rows.Next() {
+		var item plainKey
+		if err := rows.Scan(&item.id, &item.key, &item.productID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, item := range pending {
+		key, _ := NormalizeLicenseKey(item.key)
+		hashed := LicenseKeyLookup(key)
+
+		tx, err := database.DB.Begin()
+		if err != nil {
+			return migrated, err
+		}
+		if _, err := tx.Exec("UPDATE licenses SET license_key = ?, license_key_prefix = ? WHERE id = ?",
+			hashed, LicenseKeyPrefix(key, item.productID.String), item.id); err != nil {
+			tx.Rollback()
+			return migrated, err
+		}
+		if _, err := tx.Exec("UPDATE client_logs SET license_key = ? WHERE license_key = ?", hashed, item.key); err != nil {
+			tx.Rollback()
+			return migrated, err
+		}
+		if err := tx.Commit(); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	// 존재하지 않는 키로 보낸 클라이언트 로그도 평문이 남지 않도록 해시로 바꿉니다.
+	logRows, err := database.DB.Query("SELECT DISTINCT license_key FROM client_logs WHERE license_key <> '' AND license_key NOT LIKE ?", hashedLicenseKeyPrefix+"%")
+	if err != nil {
+		return migrated, err
+	}
+	var orphanKeys []string
+	for logRows.Next() {
+		var key string
+		if err := logRows.Scan(&key); err == nil {
+			orphanKeys = append(orphanKeys, key)
+		}
+	}
+	logRows.Close()
+	for _, key := range orphanKeys {
+		normalized, _ := NormalizeLicenseKey(key)
+		if _, err := database.DB.Exec("UPDATE client_logs SET license_key = ? WHERE license_key = ?", LicenseKeyLookup(normalized), key); err != nil {
+			return migrated, err
+		}
+	}
+
+	if migrated > 0 || len(orphanKeys) > 0 {
+		logger.WithFields(map[string]interface{}{
+			"licenses":        migrated,
+			"client_log_keys": len(orphanKeys),
+		}).Info("Migrated plaintext license keys to hashed storage")
+	}
+	return migrated, nil
+}
@@ -0,0 +1,85 @@
+package utils
+
+import "time"
+
+// LicenseLifecycle 만료 이후 유예 기간과 오프라인 허용 기간 설정
+type LicenseLifecycle struct {
+	GracePeriodDays int `json:"grace_period_days"`
+	MaxOfflineDays  int `json:"max_offline_days"` // 0이면 제한 없음
+}
+
+// ResolveLicenseLifecycle 라이선스 → 정책 데이터 → 환경변수 순으로 유예/오프라인 설정을 결정합니다.
+// 환경변수 기본값: LICENSE_GRACE_PERIOD_DAYS(0), LICENSE_MAX_OFFLINE_DAYS(7)
+func ResolveLicenseLifecycle(gracePeriodDays, maxOfflineDays *int, policyID *string) LicenseLifecycle {
+	return resolveLicenseLifecycle(gracePeriodDays, maxOfflineDays, loadPolicyData(policyID))
+}
+
+// LicenseLifecycleCache 여러 라이선스의 설정을 한 번에 계산할 때(스케줄러, 폐기 목록) 정책 데이터를 정책 ID별로 한 번만 읽습니다.
+type LicenseLifecycleCache map[string]map[string]interface{}
+
+// NewLicenseLifecycleCache 빈 정책 데이터 캐시를 생성합니다.
+func NewLicenseLifecycleCache() LicenseLifecycleCache {
+	return LicenseLifecycleCache{}
+}
+
+// Resolve ResolveLicenseLifecycle과 같지만 정책 데이터를 캐시에서 읽습니다.
+func (c LicenseLifecycleCache) Resolve(gracePeriodDays, maxOfflineDays *int, policyID *string) LicenseLifecycle {
+	var policyData map[string]interface{}
+	if policyID != nil && *policyID != "" {
+		data, ok := c[*policyID]
+		if !ok {
+			data = loadPolicyData(policyID)
+			c[*policyID] = data
+		}
+		policyData = data
+	}
+	return resolveLicenseLifecycle(gracePeriodDays, maxOfflineDays, policyData)
+}
+
+// resolveLicenseLifecycle 이미 읽어 둔 정책 데이터로 유예/오프라인 설정을 결정합니다.
+func resolveLicenseLifecycle(gracePeriodDays, maxOfflineDays *int, policyData map[string]interface{}) LicenseLifecycle {
+	lifecycle := LicenseLifecycle{
+		GracePeriodDays: GetEnvInt("LICENSE_GRACE_PERIOD_DAYS", 0),
+		MaxOfflineDays:  GetEnvInt("LICENSE_MAX_OFFLINE_DAYS", 7),
+	}
+
+	if value, ok := policyDataInt(policyData, "grace_period_days"); ok {
+		lifecycle.GracePeriodDays = value
+	}
+	if value, ok := policyDataInt(policyData, "max_offline_days"); ok {
+		lifecycle.MaxOfflineDays = value
+	}
+
+	if gracePeriodDays != nil {
+		lifecycle.GracePeriodDays = *gracePeriodDays
+	}
+	if maxOfflineDays != nil {
+		lifecycle.MaxOfflineDays = *maxOfflineDays
+	}
+
+	if lifecycle.GracePeriodDays < 0 {
+		lifecycle.GracePeriodDays = 0
+	}
+	if lifecycle.MaxOfflineDays < 0 {
+		lifecycle.MaxOfflineDays = 0
+	}
+	return lifecycle
+}
+
+// LicenseValidUntil 만료일 당일까지 사용 가능하므로 만료일 다음 날 0시를 반환합니다.
+func LicenseValidUntil(expiresAt string) (time.Time, error) {
+	ts, err := ParseDBDate(expiresAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return StartOfDay(ts).AddDate(0, 0, 1), nil
+}
+
+// GraceEndsAt 유예 기간이 끝나는 시각
+func (l LicenseLifecycle) GraceEndsAt(expiresAt string) (time.Time, error) {
+	validUntil, err := LicenseValidUntil(expiresAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return validUntil.AddDate(0, 0, l.GracePeriodDays), nil
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/licensecert"
+	"studiolicense/logger"
+	"studiolicense/models"
+)
+
+type signingKey struct {
+	id         string
+	privateKey ed25519.PrivateKey
+}
+
+// signingKeyCacheTTL 활성 서명 키 캐시 유지 시간
+// 여러 서버가 같은 DB를 쓰면 다른 서버에서 키를 교체해도 이 시간 안에 새 활성 키로 서명하게 됩니다.
+const signingKeyCacheTTL = 30 * time.Second
+
+var (
+	signingKeyMu       sync.Mutex
+	cachedSigningKey   *signingKey
+	signingKeyLoadedAt time.Time
+)
+
+// EnsureLicenseSigningKey 활성 서명 키가 없으면 새로 생성합니다.
+func EnsureLicenseSigningKey() error {
+	_, err := activeSigningKey()
+	return err
+}
+
+// RotateLicenseSigningKey 새 서명 키를 생성하고 기존 활성 키를 retired 상태로 전환합니다.
+// retired 키는 공개키 목록에 계속 노출되어 이전에 발급된 인증서를 검증할 수 있습니다.
+func RotateLicenseSigningKey() (models.LicenseSigningKey, error) {
+	signingKeyMu.Lock()
+	defer signingKeyMu.Unlock()
+
+	key, err := createSigningKey(true)
+	if err != nil {
+		return models.LicenseSigningKey{}, err
+	}
+	cachedSigningKey = nil
+	return key, nil
+}
+
+// ListLicenseSigningKeys 공개 가능한 서명 키 목록을 최신순으로 반환합니다.
+func ListLicenseSigningKeys() ([]models.LicenseSigningKey, error) {
+	rows, err := database.DB.Query(`SELECT id, algorithm, public_key, status, created_at, retired_at
+		FROM license_signing_keys ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.LicenseSigningKey{}
+	for rows.Next() {
+		var (
+			key       models.LicenseSigningKey
+			retiredAt sql.NullString
+		)
+		if err := rows.Scan(&key.ID, &key.Algorithm, &key.PublicKey, &key.Status, &key.CreatedAt, &retiredAt); err != nil {
+			return nil, err
+		}
+		if retiredAt.Valid {
+			key.RetiredAt = &retiredAt.String
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// SignLicenseCertificate 현재 활성 키로 라이선스 인증서에 서명합니다.
+func SignLicenseCertificate(cert licensecert.Certificate) (licensecert.SignedCertificate, error) {
+	key, err := activeSigningKey()
+	if err != nil {
+		return licensecert.SignedCertificate{}, err
+	}
+	return licensecert.Sign(cert, key.id, key.privateKey)
+}
+
+// SignOfflineResponse 현재 활성 키로 오프라인 활성화/비활성화 응답 파일에 서명합니다.
+func SignOfflineResponse(resp licensecert.OfflineResponse) (licensecert.SignedOfflineResponse, error) {
+	key, err := activeSigningKey()
+	if err != nil {
+		return licensecert.SignedOfflineResponse{}, err
+	}
+	return licensecert.SignOfflineResponse(resp, key.id, key.privateKey)
+}
+
+// SignRevocationList 현재 활성 키로 폐기 목록에 서명합니다.
+func SignRevocationList(list licensecert.RevocationList) (licensecert.SignedRevocationList, error) {
+	key, err := activeSigningKey()
+	if err != nil {
+		return licensecert.SignedRevocationList{}, err
+	}
+	return licensecert.SignRevocationList(list, key.id, key.privateKey)
+}
+
+func activeSigningKey() (*signingKey, error) {
+	signingKeyMu.Lock()
+	defer signingKeyMu.Unlock()
+
+	if cachedSigningKey != nil && time.Since(signingKeyLoadedAt) < signingKeyCacheTTL {
+		return cachedSigningKey, nil
+	}
+
+	key, err := loadActiveSigningKey()
+	if err == sql.ErrNoRows {
+		// 최초 기동 시 활성 키가 없으면 자동으로 생성합니다.
+		if _, err := createSigningKey(false); err != nil {
+			return nil, err
+		}
+		key, err = loadActiveSigningKey()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cachedSigningKey = key
+	signingKeyLoadedAt = time.Now()
+	return key, nil
+}
+
+func loadActiveSigningKey() (*signingKey, error) {
+	var id, encoded string
+	err := database.DB.QueryRow(
+		"SELECT id, private_key FROM license_signing_keys WHERE status = ? ORDER BY created_at DESC LIMIT 1",
+		models.SigningKeyStatusActive,
+	).Scan(&id, &encoded)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSigningKey(id, encoded)
+}
+
+func decodeSigningKey(id, encoded string) (*signingKey, error) {
+	seed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid signing key material for %s", id)
+	}
+	return &signingKey{id: id, privateKey: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// createSigningKey는 새 키를 저장합니다. retirePrevious가 true이면 기존 활성 키를 retired로 전환합니다.
+// NOTE: 개인키 시드는 DB에 base64로 저장되므로 DB 접근 권한을 엄격히 관리해야 합니다.
+func createSigningKey(retirePrevious bool) (models.LicenseSigningKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return models.LicenseSigningKey{}, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	id, err := GenerateID("kid")
+	if err != nil {
+		return models.LicenseSigningKey{}, err
+	}
+
+	now := FormatDateTimeForDB(NowSeoul())
+	key := models.LicenseSigningKey{
+		ID:        id,
+		Algorithm: licensecert.AlgorithmEd25519,
+		PublicKey: licensecert.EncodePublicKey(publicKey),
+		Status:    models.SigningKeyStatusActive,
+		CreatedAt: now,
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return models.LicenseSigningKey{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if retirePrevious {
+		if _, err = tx.Exec(
+			"UPDATE license_signing_keys SET status = ?, retired_at = ? WHERE status = ?",
+			models.SigningKeyStatusRetired, now, models.SigningKeyStatusActive,
+		); err != nil {
+			return models.LicenseSigningKey{}, err
+		}
+	}
+
+	if _, err = tx.Exec(
+		`INSERT INTO license_signing_keys (id, algorithm, public_key, private_key, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		key.ID, key.Algorithm, key.PublicKey,
+		base64.StdEncoding.EncodeToString(privateKey.Seed()),
+		key.Status, now,
+	); err != nil {
+		return models.LicenseSigningKey{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.LicenseSigningKey{}, err
+	}
+
+	logger.Info("License signing key created: %s", key.ID)
+	return key, nil
+}
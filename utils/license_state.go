@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"studiolicense/models"
+)
+
+var (
+	// ErrInvalidLicenseTransition 상태 전환 규칙에서 허용하지 않는 전환
+	ErrInvalidLicenseTransition = errors.New("invalid license status transition")
+	// ErrLicenseStatusChanged 확인한 이후 다른 요청이 상태를 먼저 바꾼 경우
+	ErrLicenseStatusChanged = errors.New("license status changed concurrently")
+)
+
+// LicenseTransition 라이선스 상태 전환 요청
+type LicenseTransition struct {
+	From string // 현재 상태 (이 상태일 때만 전환)
+	To   string
+	// SuspensionReason, ResumeAt suspended로 전환할 때의 사유 코드와 자동 재개일(YYYY-MM-DD, 선택)
+	SuspensionReason string
+	ResumeAt         string
+	// RevocationReason revoked로 전환할 때의 사유 코드
+	RevocationReason string
+	// ExpiredBefore 지정하면 만료일이 이 날짜 이전인 경우에만 전환합니다 (스케줄러용)
+	ExpiredBefore string
+	// StartedBy 지정하면 시작일(starts_at)이 없거나 이 시각 이전인 경우에만 전환합니다
+	StartedBy string
+}
+
+// TransitionLicenseStatus 상태 전환 규칙을 확인한 뒤 라이선스 상태를 바꿉니다.
+// 현재 상태를 조건으로 갱신하므로 동시에 다른 전환이 먼저 일어나면 ErrLicenseStatusChanged를 반환합니다.
+// suspended를 벗어나는 전환은 일시 정지 정보를 함께 지웁니다.
+func TransitionLicenseStatus(db QueryExecer, licenseID string, t LicenseTransition) error {
+	if !models.CanTransitionLicenseStatus(t.From, t.To) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidLicenseTransition, t.From, t.To)
+	}
+
+	var reason, resumeAt, suspendedAt interface{}
+	now := FormatDateTimeForDB(NowSeoul())
+	if t.To == models.LicenseStatusSuspended {
+		reason, suspendedAt = t.SuspensionReason, now
+		if t.ResumeAt != "" {
+			resumeAt = t.ResumeAt
+		}
+	}
+
+	query := `UPDATE licenses SET status = ?, suspension_reason = ?, suspended_at = ?, resume_at = ?, updated_at = ?`
+	args := []interface{}{t.To, reason, suspendedAt, resumeAt, now}
+	if t.To == models.LicenseStatusRevoked {
+		query += ", revocation_reason = ?, revoked_at = ?"
+		args = append(args, t.RevocationReason, now)
+	}
+	query += " WHERE id = ? AND status = ?"
+	args = append(args, licenseID, t.From)
+	if t.ExpiredBefore != "" {
+		query += " AND expires_at < ?"
+		args = append(args, t.ExpiredBefore)
+	}
+	if t.StartedBy != "" {
+		query += " AND (starts_at IS NULL OR starts_at <= ?)"
+		args = append(args, t.StartedBy)
+	}
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrLicenseStatusChanged
+	}
+	return nil
+}
+
+// CurrentLicenseStatus 전환 전에 현재 상태를 조회합니다. 트랜잭션 안에서는 행을 잠그려면 forUpdate를 지정합니다.
+func CurrentLicenseStatus(db QueryExecer, licenseID string, forUpdate bool) (string, error) {
+	query := "SELECT status FROM licenses WHERE id = ?"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+	var status string
+	err := db.QueryRow(query, licenseID).Scan(&status)
+	return status, err
+}
+
+// StartScheduledLicense 시작일이 된 scheduled 라이선스를 active로 전환하고 이력을 남깁니다.
+// 아직 시작일 전이거나 다른 요청이 먼저 전환했으면 false를 반환합니다.
+func StartScheduledLicense(db QueryExecer, licenseID string) (bool, error) {
+	err := TransitionLicenseStatus(db, licenseID, LicenseTransition{
+		From:      models.LicenseStatusScheduled,
+		To:        models.LicenseStatusActive,
+		StartedBy: FormatDateTimeForDB(NowSeoul()),
+	})
+	if err == ErrLicenseStatusChanged {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, RecordLicenseEvent(db, models.LicenseEvent{
+		LicenseID: licenseID,
+		EventType: models.LicenseEventStarted,
+		Changes: map[string]models.LicenseFieldChange{
+			"status": {Old: models.LicenseStatusScheduled, New: models.LicenseStatusActive},
+		},
+		ActorID:   "system",
+		ActorName: "System",
+	})
+}
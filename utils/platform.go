@@ -0,0 +1,31 @@
+package utils
+
+import "strings"
+
+// NormalizeOS 클라이언트가 보내는 운영체제 이름을 windows, macos, linux로 맞춥니다. 그 밖의 값은 소문자로만 바꿉니다.
+func NormalizeOS(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "win", "win32", "win64", "windows":
+		return "windows"
+	case "mac", "macos", "osx", "darwin":
+		return "macos"
+	case "linux":
+		return "linux"
+	}
+	return value
+}
+
+// NormalizeArch 아키텍처 이름을 x64, x86, arm64로 맞춥니다. 그 밖의 값은 소문자로만 바꿉니다.
+func NormalizeArch(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "x64", "amd64", "x86_64":
+		return "x64"
+	case "x86", "386", "i386", "i686":
+		return "x86"
+	case "arm64", "aarch64":
+		return "arm64"
+	}
+	return value
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"encoding/json"
+
+	"studiolicense/database"
+)
+
+// PolicyDataInt 정책 데이터(JSON)에서 정수 설정값을 읽습니다. 값이 없으면 ok=false를 반환합니다.
+func PolicyDataInt(policyID *string, key string) (int, bool) {
+	return policyDataInt(loadPolicyData(policyID), key)
+}
+
+func loadPolicyData(policyID *string) map[string]interface{} {
+	if policyID == nil || *policyID == "" {
+		return nil
+	}
+
+	var policyData string
+	if err := database.DB.QueryRow("SELECT policy_data FROM policies WHERE id = ?", *policyID).Scan(&policyData); err != nil {
+		return nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(policyData), &data); err != nil {
+		return nil
+	}
+	return data
+}
+
+func policyDataInt(data map[string]interface{}, key string) (int, bool) {
+	number, ok := data[key].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(number), true
+}
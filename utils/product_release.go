@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"sort"
+
+	"studiolicense/database"
+	"studiolicense/models"
+)
+
+// LoadProductReleases 제품의 릴리스를 버전이 낮은 순으로 조회합니다. channel이 비어 있으면 모든 채널을 반환합니다.
+func LoadProductReleases(productID, channel string) ([]models.ProductRelease, error) {
+	query := `SELECT id, product_id, version, DATE_FORMAT(release_date, '%Y-%m-%d'), channel, mandatory,
+		COALESCE(notes, ''), COALESCE(created_by, ''), created_at
+		FROM product_releases WHERE product_id = ?`
+	args := []interface{}{productID}
+	if channel != "" {
+		query += " AND channel = ?"
+		args = append(args, channel)
+	}
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	releases := []models.ProductRelease{}
+	for rows.Next() {
+		var release models.ProductRelease
+		if err := rows.Scan(&release.ID, &release.ProductID, &release.Version, &release.ReleaseDate, &release.Channel, &release.Mandatory,
+			&release.Notes, &release.CreatedBy, &release.CreatedAt); err != nil {
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return CompareVersions(releases[i].Version, releases[j].Version) < 0
+	})
+	return releases, nil
+}
+
+// ResolveReleaseEntitlement 유지보수 종료일까지 출시된 릴리스를 기준으로 appVersion 사용 가능 여부를 판단합니다.
+//   - maintenanceUntil이 nil이면 모든 버전을 사용할 수 있습니다.
+//   - 등록된 릴리스는 출시일이 유지보수 종료일 이전(당일 포함)이어야 합니다.
+//   - 등록되지 않은 버전은 사용할 수 있는 가장 높은 릴리스 이하이면 허용합니다.
+//   - 제품에 릴리스가 하나도 없으면 버전 제한을 하지 않습니다.
+//
+// releases는 LoadProductReleases처럼 버전 오름차순이어야 하며, appVersion이 비어 있으면 최신 버전 정보만 채웁니다.
+func ResolveReleaseEntitlement(releases []models.ProductRelease, maintenanceUntil *string, appVersion string) models.ReleaseEntitlement {
+	result := models.ReleaseEntitlement{
+		AppVersion:       appVersion,
+		Entitled:         true,
+		MaintenanceUntil: maintenanceUntil,
+	}
+
+	var highestCovered *string
+	var matched *models.ProductRelease
+	for i := range releases {
+		release := releases[i]
+		if release.Channel == models.ReleaseChannelStable {
+			version := release.Version
+			result.LatestVersion = &version
+			if ReleaseCovered(release, maintenanceUntil) {
+				result.LatestEntitledVersion = &version
+			}
+		}
+		if ReleaseCovered(release, maintenanceUntil) {
+			version := release.Version
+			highestCovered = &version
+		}
+		if appVersion != "" && CompareVersions(release.Version, appVersion) == 0 {
+			matched = &releases[i]
+		}
+	}
+
+	if appVersion == "" || len(releases) == 0 {
+		return result
+	}
+	if matched != nil {
+		result.Entitled = ReleaseCovered(*matched, maintenanceUntil)
+		return result
+	}
+	result.Entitled = highestCovered != nil && CompareVersions(appVersion, *highestCovered) <= 0
+	return result
+}
+
+// ReleaseCovered 릴리스가 유지보수 종료일(당일 포함) 이전에 출시되었는지 확인합니다. maintenanceUntil이 nil이면 항상 true입니다.
+func ReleaseCovered(release models.ProductRelease, maintenanceUntil *string) bool {
+	return maintenanceUntil == nil || release.ReleaseDate <= *maintenanceUntil
+}
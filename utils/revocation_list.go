@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"studiolicense/database"
+	"studiolicense/licensecert"
+	"studiolicense/models"
+)
+
+// revocationRetentionMargin 클라이언트 시계 오차를 고려해 인증서 만료 후에도 항목을 더 유지하는 시간
+const revocationRetentionMargin = 24 * time.Hour
+
+// RevocationListTTL 폐기 목록의 다음 갱신 주기 (REVOCATION_LIST_TTL_MINUTES, 기본 60분)
+func RevocationListTTL() time.Duration {
+	return time.Duration(GetEnvInt("REVOCATION_LIST_TTL_MINUTES", 60)) * time.Minute
+}
+
+// BuildRevocationList 현재 폐기된 라이선스와 활성화가 해제된 디바이스로 폐기 목록을 만듭니다.
+// 디바이스는 아직 사용 가능한(active, grace, suspended) 라이선스의 것만 포함합니다. 폐기되거나 만료된 라이선스의
+// 인증서는 라이선스 항목이나 인증서 만료로 이미 무효이기 때문입니다.
+// 이전에 발급된 인증서가 모두 만료된 항목(revocationEntryExpired)은 빼서 목록이 계속 커지지 않게 합니다.
+// 목록 내용이 직전 목록과 다를 때만 새 일련번호를 발급하므로, 같은 내용이면 같은 serial이 유지됩니다.
+func BuildRevocationList() (licensecert.RevocationList, error) {
+	list := licensecert.RevocationList{
+		Version:  licensecert.CurrentVersion,
+		Licenses: []licensecert.RevokedLicense{},
+		Devices:  []licensecert.RevokedDevice{},
+	}
+	now := NowSeoul()
+	lifecycles := NewLicenseLifecycleCache()
+
+	rows, err := database.DB.Query(`SELECT id, COALESCE(revocation_reason, ''), COALESCE(revoked_at, updated_at),
+		expires_at, grace_period_days, max_offline_days, policy_id
+		FROM licenses WHERE status = ? ORDER BY id`, models.LicenseStatusRevoked)
+	if err != nil {
+		return list, err
+	}
+	for rows.Next() {
+		var (
+			entry     licensecert.RevokedLicense
+			expiresAt string
+			grace     *int
+			offline   *int
+			policyID  *string
+		)
+		if err := rows.Scan(&entry.LicenseID, &entry.Reason, &entry.RevokedAt, &expiresAt, &grace, &offline, &policyID); err != nil {
+			rows.Close()
+			return list, err
+		}
+		if revocationEntryExpired(lifecycles.Resolve(grace, offline, policyID), expiresAt, entry.RevokedAt, now) {
+			continue
+		}
+		list.Licenses = append(list.Licenses, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return list, err
+	}
+
+	rows, err = database.DB.Query(`SELECT d.id, d.license_id, d.deactivated_at,
+		l.expires_at, l.grace_period_days, l.max_offline_days, l.policy_id
+		FROM device_activations d
+		JOIN licenses l ON l.id = d.license_id
+		WHERE d.status = ? AND d.deactivated_at IS NOT NULL AND l.status IN (?, ?, ?)
+		ORDER BY d.id`,
+		models.DeviceStatusDeactivated, models.LicenseStatusActive, models.LicenseStatusGrace, models.LicenseStatusSuspended)
+	if err != nil {
+		return list, err
+	}
+	for rows.Next() {
+		var (
+			entry     licensecert.RevokedDevice
+			expiresAt string
+			grace     *int
+			offline   *int
+			policyID  *string
+		)
+		if err := rows.Scan(&entry.DeviceID, &entry.LicenseID, &entry.RevokedAt, &expiresAt, &grace, &offline, &policyID); err != nil {
+			rows.Close()
+			return list, err
+		}
+		if revocationEntryExpired(lifecycles.Resolve(grace, offline, policyID), expiresAt, entry.RevokedAt, now) {
+			continue
+		}
+		list.Devices = append(list.Devices, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return list, err
+	}
+
+	content, err := json.Marshal(struct {
+		Licenses []licensecert.RevokedLicense `json:"licenses"`
+		Devices  []licensecert.RevokedDevice  `json:"devices"`
+	}{list.Licenses, list.Devices})
+	if err != nil {
+		return list, err
+	}
+	sum := sha256.Sum256(content)
+	serial, err := revocationListSerial(hex.EncodeToString(sum[:]))
+	if err != nil {
+		return list, err
+	}
+
+	list.Serial = serial
+	list.IssuedAt = now
+	list.NextUpdate = now.Add(RevocationListTTL())
+	return list, nil
+}
+
+// revocationEntryExpired 폐기/해제 이전에 발급된 인증서가 모두 만료되었는지 확인합니다.
+// 폐기·해제 후에는 인증서가 새로 발급되지 않으므로, 라이선스 만료일 + 유예 기간이나
+// 폐기 시각 + max_offline_days가 지나면 목록에 남겨 둘 필요가 없습니다.
+func revocationEntryExpired(lifecycle LicenseLifecycle, expiresAt string, revokedAt, now time.Time) bool {
+	cutoff := now.Add(-revocationRetentionMargin)
+	if graceEndsAt, err := lifecycle.GraceEndsAt(expiresAt); err == nil && graceEndsAt.Before(cutoff) {
+		return true
+	}
+	return lifecycle.MaxOfflineDays > 0 && revokedAt.AddDate(0, 0, lifecycle.MaxOfflineDays).Before(cutoff)
+}
+
+// revocationListSerial 직전 목록과 내용 해시가 같으면 그 일련번호를, 다르면 새 일련번호를 반환합니다.
+// 동시에 목록을 만드는 요청이 같은 내용에 일련번호를 따로 받지 않도록 최신 행을 잠근 트랜잭션에서 비교하고 추가합니다.
+// 내용이 이전 목록으로 돌아가도 일련번호는 항상 증가해야 하므로 해시로 기존 번호를 재사용하지 않습니다.
+func revocationListSerial(contentHash string) (int64, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var (
+		serial     int64
+		latestHash string
+	)
+	err = tx.QueryRow(
+		"SELECT serial, content_hash FROM revocation_list_serials ORDER BY serial DESC LIMIT 1 FOR UPDATE",
+	).Scan(&serial, &latestHash)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	if err == nil && latestHash == contentHash {
+		return serial, tx.Commit()
+	}
+
+	result, err := tx.Exec("INSERT INTO revocation_list_serials (content_hash, created_at) VALUES (?, ?)",
+		contentHash, FormatDateTimeForDB(NowSeoul()))
+	if err != nil {
+		return 0, err
+	}
+	if serial, err = result.LastInsertId(); err != nil {
+		return 0, err
+	}
+	return serial, tx.Commit()
+}
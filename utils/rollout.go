@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"studiolicense/models"
+)
+
+// RolloutBucket 디바이스를 0~99 구간 중 하나에 고정 배정합니다.
+// 같은 디바이스와 salt는 항상 같은 구간이므로 배포 비율을 올려도 이미 받은 디바이스는 계속 대상에 남습니다.
+func RolloutBucket(deviceKey, salt string) int {
+	sum := sha256.Sum256([]byte(salt + ":" + deviceKey))
+	return int(binary.BigEndian.Uint32(sum[:4]) % 100)
+}
+
+// RolloutSalt 같은 버전의 파일(플랫폼별 파일 포함)이 같은 디바이스 집합에 배포되도록 제품과 버전으로 salt를 만듭니다.
+// 버전이 없는 파일은 매핑 ID를 사용합니다.
+func RolloutSalt(productID, version, mappingID string) string {
+	if version == "" {
+		return mappingID
+	}
+	return productID + "@" + version
+}
+
+// InRollout 단계적 배포 상태와 비율에 따라 디바이스가 파일을 받을 수 있는지 확인합니다.
+// active 상태만 배포하며, 100% 이상이면 모든 디바이스, 0% 이하이면 아무 디바이스도 받지 않습니다.
+func InRollout(status string, percentage int, deviceKey, salt string) bool {
+	if status != models.RolloutStatusActive {
+		return false
+	}
+	if percentage >= 100 {
+		return true
+	}
+	if percentage <= 0 {
+		return false
+	}
+	return RolloutBucket(deviceKey, salt) < percentage
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"studiolicense/models"
+)
+
+func TestRolloutBucketStable(t *testing.T) {
+	const salt = "prod-1@2.0.0"
+
+	counts := make([]int, 100)
+	for i := 0; i < 10000; i++ {
+		device := fmt.Sprintf("device-%d", i)
+		bucket := RolloutBucket(device, salt)
+		if bucket < 0 || bucket > 99 {
+			t.Fatalf("RolloutBucket(%q) = %d, want 0~99", device, bucket)
+		}
+		if again := RolloutBucket(device, salt); again != bucket {
+			t.Fatalf("RolloutBucket(%q) changed from %d to %d", device, bucket, again)
+		}
+		counts[bucket]++
+	}
+	// 구간마다 평균 100대가 배정되므로 크게 치우친 구간이 없어야 합니다.
+	for bucket, count := range counts {
+		if count < 50 || count > 150 {
+			t.Errorf("bucket %d has %d devices, distribution is skewed", bucket, count)
+		}
+	}
+
+	// salt가 다르면 다른 버전의 배포 대상이 같은 디바이스로 고정되지 않습니다.
+	differs := 0
+	for i := 0; i < 100; i++ {
+		device := fmt.Sprintf("device-%d", i)
+		if RolloutBucket(device, salt) != RolloutBucket(device, "prod-1@2.1.0") {
+			differs++
+		}
+	}
+	if differs < 50 {
+		t.Errorf("only %d of 100 devices changed bucket with a new salt", differs)
+	}
+}
+
+func TestRolloutSalt(t *testing.T) {
+	if got := RolloutSalt("prod-1", "2.0.0", "map-1"); got != "prod-1@2.0.0" {
+		t.Errorf("RolloutSalt with version = %q", got)
+	}
+	if got := RolloutSalt("prod-1", "", "map-1"); got != "map-1" {
+		t.Errorf("RolloutSalt without version = %q", got)
+	}
+}
+
+func TestInRollout(t *testing.T) {
+	const salt = "prod-1@2.0.0"
+
+	// 비율을 올려도 이미 대상이던 디바이스는 계속 대상에 남습니다.
+	for i := 0; i < 1000; i++ {
+		device := fmt.Sprintf("device-%d", i)
+		included := false
+		for _, percentage := range []int{0, 1, 10, 25, 50, 99, 100} {
+			in := InRollout(models.RolloutStatusActive, percentage, device, salt)
+			if included && !in {
+				t.Fatalf("%q dropped out of the rollout at %d%%", device, percentage)
+			}
+			included = in
+		}
+		if !included {
+			t.Fatalf("%q not included at 100%%", device)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		status     string
+		percentage int
+		want       bool
+	}{
+		{"negative percentage", models.RolloutStatusActive, -5, false},
+		{"over 100", models.RolloutStatusActive, 150, true},
+		{"paused", models.RolloutStatusPaused, 100, false},
+		{"rolled back", models.RolloutStatusRolledBack, 100, false},
+		{"unknown status", "draft", 100, false},
+	}
+	for _, tt := range tests {
+		if got := InRollout(tt.status, tt.percentage, "device-1", salt); got != tt.want {
+			t.Errorf("%s: InRollout = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Semver 시맨틱 버전 (MAJOR.MINOR.PATCH[-PRERELEASE], 빌드 메타데이터는 무시)
+type Semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// ParseSemver 시맨틱 버전 문자열을 파싱합니다. 앞의 "v"는 허용하고 MINOR, PATCH를 생략하면 0으로 봅니다.
+func ParseSemver(value string) (Semver, error) {
+	raw := strings.TrimPrefix(strings.TrimSpace(value), "v")
+	if idx := strings.Index(raw, "+"); idx >= 0 {
+		raw = raw[:idx]
+	}
+
+	var version Semver
+	core := raw
+	if idx := strings.Index(raw, "-"); idx >= 0 {
+		core, version.Prerelease = raw[:idx], raw[idx+1:]
+		if version.Prerelease == "" {
+			return Semver{}, fmt.Errorf("invalid version %q", value)
+		}
+	}
+
+	parts := strings.Split(core, ".")
+	if core == "" || len(parts) > 3 {
+		return Semver{}, fmt.Errorf("invalid version %q", value)
+	}
+	numbers := []*int{&version.Major, &version.Minor, &version.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Semver{}, fmt.Errorf("invalid version %q", value)
+		}
+		*numbers[i] = n
+	}
+	return version, nil
+}
+
+// String 정규화된 버전 문자열 (예: 2.3.0, 3.0.0-beta.1)
+func (v Semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare v가 other보다 낮으면 -1, 같으면 0, 높으면 1을 반환합니다.
+// 사전 출시 버전(-beta.1 등)은 같은 번호의 정식 버전보다 낮습니다.
+func (v Semver) Compare(other Semver) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+
+	left, right := strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		if c := comparePrereleaseIdentifier(left[i], right[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(left) < len(right):
+		return -1
+	case len(left) > len(right):
+		return 1
+	}
+	return 0
+}
+
+// comparePrereleaseIdentifier 숫자 식별자는 숫자로, 나머지는 문자열로 비교하며 숫자 식별자가 더 낮습니다.
+func comparePrereleaseIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		if an < bn {
+			return -1
+		}
+		if an > bn {
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// CompareVersions 두 버전 문자열을 비교합니다. 파싱할 수 없는 버전은 문자열로 비교합니다.
+func CompareVersions(a, b string) int {
+	av, aErr := ParseSemver(a)
+	bv, bErr := ParseSemver(b)
+	if aErr != nil || bErr != nil {
+		return strings.Compare(a, b)
+	}
+	return av.Compare(bv)
+}
@@ -0,0 +1,77 @@
+package utils
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1.2.3", "1.2.3"},
+		{" v2.0.1 ", "2.0.1"},
+		{"3", "3.0.0"},
+		{"3.1", "3.1.0"},
+		{"3.0.0-beta.1", "3.0.0-beta.1"},
+		{"1.2.3+build.7", "1.2.3"},
+		{"1.2.3-rc.1+build.7", "1.2.3-rc.1"},
+	}
+	for _, tt := range tests {
+		got, err := ParseSemver(tt.input)
+		if err != nil {
+			t.Errorf("ParseSemver(%q): %v", tt.input, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseSemver(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "v", "1.2.3.4", "1.x.3", "1.-2.3", "1.2.3-", "1..3"} {
+		if _, err := ParseSemver(input); err == nil {
+			t.Errorf("ParseSemver(%q) succeeded", input)
+		}
+	}
+}
+
+func TestSemverPrecedence(t *testing.T) {
+	// semver.org 11절의 예시 순서
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"1.10.0",
+		"2.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := CompareVersions(ordered[i], ordered[j]); got != want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	// "v" 접두사, 생략된 번호, 빌드 메타데이터는 순서에 영향을 주지 않습니다.
+	for _, pair := range [][2]string{{"v1.2.0", "1.2"}, {"2", "2.0.0"}, {"1.0.0+a", "1.0.0+b"}} {
+		if got := CompareVersions(pair[0], pair[1]); got != 0 {
+			t.Errorf("CompareVersions(%q, %q) = %d, want 0", pair[0], pair[1], got)
+		}
+	}
+
+	// 파싱할 수 없는 버전은 문자열로 비교합니다.
+	if got := CompareVersions("build-a", "build-b"); got != -1 {
+		t.Errorf("CompareVersions(build-a, build-b) = %d, want -1", got)
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"encoding/json"
+	"time"
+
+	"studiolicense/models"
+)
+
+// ResolveUsageQuotas 정책 데이터의 usage_quotas 위에 라이선스의 usage_quotas를 덮어써 측정 항목별 한도를 결정합니다.
+// 라이선스에서 한도를 음수로 지정한 항목은 한도 없이 집계만 합니다.
+func ResolveUsageQuotas(licenseQuotas *string, policyID *string) map[string]models.UsageQuota {
+	quotas := map[string]models.UsageQuota{}
+
+	if raw, ok := loadPolicyData(policyID)["usage_quotas"]; ok {
+		if encoded, err := json.Marshal(raw); err == nil {
+			mergeUsageQuotas(quotas, encoded)
+		}
+	}
+	if licenseQuotas != nil && *licenseQuotas != "" {
+		mergeUsageQuotas(quotas, []byte(*licenseQuotas))
+	}
+
+	for metric, quota := range quotas {
+		if quota.Limit < 0 {
+			delete(quotas, metric)
+		}
+	}
+	return quotas
+}
+
+func mergeUsageQuotas(dst map[string]models.UsageQuota, raw []byte) {
+	var parsed map[string]models.UsageQuota
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return
+	}
+	for metric, quota := range parsed {
+		if !models.IsValidUsagePeriod(quota.Period) {
+			quota.Period = models.UsagePeriodMonthly
+		}
+		dst[metric] = quota
+	}
+}
+
+// UsagePeriodKey 집계 주기에 해당하는 기간 키를 반환합니다. (예: 2025-01, 2025-01-31, all)
+func UsagePeriodKey(period string, now time.Time) string {
+	now = now.In(SeoulLocation())
+	switch period {
+	case models.UsagePeriodDaily:
+		return now.Format(dateOnlyLayout)
+	case models.UsagePeriodLifetime:
+		return "all"
+	default:
+		return now.Format("2006-01")
+	}
+}
+
+// UsagePeriodResetsAt 다음 집계 기간이 시작되는 시각을 반환합니다. lifetime이면 ok=false입니다.
+func UsagePeriodResetsAt(period string, now time.Time) (time.Time, bool) {
+	day := StartOfDay(now.In(SeoulLocation()))
+	switch period {
+	case models.UsagePeriodDaily:
+		return day.AddDate(0, 0, 1), true
+	case models.UsagePeriodLifetime:
+		return time.Time{}, false
+	default:
+		return day.AddDate(0, 1, 1-day.Day()), true
+	}
+}
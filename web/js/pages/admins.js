@@ -26,6 +26,12 @@ const RESOURCE_TYPES = [
     placeholder: '제품명 검색',
     summaryLabel: '제품',
   },
+  {
+    key: 'customers',
+    label: '고객',
+    placeholder: '고객명, 회사, 이메일 검색',
+    summaryLabel: '고객',
+  },
 ];
 const resourceTypeIndex = new Map(RESOURCE_TYPES.map((type) => [type.key, type]));
 const resourceCatalogCache = new Map(); // resourceType -> { items, loaded, loading, error }
@@ -366,6 +372,25 @@ async function fetchResourceItems(type) {
       }
       throw new Error(body?.message || '제품을 불러오지 못했습니다.');
     }
+    case 'customers': {
+      const res = await apiFetch(`${API_BASE_URL}/api/admin/customers`, {
+        headers: { Authorization: `Bearer ${state.token}` },
+        _noGlobalLoading: true,
+      });
+      const body = await res.json();
+      if (res.status === 403) {
+        throw new Error(`${config?.label || type} 목록에 접근할 권한이 없습니다. 고객 조회 기능 권한을 부여하세요.`);
+      }
+      if (res.ok && body.status === 'success') {
+        const items = Array.isArray(body.data) ? body.data : [];
+        return items.map((customer) => ({
+          id: customer.id,
+          name: customer.organization ? `${customer.organization} (${customer.name})` : customer.name,
+          description: customer.email || '-',
+        }));
+      }
+      throw new Error(body?.message || '고객을 불러오지 못했습니다.');
+    }
     default:
       return [];
   }
@@ -16,6 +16,11 @@ export async function loadDashboardStats() {
       document.getElementById('active-licenses').textContent = stats.active_licenses || 0;
       document.getElementById('expired-licenses').textContent = stats.expired_licenses || 0;
       document.getElementById('total-devices').textContent = stats.total_active_devices || 0;
+      const conversions = document.getElementById('trial-conversions');
+      if (conversions) {
+        const rate = Math.round((stats.trial_conversion_rate || 0) * 100);
+        conversions.textContent = `${stats.trial_conversions || 0} (${rate}%)`;
+      }
     }
   } catch (error) {
     console.error('Failed to load stats:', error);